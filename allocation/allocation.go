@@ -0,0 +1,279 @@
+// Package allocation breaks the current positions down by instrument type,
+// instrument currency, exchange and pie membership, to show concentration
+// and currency exposure. Values are in account currency.
+package allocation
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+)
+
+// Dimension represents what positions are grouped by
+type Dimension string
+
+const (
+	ByType     Dimension = "TYPE"
+	ByCurrency Dimension = "CURRENCY"
+	ByExchange Dimension = "EXCHANGE"
+	// ByPie splits each position into the shares held in pies and the
+	// shares held directly
+	ByPie Dimension = "PIE"
+)
+
+// Dimensions lists every dimension
+var Dimensions = []Dimension{ByType, ByCurrency, ByExchange, ByPie}
+
+// ParseDimension returns the dimension with the given name, ignoring case
+func ParseDimension(s string) (Dimension, error) {
+	for _, d := range Dimensions {
+		if strings.EqualFold(s, string(d)) {
+			return d, nil
+		}
+	}
+	return "", fmt.Errorf("unknown dimension %q, expected type, currency, exchange or pie", s)
+}
+
+// Group keys used when a position cannot be classified, and for ByPie
+const (
+	Unknown = "UNKNOWN"
+	InPies  = "PIES"
+	Direct  = "DIRECT"
+)
+
+// Options represents options for Analyze
+type Options struct {
+	// Currency is the account currency
+	Currency string
+	// Rates converts instrument currencies to account currency: a value in
+	// that currency times its rate is the value in account currency. The
+	// account currency is 1, and GBX is 0.01 in a GBP account, unless set.
+	Rates map[string]float64
+}
+
+// Holding represents a position with its instrument details
+type Holding struct {
+	Ticker   string                    `json:"ticker"`
+	Name     string                    `json:"name"`
+	Type     trading212.InstrumentType `json:"type"`
+	Currency string                    `json:"currency"`
+	Exchange string                    `json:"exchange"`
+	Quantity float64                   `json:"quantity"`
+	// PieQuantity is the part of Quantity held in pies
+	PieQuantity float64 `json:"pieQuantity"`
+	// Value is the market value in account currency
+	Value float64 `json:"value"`
+	// Weight is Value as a fraction of the total
+	Weight float64 `json:"weight"`
+	// Ppl is the unrealized P&L in account currency, FxImpact the part of
+	// it caused by exchange rate moves
+	Ppl      float64 `json:"ppl"`
+	FxImpact float64 `json:"fxImpact"`
+}
+
+// Group represents the positions sharing a key
+type Group struct {
+	Key       string  `json:"key"`
+	Positions int     `json:"positions"`
+	Value     float64 `json:"value"`
+	Weight    float64 `json:"weight"`
+	Ppl       float64 `json:"ppl"`
+	FxImpact  float64 `json:"fxImpact"`
+}
+
+// Report represents the allocation of the positions
+type Report struct {
+	Currency string  `json:"currency"`
+	Total    float64 `json:"total"`
+	// Holdings are ordered by value, largest first
+	Holdings []Holding `json:"holdings"`
+	// Groups holds the groups of each dimension, largest first
+	Groups map[Dimension][]Group `json:"groups"`
+	// Unpriced lists the positions left out because there is no rate for
+	// their currency
+	Unpriced []string `json:"unpriced,omitempty"`
+}
+
+// Analyze classifies positions using the instrument catalog and the
+// exchanges, whose working schedules are matched with each instrument's
+// WorkingScheduleID. Instruments missing from the catalog are grouped
+// under Unknown and assumed to trade in the account currency; a nil catalog
+// is treated as empty.
+func Analyze(positions []trading212.Position, catalog *trading212.InstrumentCatalog, exchanges []trading212.Exchange, opts *Options) *Report {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	report := &Report{Currency: o.Currency, Groups: make(map[Dimension][]Group)}
+
+	schedules := make(map[int64]string)
+	for _, exchange := range exchanges {
+		for _, schedule := range exchange.WorkingSchedules {
+			schedules[schedule.ID] = exchange.Name
+		}
+	}
+
+	for _, p := range positions {
+		h := Holding{
+			Ticker:      p.Ticker,
+			Type:        Unknown,
+			Currency:    o.Currency,
+			Exchange:    Unknown,
+			Quantity:    p.Quantity,
+			PieQuantity: p.PieQuantity,
+			Ppl:         p.Ppl,
+			FxImpact:    p.FxPpl,
+		}
+		if instrument, ok := lookup(catalog, p.Ticker); ok {
+			h.Name = instrument.Name
+			h.Type = instrument.Type
+			h.Currency = instrument.CurrencyCode
+			if name, ok := schedules[instrument.WorkingScheduleID]; ok {
+				h.Exchange = name
+			}
+		}
+
+		rate, ok := o.rate(h.Currency)
+		if !ok {
+			report.Unpriced = append(report.Unpriced, p.Ticker)
+			continue
+		}
+		h.Value = p.Quantity * p.CurrentPrice * rate
+		report.Total += h.Value
+		report.Holdings = append(report.Holdings, h)
+	}
+
+	for i := range report.Holdings {
+		report.Holdings[i].Weight = weight(report.Holdings[i].Value, report.Total)
+	}
+	sort.SliceStable(report.Holdings, func(i, j int) bool {
+		return report.Holdings[i].Value > report.Holdings[j].Value
+	})
+	for _, d := range Dimensions {
+		report.Groups[d] = report.group(d)
+	}
+	return report
+}
+
+// group totals the holdings by one dimension
+func (r *Report) group(d Dimension) []Group {
+	var groups []Group
+	index := make(map[string]int)
+	add := func(key string, h Holding, share float64) {
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, Group{Key: key})
+		}
+		g := &groups[i]
+		g.Positions++
+		g.Value += h.Value * share
+		g.Ppl += h.Ppl * share
+		g.FxImpact += h.FxImpact * share
+	}
+
+	for _, h := range r.Holdings {
+		switch d {
+		case ByType:
+			add(string(h.Type), h, 1)
+		case ByCurrency:
+			add(h.Currency, h, 1)
+		case ByExchange:
+			add(h.Exchange, h, 1)
+		case ByPie:
+			inPies := 0.0
+			if h.Quantity > 0 {
+				inPies = h.PieQuantity / h.Quantity
+			}
+			if inPies > 0 {
+				add(InPies, h, inPies)
+			}
+			if inPies < 1 {
+				add(Direct, h, 1-inPies)
+			}
+		}
+	}
+
+	for i := range groups {
+		groups[i].Weight = weight(groups[i].Value, r.Total)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Value > groups[j].Value
+	})
+	return groups
+}
+
+// rate returns the rate from currency to account currency
+func (o Options) rate(currency string) (float64, bool) {
+	if rate, ok := o.Rates[currency]; ok {
+		return rate, true
+	}
+	switch {
+	case currency == o.Currency:
+		return 1, true
+	case currency == "GBX" && o.Currency == "GBP":
+		return 0.01, true
+	}
+	return 0, false
+}
+
+// RatesFromFills returns the FX rate of the latest fill in each instrument
+// currency, for use as Options.Rates; it is empty when catalog is nil
+func RatesFromFills(orders []trading212.HistoricalOrder, catalog *trading212.InstrumentCatalog) map[string]float64 {
+	rates := make(map[string]float64)
+	latest := make(map[string]trading212.Fill)
+	for _, order := range orders {
+		f := order.Fill
+		if f.WalletImpact.FxRate == 0 {
+			continue
+		}
+		instrument, ok := lookup(catalog, order.Order.Ticker)
+		if !ok {
+			continue
+		}
+		if last, ok := latest[instrument.CurrencyCode]; !ok || f.FilledAt.After(last.FilledAt) {
+			latest[instrument.CurrencyCode] = f
+			rates[instrument.CurrencyCode] = f.WalletImpact.FxRate
+		}
+	}
+	return rates
+}
+
+// lookup is catalog.ByTicker allowing a nil catalog
+func lookup(catalog *trading212.InstrumentCatalog, ticker string) (trading212.TradableInstrument, bool) {
+	if catalog == nil {
+		return trading212.TradableInstrument{}, false
+	}
+	return catalog.ByTicker(ticker)
+}
+
+// ParseRates parses rates written as USD=0.79,EUR=0.85
+func ParseRates(s string) (map[string]float64, error) {
+	rates := make(map[string]float64)
+	if s == "" {
+		return rates, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		currency, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rate %q, expected CURRENCY=RATE", pair)
+		}
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("invalid rate %q, expected a positive number", pair)
+		}
+		rates[strings.ToUpper(strings.TrimSpace(currency))] = rate
+	}
+	return rates, nil
+}
+
+func weight(value, total float64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return value / total
+}
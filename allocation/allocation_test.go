@@ -0,0 +1,103 @@
+package allocation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+)
+
+var (
+	catalog = trading212.NewInstrumentCatalogFrom([]trading212.TradableInstrument{
+		{Ticker: "AAPL_US_EQ", Name: "Apple", Type: trading212.InstrumentTypeStock, CurrencyCode: "USD", WorkingScheduleID: 1},
+		{Ticker: "VUSAl_EQ", Name: "Vanguard S&P 500", Type: trading212.InstrumentTypeETF, CurrencyCode: "GBX", WorkingScheduleID: 2},
+		{Ticker: "SAPd_EQ", Name: "SAP", Type: trading212.InstrumentTypeStock, CurrencyCode: "EUR", WorkingScheduleID: 3},
+	})
+	exchanges = []trading212.Exchange{
+		{Name: "NASDAQ", WorkingSchedules: []trading212.WorkingSchedule{{ID: 1}}},
+		{Name: "London Stock Exchange", WorkingSchedules: []trading212.WorkingSchedule{{ID: 2}}},
+	}
+)
+
+func TestAnalyze(t *testing.T) {
+	positions := []trading212.Position{
+		{Ticker: "AAPL_US_EQ", Quantity: 4, PieQuantity: 1, CurrentPrice: 200, Ppl: 40, FxPpl: -8},
+		{Ticker: "VUSAl_EQ", Quantity: 10, CurrentPrice: 8000, Ppl: 60},
+		{Ticker: "SAPd_EQ", Quantity: 1, CurrentPrice: 100},
+		{Ticker: "OLD_EQ", Quantity: 2, CurrentPrice: 50},
+	}
+	report := Analyze(positions, catalog, exchanges, &Options{Currency: "GBP", Rates: map[string]float64{"USD": 0.75}})
+
+	assert.Equal(t, []string{"SAPd_EQ"}, report.Unpriced)
+	assert.InDelta(t, 1500, report.Total, 1e-9)
+	require.Len(t, report.Holdings, 3)
+	assert.Equal(t, "VUSAl_EQ", report.Holdings[0].Ticker)
+	assert.InDelta(t, 800, report.Holdings[0].Value, 1e-9)
+	assert.Equal(t, "London Stock Exchange", report.Holdings[0].Exchange)
+	assert.Equal(t, Unknown, report.Holdings[2].Exchange)
+	assert.Equal(t, trading212.InstrumentType(Unknown), report.Holdings[2].Type)
+
+	currencies := report.Groups[ByCurrency]
+	require.Len(t, currencies, 3)
+	assert.Equal(t, "GBX", currencies[0].Key)
+	assert.Equal(t, "USD", currencies[1].Key)
+	assert.InDelta(t, 0.4, currencies[1].Weight, 1e-9)
+	assert.InDelta(t, -8, currencies[1].FxImpact, 1e-9)
+
+	exchanges := report.Groups[ByExchange]
+	assert.Equal(t, "London Stock Exchange", exchanges[0].Key)
+	assert.Equal(t, Unknown, exchanges[2].Key)
+
+	types := report.Groups[ByType]
+	require.Len(t, types, 3)
+	assert.Equal(t, string(trading212.InstrumentTypeETF), types[0].Key)
+
+	pies := report.Groups[ByPie]
+	require.Len(t, pies, 2)
+	assert.Equal(t, Direct, pies[0].Key)
+	assert.Equal(t, 3, pies[0].Positions)
+	assert.InDelta(t, 1350, pies[0].Value, 1e-9)
+	assert.Equal(t, InPies, pies[1].Key)
+	assert.InDelta(t, 150, pies[1].Value, 1e-9)
+	assert.InDelta(t, 10, pies[1].Ppl, 1e-9)
+}
+
+func TestAnalyze_NilCatalog(t *testing.T) {
+	positions := []trading212.Position{{Ticker: "AAPL_US_EQ", Quantity: 2, CurrentPrice: 50}}
+	report := Analyze(positions, nil, nil, &Options{Currency: "GBP"})
+
+	require.Len(t, report.Holdings, 1)
+	assert.Equal(t, trading212.InstrumentType(Unknown), report.Holdings[0].Type)
+	assert.Equal(t, "GBP", report.Holdings[0].Currency)
+	assert.InDelta(t, 100, report.Total, 1e-9)
+
+	orders := []trading212.HistoricalOrder{{Order: trading212.Order{Ticker: "AAPL_US_EQ"}, Fill: trading212.Fill{WalletImpact: trading212.FillWalletImpact{FxRate: 0.79}}}}
+	assert.Empty(t, RatesFromFills(orders, nil))
+}
+
+func TestRates(t *testing.T) {
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	orders := []trading212.HistoricalOrder{
+		{Order: trading212.Order{Ticker: "AAPL_US_EQ"}, Fill: trading212.Fill{FilledAt: day.AddDate(0, 0, 1), WalletImpact: trading212.FillWalletImpact{FxRate: 0.79}}},
+		{Order: trading212.Order{Ticker: "AAPL_US_EQ"}, Fill: trading212.Fill{FilledAt: day, WalletImpact: trading212.FillWalletImpact{FxRate: 0.8}}},
+		{Order: trading212.Order{Ticker: "VUSAl_EQ"}, Fill: trading212.Fill{FilledAt: day}},
+	}
+	assert.Equal(t, map[string]float64{"USD": 0.79}, RatesFromFills(orders, catalog))
+
+	rates, err := ParseRates("usd=0.79, EUR=0.85")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"USD": 0.79, "EUR": 0.85}, rates)
+	_, err = ParseRates("USD")
+	assert.Error(t, err)
+	_, err = ParseRates("USD=-1")
+	assert.Error(t, err)
+
+	d, err := ParseDimension("currency")
+	require.NoError(t, err)
+	assert.Equal(t, ByCurrency, d)
+	_, err = ParseDimension("sector")
+	assert.Error(t, err)
+}
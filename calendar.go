@@ -0,0 +1,209 @@
+package trading212
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// SessionType represents the trading session an exchange is in
+type SessionType string
+
+const (
+	SessionClosed     SessionType = "CLOSED"
+	SessionPreMarket  SessionType = "PRE_MARKET"
+	SessionRegular    SessionType = "REGULAR"
+	SessionAfterHours SessionType = "AFTER_HOURS"
+	SessionOvernight  SessionType = "OVERNIGHT"
+	SessionBreak      SessionType = "BREAK"
+)
+
+var (
+	// ErrUnknownTicker is returned when the calendar has no instrument for a ticker
+	ErrUnknownTicker = errors.New("unknown ticker")
+	// ErrOutsideSchedule is returned when a time is not covered by the
+	// working schedule returned by the API
+	ErrOutsideSchedule = errors.New("time outside known working schedule")
+)
+
+// MarketCalendar answers market hours questions using the working schedules
+// returned by GetExchanges and the schedule IDs of GetInstruments
+type MarketCalendar struct {
+	schedules   map[int64][]TimeEvent
+	instruments map[string]int64
+}
+
+// NewMarketCalendar builds a calendar from exchange and instrument metadata
+func NewMarketCalendar(exchanges []Exchange, instruments []TradableInstrument) *MarketCalendar {
+	cal := &MarketCalendar{
+		schedules:   make(map[int64][]TimeEvent),
+		instruments: make(map[string]int64, len(instruments)),
+	}
+
+	for _, exchange := range exchanges {
+		for _, schedule := range exchange.WorkingSchedules {
+			events := append([]TimeEvent(nil), schedule.TimeEvents...)
+			sort.SliceStable(events, func(i, j int) bool {
+				if events[i].Date.Equal(events[j].Date) {
+					return sessionAfter(events[i].Type) == SessionClosed && sessionAfter(events[j].Type) != SessionClosed
+				}
+				return events[i].Date.Before(events[j].Date)
+			})
+			cal.schedules[schedule.ID] = events
+		}
+	}
+	for _, instrument := range instruments {
+		cal.instruments[instrument.Ticker] = instrument.WorkingScheduleID
+	}
+
+	return cal
+}
+
+// GetMarketCalendar fetches exchanges and instruments and builds a calendar
+func (c *Client) GetMarketCalendar(ctx context.Context) (*MarketCalendar, error) {
+	exchanges, err := c.GetExchanges(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	instruments, err := c.GetInstruments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMarketCalendar(exchanges, instruments), nil
+}
+
+// Session returns the session the ticker's exchange is in at t
+func (m *MarketCalendar) Session(ticker string, t time.Time) (SessionType, error) {
+	events, err := m.events(ticker)
+	if err != nil {
+		return SessionClosed, err
+	}
+
+	i := sort.Search(len(events), func(i int) bool {
+		return events[i].Date.After(t)
+	})
+	if i == 0 {
+		return SessionClosed, fmt.Errorf("%w: %s at %s", ErrOutsideSchedule, ticker, t.Format(time.RFC3339))
+	}
+
+	return sessionAfter(events[i-1].Type), nil
+}
+
+// IsOpen reports whether the ticker's regular session is open at t
+func (m *MarketCalendar) IsOpen(ticker string, t time.Time) (bool, error) {
+	session, err := m.Session(ticker, t)
+	if err != nil {
+		return false, err
+	}
+	return session == SessionRegular, nil
+}
+
+// IsTradable reports whether the ticker can be traded at t, including
+// pre-market, after-hours and overnight sessions when extendedHours is set
+func (m *MarketCalendar) IsTradable(ticker string, t time.Time, extendedHours bool) (bool, error) {
+	session, err := m.Session(ticker, t)
+	if err != nil {
+		return false, err
+	}
+	switch session {
+	case SessionRegular:
+		return true, nil
+	case SessionPreMarket, SessionAfterHours, SessionOvernight:
+		return extendedHours, nil
+	}
+	return false, nil
+}
+
+// NextOpen returns the next start of the regular session after t
+func (m *MarketCalendar) NextOpen(ticker string, t time.Time) (time.Time, error) {
+	return m.next(ticker, t, TimeEventTypeOpen, TimeEventTypeBreakEnd)
+}
+
+// NextClose returns the next end of the regular session after t
+func (m *MarketCalendar) NextClose(ticker string, t time.Time) (time.Time, error) {
+	return m.next(ticker, t, TimeEventTypeClose, TimeEventTypeBreakStart)
+}
+
+// WaitUntilOpen blocks until the ticker's regular session is open or ctx is done
+func (m *MarketCalendar) WaitUntilOpen(ctx context.Context, ticker string) error {
+	for {
+		now := time.Now()
+		open, err := m.IsOpen(ticker, now)
+		if err != nil && !errors.Is(err, ErrOutsideSchedule) {
+			return err
+		}
+		if open {
+			return nil
+		}
+
+		next, err := m.NextOpen(ticker, now)
+		if err != nil {
+			return err
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// next returns the first event of one of the given types strictly after t
+func (m *MarketCalendar) next(ticker string, t time.Time, types ...TimeEventType) (time.Time, error) {
+	events, err := m.events(ticker)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	i := sort.Search(len(events), func(i int) bool {
+		return events[i].Date.After(t)
+	})
+	for ; i < len(events); i++ {
+		for _, eventType := range types {
+			if events[i].Type == eventType {
+				return events[i].Date, nil
+			}
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%w: no %s for %s after %s", ErrOutsideSchedule, types[0], ticker, t.Format(time.RFC3339))
+}
+
+// events returns the sorted time events of the ticker's working schedule
+func (m *MarketCalendar) events(ticker string) ([]TimeEvent, error) {
+	scheduleID, ok := m.instruments[ticker]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTicker, ticker)
+	}
+
+	events, ok := m.schedules[scheduleID]
+	if !ok || len(events) == 0 {
+		return nil, fmt.Errorf("%w: no working schedule %d for %s", ErrOutsideSchedule, scheduleID, ticker)
+	}
+
+	return events, nil
+}
+
+// sessionAfter returns the session that starts with an event of the given type
+func sessionAfter(eventType TimeEventType) SessionType {
+	switch eventType {
+	case TimeEventTypeOpen, TimeEventTypeBreakEnd:
+		return SessionRegular
+	case TimeEventTypeBreakStart:
+		return SessionBreak
+	case TimeEventTypePreMarketOpen:
+		return SessionPreMarket
+	case TimeEventTypeAfterHoursOpen:
+		return SessionAfterHours
+	case TimeEventTypeOvernightOpen:
+		return SessionOvernight
+	}
+	return SessionClosed
+}
@@ -0,0 +1,121 @@
+package trading212
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testCalendar() (*MarketCalendar, time.Time) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	at := func(h, m int) time.Time { return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute) }
+
+	exchanges := []Exchange{{
+		ID:   1,
+		Name: "NASDAQ",
+		WorkingSchedules: []WorkingSchedule{{
+			ID: 10,
+			TimeEvents: []TimeEvent{
+				{Date: at(14, 30), Type: TimeEventTypeOpen},
+				{Date: at(9, 0), Type: TimeEventTypePreMarketOpen},
+				{Date: at(21, 0), Type: TimeEventTypeAfterHoursOpen},
+				{Date: at(21, 0), Type: TimeEventTypeClose},
+				{Date: at(23, 59), Type: TimeEventTypeAfterHoursClose},
+				{Date: at(24+14, 30), Type: TimeEventTypeOpen},
+				{Date: at(24+21, 0), Type: TimeEventTypeClose},
+			},
+		}},
+	}, {
+		ID:   2,
+		Name: "XETRA",
+		WorkingSchedules: []WorkingSchedule{{
+			ID: 20,
+			TimeEvents: []TimeEvent{
+				{Date: at(8, 0), Type: TimeEventTypeOpen},
+				{Date: at(12, 0), Type: TimeEventTypeBreakStart},
+				{Date: at(12, 30), Type: TimeEventTypeBreakEnd},
+				{Date: at(16, 30), Type: TimeEventTypeClose},
+			},
+		}},
+	}}
+	instruments := []TradableInstrument{
+		{Ticker: "AAPL_US_EQ", WorkingScheduleID: 10},
+		{Ticker: "SAPd_EQ", WorkingScheduleID: 20},
+	}
+
+	return NewMarketCalendar(exchanges, instruments), day
+}
+
+func TestMarketCalendar_Session(t *testing.T) {
+	cal, day := testCalendar()
+
+	tests := []struct {
+		ticker   string
+		offset   time.Duration
+		expected SessionType
+	}{
+		{"AAPL_US_EQ", 10 * time.Hour, SessionPreMarket},
+		{"AAPL_US_EQ", 15 * time.Hour, SessionRegular},
+		{"AAPL_US_EQ", 21 * time.Hour, SessionAfterHours},
+		{"AAPL_US_EQ", 24 * time.Hour, SessionClosed},
+		{"SAPd_EQ", 12*time.Hour + 15*time.Minute, SessionBreak},
+		{"SAPd_EQ", 13 * time.Hour, SessionRegular},
+		{"SAPd_EQ", 17 * time.Hour, SessionClosed},
+	}
+	for _, tt := range tests {
+		session, err := cal.Session(tt.ticker, day.Add(tt.offset))
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, session, "%s at %s", tt.ticker, tt.offset)
+	}
+
+	_, err := cal.Session("AAPL_US_EQ", day)
+	assert.ErrorIs(t, err, ErrOutsideSchedule)
+
+	_, err = cal.Session("MSFT_US_EQ", day)
+	assert.ErrorIs(t, err, ErrUnknownTicker)
+}
+
+func TestMarketCalendar_OpenAndClose(t *testing.T) {
+	cal, day := testCalendar()
+
+	open, err := cal.IsOpen("AAPL_US_EQ", day.Add(22*time.Hour))
+	require.NoError(t, err)
+	assert.False(t, open)
+
+	tradable, err := cal.IsTradable("AAPL_US_EQ", day.Add(22*time.Hour), true)
+	require.NoError(t, err)
+	assert.True(t, tradable)
+
+	next, err := cal.NextOpen("AAPL_US_EQ", day.Add(22*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, day.Add(24*time.Hour+14*time.Hour+30*time.Minute), next)
+
+	closing, err := cal.NextClose("SAPd_EQ", day.Add(9*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, day.Add(12*time.Hour), closing)
+
+	_, err = cal.NextOpen("AAPL_US_EQ", day.Add(48*time.Hour))
+	assert.ErrorIs(t, err, ErrOutsideSchedule)
+}
+
+func TestMarketCalendar_WaitUntilOpen(t *testing.T) {
+	now := time.Now()
+	cal := NewMarketCalendar([]Exchange{{
+		WorkingSchedules: []WorkingSchedule{{
+			ID: 1,
+			TimeEvents: []TimeEvent{
+				{Date: now.Add(-time.Hour), Type: TimeEventTypeClose},
+				{Date: now.Add(20 * time.Millisecond), Type: TimeEventTypeOpen},
+				{Date: now.Add(time.Hour), Type: TimeEventTypeClose},
+			},
+		}},
+	}}, []TradableInstrument{{Ticker: "AAPL_US_EQ", WorkingScheduleID: 1}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, cal.WaitUntilOpen(ctx, "AAPL_US_EQ"))
+	assert.False(t, time.Now().Before(now.Add(20*time.Millisecond)))
+}
@@ -0,0 +1,475 @@
+// Package cassette records HTTP interactions of a Client to a file and
+// replays them later, so tests can run against real API responses without
+// network access or credentials.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Mode represents what a Recorder does with requests
+type Mode string
+
+const (
+	// ModeRecord sends requests and stores the interactions
+	ModeRecord Mode = "record"
+	// ModeReplay answers requests from the cassette without network access
+	ModeReplay Mode = "replay"
+	// ModePassthrough sends requests without recording anything
+	ModePassthrough Mode = "passthrough"
+)
+
+// Redacted replaces secrets in recorded interactions
+const Redacted = "REDACTED"
+
+// RedactedAccountID replaces account IDs in recorded interactions
+const RedactedAccountID = "10000000"
+
+// ErrNoInteraction is returned in replay mode when no recorded interaction
+// matches a request
+var ErrNoInteraction = errors.New("no recorded interaction matches request")
+
+// ParseMode parses "record", "replay" or "passthrough"; an empty string
+// selects replay so CI never hits the network by accident
+func ParseMode(s string) (Mode, error) {
+	switch mode := Mode(strings.ToLower(strings.TrimSpace(s))); mode {
+	case "":
+		return ModeReplay, nil
+	case ModeRecord, ModeReplay, ModePassthrough:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown cassette mode %q", s)
+}
+
+// Options represents options for a Recorder
+type Options struct {
+	// Mode selects record, replay or passthrough (default replay)
+	Mode Mode
+	// Transport sends requests in record and passthrough mode
+	// (default http.DefaultTransport)
+	Transport http.RoundTripper
+	// RedactHeaders lists headers to redact in addition to Authorization,
+	// Cookie and Set-Cookie
+	RedactHeaders []string
+	// Secrets lists literal values, such as account IDs known in advance,
+	// to replace with Redacted wherever they appear
+	Secrets []string
+}
+
+// Cassette represents the stored interactions
+type Cassette struct {
+	Version      int           `json:"version"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction represents a request and the response it received
+type Interaction struct {
+	Request    Request   `json:"request"`
+	Response   Response  `json:"response"`
+	RecordedAt time.Time `json:"recordedAt"`
+}
+
+// Request represents a recorded request
+type Request struct {
+	Method  string      `json:"method"`
+	Path    string      `json:"path"`
+	Query   string      `json:"query,omitempty"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    Body        `json:"body,omitempty"`
+}
+
+// Response represents a recorded response
+type Response struct {
+	Status  int         `json:"status"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    Body        `json:"body,omitempty"`
+}
+
+// Body holds a recorded body. JSON bodies are stored as JSON so cassettes
+// stay readable and diff well; anything else is stored as a string.
+type Body []byte
+
+// MarshalJSON stores JSON bodies verbatim and other bodies as strings
+func (b Body) MarshalJSON() ([]byte, error) {
+	if len(b) == 0 {
+		return []byte(`""`), nil
+	}
+	if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid(b) {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, b); err == nil {
+			return compact.Bytes(), nil
+		}
+	}
+	return json.Marshal(string(b))
+}
+
+// UnmarshalJSON reverses MarshalJSON
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*b = Body(s)
+		return nil
+	}
+	*b = append(Body(nil), data...)
+	return nil
+}
+
+// Recorder is an http.RoundTripper that records or replays interactions
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+	redact    map[string]bool
+	secrets   []string
+	now       func() time.Time
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// New creates a Recorder for the cassette at path. In replay mode the file
+// must exist; in record mode it is written by Save.
+func New(path string, opts *Options) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		mode:      ModeReplay,
+		transport: http.DefaultTransport,
+		redact:    map[string]bool{"Authorization": true, "Cookie": true, "Set-Cookie": true},
+		now:       time.Now,
+		cassette:  Cassette{Version: 1},
+	}
+	if opts != nil {
+		if opts.Mode != "" {
+			r.mode = opts.Mode
+		}
+		if opts.Transport != nil {
+			r.transport = opts.Transport
+		}
+		for _, header := range opts.RedactHeaders {
+			r.redact[http.CanonicalHeaderKey(header)] = true
+		}
+		r.secrets = append(r.secrets, opts.Secrets...)
+	}
+
+	if r.mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &r.cassette); err != nil {
+			return nil, fmt.Errorf("failed to decode cassette: %w", err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+	return r, nil
+}
+
+// Mode returns the mode of the recorder
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Client returns an http.Client using the recorder, for Client.SetHTTPClient
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r, Timeout: 30 * time.Second}
+}
+
+// Interactions returns a copy of the recorded or loaded interactions
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction(nil), r.cassette.Interactions...)
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch r.mode {
+	case ModePassthrough:
+		return r.transport.RoundTrip(req)
+	case ModeReplay:
+		return r.replay(req)
+	case ModeRecord:
+		return r.record(req)
+	}
+	return nil, fmt.Errorf("unknown cassette mode %q", r.mode)
+}
+
+// Save redacts and writes the recorded interactions; it does nothing outside
+// record mode
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	cassette := Cassette{Version: r.cassette.Version, Interactions: append([]Interaction(nil), r.cassette.Interactions...)}
+	r.mu.Unlock()
+
+	redactAccountIDs(cassette.Interactions)
+	for i := range cassette.Interactions {
+		r.redactSecrets(&cassette.Interactions[i])
+	}
+
+	data, err := json.MarshalIndent(cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	if err := os.WriteFile(r.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	recorded, err := r.captureRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	interaction := Interaction{
+		Request:    recorded,
+		Response:   Response{Status: resp.StatusCode, Headers: r.redactHeaders(resp.Header), Body: body},
+		RecordedAt: r.now().UTC(),
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// replay answers with the first unused matching interaction, or with the
+// last matching one once all have been used, so polling loops keep working
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	incoming, err := r.captureRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	match := -1
+	for i, interaction := range r.cassette.Interactions {
+		if !matches(interaction.Request, incoming) {
+			continue
+		}
+		match = i
+		if !r.used[i] {
+			break
+		}
+	}
+	if match < 0 {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, req.Method, req.URL.RequestURI())
+	}
+	r.used[match] = true
+
+	recorded := r.cassette.Interactions[match].Response
+	header := recorded.Headers.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+		StatusCode:    recorded.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
+
+// captureRequest reads the request body, restoring it for the transport
+func (r *Recorder) captureRequest(req *http.Request) (Request, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return Request{}, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	return Request{
+		Method:  req.Method,
+		Path:    req.URL.Path,
+		Query:   canonicalQuery(req.URL.RawQuery),
+		Headers: r.redactHeaders(req.Header),
+		Body:    body,
+	}, nil
+}
+
+func (r *Recorder) redactHeaders(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+	redacted := header.Clone()
+	for name := range redacted {
+		if r.redact[http.CanonicalHeaderKey(name)] {
+			redacted[name] = []string{Redacted}
+		}
+	}
+	return redacted
+}
+
+func (r *Recorder) redactSecrets(interaction *Interaction) {
+	replace := func(s string) string {
+		for _, secret := range r.secrets {
+			if secret != "" {
+				s = strings.ReplaceAll(s, secret, Redacted)
+			}
+		}
+		return s
+	}
+	replaceBody := func(b Body) Body {
+		return Body(replace(string(b)))
+	}
+	replaceHeader := func(h http.Header) {
+		for name, values := range h {
+			for i, value := range values {
+				h[name][i] = replace(value)
+			}
+		}
+	}
+
+	interaction.Request.Path = replace(interaction.Request.Path)
+	interaction.Request.Query = replace(interaction.Request.Query)
+	interaction.Request.Body = replaceBody(interaction.Request.Body)
+	interaction.Response.Body = replaceBody(interaction.Response.Body)
+	replaceHeader(interaction.Request.Headers)
+	replaceHeader(interaction.Response.Headers)
+}
+
+// accountPaths return the account ID in the "id" field of their response
+var accountPaths = []string{"/api/v0/equity/account/info", "/api/v0/equity/account/summary"}
+
+// redactAccountIDs replaces the "id" field of account responses with
+// RedactedAccountID, and the account IDs found there wherever they make up a
+// whole path segment, query value or header value. Other bodies are left
+// alone, since order IDs, quantities or prices may share the digits.
+func redactAccountIDs(interactions []Interaction) {
+	ids := make(map[string]bool)
+	for i, interaction := range interactions {
+		if !containsString(accountPaths, interaction.Request.Path) {
+			continue
+		}
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(interaction.Response.Body, &fields) != nil {
+			continue
+		}
+		id, ok := fields["id"]
+		if !ok || string(id) == "null" {
+			continue
+		}
+		ids[strings.Trim(string(id), `"`)] = true
+		fields["id"] = json.RawMessage(RedactedAccountID)
+		if body, err := json.Marshal(fields); err == nil {
+			interactions[i].Response.Body = body
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	replace := func(s string) string {
+		if ids[s] {
+			return RedactedAccountID
+		}
+		return s
+	}
+	replaceHeader := func(h http.Header) {
+		for name, values := range h {
+			for i, value := range values {
+				h[name][i] = replace(value)
+			}
+		}
+	}
+	for i := range interactions {
+		req := &interactions[i].Request
+		segments := strings.Split(req.Path, "/")
+		for j := range segments {
+			segments[j] = replace(segments[j])
+		}
+		req.Path = strings.Join(segments, "/")
+		if values, err := url.ParseQuery(req.Query); err == nil {
+			for _, vs := range values {
+				for j := range vs {
+					vs[j] = replace(vs[j])
+				}
+			}
+			req.Query = canonicalQuery(values.Encode())
+		}
+		replaceHeader(req.Headers)
+		replaceHeader(interactions[i].Response.Headers)
+	}
+}
+
+// canonicalQuery sorts query parameters so their order does not matter
+func canonicalQuery(raw string) string {
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		return raw
+	}
+	for _, v := range values {
+		sort.Strings(v)
+	}
+	return values.Encode()
+}
+
+// matches compares method, path, query and body; JSON bodies are compared
+// semantically so key order and whitespace do not matter
+func matches(recorded, incoming Request) bool {
+	if recorded.Method != incoming.Method || recorded.Path != incoming.Path || recorded.Query != incoming.Query {
+		return false
+	}
+	if bytes.Equal(recorded.Body, incoming.Body) {
+		return true
+	}
+
+	var a, b interface{}
+	if json.Unmarshal(recorded.Body, &a) != nil || json.Unmarshal(incoming.Body, &b) != nil {
+		return false
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func containsString(values []string, s string) bool {
+	for _, value := range values {
+		if value == s {
+			return true
+		}
+	}
+	return false
+}
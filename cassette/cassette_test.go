@@ -0,0 +1,205 @@
+package cassette
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newServer(t *testing.T, hits *int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(hits, 1)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v0/equity/account/info":
+			w.Write([]byte(`{"currencyCode":"GBP","id":55512345}`))
+		case "/api/v0/equity/history/orders":
+			w.Write([]byte(`{"items":[{"fill":{"id":1,"quantity":2}}],"nextPagePath":null,"echo":"` + r.URL.RawQuery + `"}`))
+		case "/api/v0/equity/orders/market":
+			var req trading212.MarketOrderRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			json.NewEncoder(w).Encode(trading212.Order{ID: 7, Ticker: req.Ticker, Quantity: req.Quantity, Status: trading212.OrderStatusNew})
+		case "/api/v0/equity/history/exports/report.csv":
+			w.Header().Set("Content-Type", "text/csv")
+			w.Write([]byte("a,b\n1,2\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	var hits int32
+	server := newServer(t, &hits)
+	path := filepath.Join(t.TempDir(), "fixtures", "session.json")
+	ctx := context.Background()
+
+	recorder, err := New(path, &Options{Mode: ModeRecord, Secrets: []string{"secret-key"}})
+	require.NoError(t, err)
+	client := trading212.NewClient(trading212.Environment(server.URL), "secret-key", "secret")
+	client.SetHTTPClient(recorder.Client())
+
+	info, err := client.GetAccountInfo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(55512345), info.ID)
+	_, err = client.GetHistoricalOrders(ctx, &trading212.HistoryOrdersOptions{Ticker: "AAPL_US_EQ", Limit: 5})
+	require.NoError(t, err)
+	order, err := client.PlaceMarketOrder(ctx, trading212.MarketOrderRequest{Ticker: "AAPL_US_EQ", Quantity: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), order.ID)
+	require.NoError(t, recorder.Save())
+	assert.Equal(t, int32(3), hits)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "55512345")
+	assert.NotContains(t, string(data), "Basic ")
+	assert.Contains(t, string(data), `"Authorization": [`)
+	assert.Contains(t, string(data), RedactedAccountID)
+	assert.Contains(t, string(data), `"ticker": "AAPL_US_EQ"`, "JSON bodies are stored as JSON")
+
+	replayer, err := New(path, &Options{Mode: ModeReplay})
+	require.NoError(t, err)
+	offline := trading212.NewClient(trading212.Environment("http://127.0.0.1:1"), "other", "credentials")
+	offline.SetHTTPClient(replayer.Client())
+
+	info, err = offline.GetAccountInfo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10000000), info.ID)
+
+	history, err := offline.GetHistoricalOrders(ctx, &trading212.HistoryOrdersOptions{Ticker: "AAPL_US_EQ", Limit: 5})
+	require.NoError(t, err)
+	require.Len(t, history.Items, 1)
+
+	order, err = offline.PlaceMarketOrder(ctx, trading212.MarketOrderRequest{Ticker: "AAPL_US_EQ", Quantity: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), order.ID)
+
+	_, err = offline.PlaceMarketOrder(ctx, trading212.MarketOrderRequest{Ticker: "AAPL_US_EQ", Quantity: 2})
+	assert.True(t, errors.Is(err, ErrNoInteraction), err)
+	_, err = offline.GetHistoricalOrders(ctx, &trading212.HistoryOrdersOptions{Ticker: "MSFT_US_EQ", Limit: 5})
+	assert.True(t, errors.Is(err, ErrNoInteraction), err)
+	assert.Equal(t, int32(3), hits)
+}
+
+func TestReplay_OrderOfRepeatedRequests(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "repeat.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"version":1,"interactions":[
+		{"request":{"method":"GET","path":"/x","query":"a=1&b=2"},"response":{"status":200,"body":{"n":1}}},
+		{"request":{"method":"GET","path":"/x","query":"a=1&b=2"},"response":{"status":200,"body":{"n":2}}},
+		{"request":{"method":"POST","path":"/y","body":{"a":1,"b":[1,2]}},"response":{"status":201,"body":"plain text"}}
+	]}`), 0o644))
+
+	recorder, err := New(path, nil)
+	require.NoError(t, err)
+	client := recorder.Client()
+
+	get := func() string {
+		resp, err := client.Get("http://example.invalid/x?b=2&a=1")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var body struct{ N int }
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return string(rune('0' + body.N))
+	}
+	assert.Equal(t, "1", get())
+	assert.Equal(t, "2", get())
+	assert.Equal(t, "2", get(), "the last match repeats once all are used")
+
+	resp, err := client.Post("http://example.invalid/y", "application/json", strings.NewReader(`{"b": [1, 2], "a": 1}`))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	resp.Body.Close()
+}
+
+func TestPassthroughAndNonJSONBodies(t *testing.T) {
+	var hits int32
+	server := newServer(t, &hits)
+	path := filepath.Join(t.TempDir(), "csv.json")
+
+	passthrough, err := New(path, &Options{Mode: ModePassthrough})
+	require.NoError(t, err)
+	resp, err := passthrough.Client().Get(server.URL + "/api/v0/equity/history/exports/report.csv")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.NoError(t, passthrough.Save())
+	assert.Empty(t, passthrough.Interactions())
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	recorder, err := New(path, &Options{Mode: ModeRecord})
+	require.NoError(t, err)
+	resp, err = recorder.Client().Get(server.URL + "/api/v0/equity/history/exports/report.csv")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.NoError(t, recorder.Save())
+
+	replayer, err := New(path, nil)
+	require.NoError(t, err)
+	interactions := replayer.Interactions()
+	require.Len(t, interactions, 1)
+	assert.Equal(t, "a,b\n1,2\n", string(interactions[0].Response.Body))
+	assert.Equal(t, "text/csv", interactions[0].Response.Headers.Get("Content-Type"))
+}
+
+func TestParseMode(t *testing.T) {
+	mode, err := ParseMode("")
+	require.NoError(t, err)
+	assert.Equal(t, ModeReplay, mode)
+
+	mode, err = ParseMode(" Record ")
+	require.NoError(t, err)
+	assert.Equal(t, ModeRecord, mode)
+
+	_, err = ParseMode("rewind")
+	assert.Error(t, err)
+
+	_, err = New(filepath.Join(t.TempDir(), "missing.json"), nil)
+	assert.Error(t, err)
+}
+
+func TestRedactAccountIDsLeavesOtherFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Account", "55512345")
+		switch r.URL.Path {
+		case "/api/v0/equity/account/info":
+			w.Write([]byte(`{"currencyCode":"GBP","id":55512345}`))
+		default:
+			w.Write([]byte(`{"id":55512345,"quantity":55512345,"ticker":"AAPL_US_EQ"}`))
+		}
+	}))
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "ids.json")
+
+	recorder, err := New(path, &Options{Mode: ModeRecord})
+	require.NoError(t, err)
+	for _, p := range []string{"/api/v0/equity/account/info", "/api/v0/equity/orders/55512345"} {
+		resp, err := recorder.Client().Get(server.URL + p)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	require.NoError(t, recorder.Save())
+
+	replayer, err := New(path, nil)
+	require.NoError(t, err)
+	interactions := replayer.Interactions()
+	require.Len(t, interactions, 2)
+	assert.JSONEq(t, `{"currencyCode":"GBP","id":10000000}`, string(interactions[0].Response.Body))
+	assert.Equal(t, RedactedAccountID, interactions[0].Response.Headers.Get("X-Account"))
+	assert.Equal(t, "/api/v0/equity/orders/"+RedactedAccountID, interactions[1].Request.Path)
+	assert.JSONEq(t, `{"id":55512345,"quantity":55512345,"ticker":"AAPL_US_EQ"}`, string(interactions[1].Response.Body))
+}
@@ -0,0 +1,302 @@
+package trading212
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CatalogOptions represents options for an instrument catalogue
+type CatalogOptions struct {
+	// TTL is how long loaded instruments are considered fresh (default 1h).
+	// The instruments endpoint allows 1 request per 50s, so shorter TTLs are
+	// raised to that.
+	TTL time.Duration
+	// CachePath, when set, persists the catalogue to disk between runs
+	CachePath string
+}
+
+// InstrumentFilter represents filters for InstrumentCatalog.Filter; zero
+// fields match everything
+type InstrumentFilter struct {
+	Type          InstrumentType
+	Currency      string
+	ExtendedHours *bool
+}
+
+// InstrumentCatalog caches the instrument list and indexes it for lookups
+type InstrumentCatalog struct {
+	client *Client
+	ttl    time.Duration
+	path   string
+
+	mu          sync.RWMutex
+	instruments []TradableInstrument
+	byTicker    map[string]int
+	byISIN      map[string][]int
+	byShortName map[string][]int
+	loadedAt    time.Time
+	listeners   []func([]InstrumentChange)
+}
+
+// catalogCache is the on-disk representation of a catalogue
+type catalogCache struct {
+	LoadedAt    time.Time            `json:"loadedAt"`
+	Instruments []TradableInstrument `json:"instruments"`
+}
+
+// NewInstrumentCatalog creates an empty catalogue backed by client
+func NewInstrumentCatalog(client *Client, opts *CatalogOptions) *InstrumentCatalog {
+	catalog := &InstrumentCatalog{client: client, ttl: time.Hour}
+	if opts != nil {
+		if opts.TTL > 0 {
+			catalog.ttl = opts.TTL
+		}
+		catalog.path = opts.CachePath
+	}
+	if catalog.ttl < 50*time.Second {
+		catalog.ttl = 50 * time.Second
+	}
+	return catalog
+}
+
+// NewInstrumentCatalogFrom creates a catalogue from an existing instrument
+// list without an API client; Refresh is not available on it
+func NewInstrumentCatalogFrom(instruments []TradableInstrument) *InstrumentCatalog {
+	catalog := &InstrumentCatalog{ttl: time.Hour}
+	catalog.set(instruments, time.Now())
+	return catalog
+}
+
+// Load populates the catalogue from the disk cache when it is fresh and from
+// the API otherwise. A stale cache is used if the API request fails. A cache
+// that cannot be read is treated as missing and replaced by the refresh.
+func (c *InstrumentCatalog) Load(ctx context.Context) error {
+	cached, cacheErr := c.readCache()
+	if cached != nil {
+		c.set(cached.Instruments, cached.LoadedAt)
+		if time.Since(cached.LoadedAt) < c.ttl {
+			return nil
+		}
+	}
+
+	if err := c.Refresh(ctx); err != nil {
+		if cached != nil {
+			return nil
+		}
+		return errors.Join(err, cacheErr)
+	}
+	return nil
+}
+
+// Refresh fetches the instrument list from the API, rebuilds the indexes and
+// updates the disk cache
+func (c *InstrumentCatalog) Refresh(ctx context.Context) error {
+	if c.client == nil {
+		return errors.New("catalog has no client to refresh from")
+	}
+
+	instruments, err := c.client.GetInstruments(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to refresh instruments: %w", err)
+	}
+
+	loadedAt := time.Now()
+	previous := c.All()
+	c.set(instruments, loadedAt)
+	c.notify(previous, instruments)
+	return c.writeCache(catalogCache{LoadedAt: loadedAt, Instruments: instruments})
+}
+
+// Start refreshes the catalogue in the background every TTL until ctx is
+// done. Refresh errors are passed to onError, which may be nil.
+func (c *InstrumentCatalog) Start(ctx context.Context, onError func(error)) {
+	go func() {
+		wait := c.ttl - time.Since(c.LoadedAt())
+		for {
+			if wait < 0 {
+				wait = 0
+			}
+
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+
+			wait = c.ttl
+			if err := c.Refresh(ctx); err != nil {
+				if onError != nil && ctx.Err() == nil {
+					onError(err)
+				}
+				wait = 50 * time.Second
+			}
+		}
+	}()
+}
+
+// LoadedAt returns when the catalogue data was fetched from the API
+func (c *InstrumentCatalog) LoadedAt() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.loadedAt
+}
+
+// Len returns the number of instruments in the catalogue
+func (c *InstrumentCatalog) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.instruments)
+}
+
+// All returns a copy of every instrument in the catalogue
+func (c *InstrumentCatalog) All() []TradableInstrument {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]TradableInstrument(nil), c.instruments...)
+}
+
+// ByTicker returns the instrument with the given ticker
+func (c *InstrumentCatalog) ByTicker(ticker string) (TradableInstrument, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	i, ok := c.byTicker[ticker]
+	if !ok {
+		return TradableInstrument{}, false
+	}
+	return c.instruments[i], true
+}
+
+// ByISIN returns every instrument with the given ISIN; the same security is
+// often listed on several exchanges
+func (c *InstrumentCatalog) ByISIN(isin string) []TradableInstrument {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.collect(c.byISIN[strings.ToUpper(isin)])
+}
+
+// ByShortName returns every instrument with the given short name, ignoring case
+func (c *InstrumentCatalog) ByShortName(shortName string) []TradableInstrument {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.collect(c.byShortName[strings.ToUpper(shortName)])
+}
+
+// Filter returns the instruments matching f
+func (c *InstrumentCatalog) Filter(f InstrumentFilter) []TradableInstrument {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var result []TradableInstrument
+	for _, instrument := range c.instruments {
+		if f.Type != "" && instrument.Type != f.Type {
+			continue
+		}
+		if f.Currency != "" && !strings.EqualFold(instrument.CurrencyCode, f.Currency) {
+			continue
+		}
+		if f.ExtendedHours != nil && instrument.ExtendedHours != *f.ExtendedHours {
+			continue
+		}
+		result = append(result, instrument)
+	}
+	return result
+}
+
+// set replaces the catalogue contents and rebuilds the indexes
+func (c *InstrumentCatalog) set(instruments []TradableInstrument, loadedAt time.Time) {
+	byTicker := make(map[string]int, len(instruments))
+	byISIN := make(map[string][]int)
+	byShortName := make(map[string][]int)
+	for i, instrument := range instruments {
+		byTicker[instrument.Ticker] = i
+		if instrument.ISIN != "" {
+			key := strings.ToUpper(instrument.ISIN)
+			byISIN[key] = append(byISIN[key], i)
+		}
+		if instrument.ShortName != "" {
+			key := strings.ToUpper(instrument.ShortName)
+			byShortName[key] = append(byShortName[key], i)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.instruments = instruments
+	c.byTicker = byTicker
+	c.byISIN = byISIN
+	c.byShortName = byShortName
+	c.loadedAt = loadedAt
+}
+
+// collect returns the instruments at the given indexes; callers must hold c.mu
+func (c *InstrumentCatalog) collect(indexes []int) []TradableInstrument {
+	if len(indexes) == 0 {
+		return nil
+	}
+	result := make([]TradableInstrument, len(indexes))
+	for i, index := range indexes {
+		result[i] = c.instruments[index]
+	}
+	return result
+}
+
+// readCache loads the disk cache, returning nil if there is none
+func (c *InstrumentCatalog) readCache() (*catalogCache, error) {
+	if c.path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read catalog cache: %w", err)
+	}
+
+	var cached catalogCache
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil, fmt.Errorf("failed to decode catalog cache: %w", err)
+	}
+	return &cached, nil
+}
+
+// writeCache atomically replaces the disk cache
+func (c *InstrumentCatalog) writeCache(cached catalogCache) error {
+	if c.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return fmt.Errorf("failed to encode catalog cache: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write catalog cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write catalog cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write catalog cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("failed to write catalog cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package trading212
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testInstruments = []TradableInstrument{
+	{Ticker: "AAPL_US_EQ", ISIN: "US0378331005", ShortName: "AAPL", Name: "Apple", CurrencyCode: "USD", Type: InstrumentTypeStock, ExtendedHours: true},
+	{Ticker: "VUSAl_EQ", ISIN: "IE00B3XXRP09", ShortName: "VUSA", Name: "Vanguard S&P 500 (Dist)", CurrencyCode: "GBP", Type: InstrumentTypeETF},
+	{Ticker: "VUSAa_EQ", ISIN: "IE00B3XXRP09", ShortName: "VUSA", Name: "Vanguard S&P 500 (Dist)", CurrencyCode: "EUR", Type: InstrumentTypeETF},
+}
+
+func newInstrumentServer(t *testing.T) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		json.NewEncoder(w).Encode(testInstruments)
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestInstrumentCatalog_Lookups(t *testing.T) {
+	server, _ := newInstrumentServer(t)
+	catalog := NewInstrumentCatalog(NewClient(Environment(server.URL), "key", "secret"), nil)
+	require.NoError(t, catalog.Load(context.Background()))
+
+	instrument, ok := catalog.ByTicker("AAPL_US_EQ")
+	require.True(t, ok)
+	assert.Equal(t, "Apple", instrument.Name)
+
+	_, ok = catalog.ByTicker("MSFT_US_EQ")
+	assert.False(t, ok)
+
+	assert.Len(t, catalog.ByISIN("ie00b3xxrp09"), 2)
+	assert.Len(t, catalog.ByShortName("vusa"), 2)
+
+	assert.Len(t, catalog.Filter(InstrumentFilter{Type: InstrumentTypeETF}), 2)
+	assert.Len(t, catalog.Filter(InstrumentFilter{Type: InstrumentTypeETF, Currency: "gbp"}), 1)
+	extended := true
+	assert.Len(t, catalog.Filter(InstrumentFilter{ExtendedHours: &extended}), 1)
+}
+
+func TestInstrumentCatalog_DiskCache(t *testing.T) {
+	server, calls := newInstrumentServer(t)
+	client := NewClient(Environment(server.URL), "key", "secret")
+	path := filepath.Join(t.TempDir(), "instruments.json")
+
+	first := NewInstrumentCatalog(client, &CatalogOptions{CachePath: path})
+	require.NoError(t, first.Load(context.Background()))
+	assert.EqualValues(t, 1, atomic.LoadInt32(calls))
+
+	second := NewInstrumentCatalog(client, &CatalogOptions{CachePath: path})
+	require.NoError(t, second.Load(context.Background()))
+	assert.EqualValues(t, 1, atomic.LoadInt32(calls))
+	assert.Equal(t, 3, second.Len())
+	assert.WithinDuration(t, first.LoadedAt(), second.LoadedAt(), 0)
+
+	data, err := json.Marshal(catalogCache{LoadedAt: time.Now().Add(-2 * time.Hour), Instruments: testInstruments[:1]})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	server.Close()
+	stale := NewInstrumentCatalog(client, &CatalogOptions{CachePath: path})
+	require.NoError(t, stale.Load(context.Background()))
+	assert.Equal(t, 1, stale.Len())
+}
+
+func TestInstrumentCatalog_CorruptCache(t *testing.T) {
+	server, calls := newInstrumentServer(t)
+	client := NewClient(Environment(server.URL), "key", "secret")
+	path := filepath.Join(t.TempDir(), "instruments.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"loadedAt": "2024-01-01T00:00:00Z", "instru`), 0o600))
+
+	catalog := NewInstrumentCatalog(client, &CatalogOptions{CachePath: path})
+	require.NoError(t, catalog.Load(context.Background()))
+	assert.EqualValues(t, 1, atomic.LoadInt32(calls))
+	assert.Equal(t, 3, catalog.Len())
+
+	// The refresh replaced the corrupt file
+	reloaded := NewInstrumentCatalog(client, &CatalogOptions{CachePath: path})
+	require.NoError(t, reloaded.Load(context.Background()))
+	assert.EqualValues(t, 1, atomic.LoadInt32(calls))
+
+	// Without the API both errors are reported
+	require.NoError(t, os.WriteFile(path, []byte(`{`), 0o600))
+	server.Close()
+	err := NewInstrumentCatalog(client, &CatalogOptions{CachePath: path}).Load(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to decode catalog cache")
+	assert.Contains(t, err.Error(), "failed to refresh instruments")
+}
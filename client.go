@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"sync"
 	"time"
 )
 
@@ -29,6 +30,17 @@ type Client struct {
 	apiKey     string
 	apiSecret  string
 	httpClient *http.Client
+	journal    OrderJournal
+	dryRun     bool
+
+	dryRunLogger func(DryRunCall)
+
+	submissionsMu sync.Mutex
+	submissions   map[string]*SubmissionResult
+	inFlight      map[string]chan struct{}
+
+	statusesMu sync.Mutex
+	statuses   map[int64]OrderStatus
 }
 
 // NewClient creates a new Trading 212 API client
@@ -78,13 +90,23 @@ func (c *Client) makeRequest(ctx context.Context, method, path string, body inte
 	return resp, nil
 }
 
+// APIError represents an error response returned by the API
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Body)
+}
+
 // handleResponse processes the HTTP response and unmarshals JSON
 func (c *Client) handleResponse(resp *http.Response, result interface{}) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return &APIError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	if result != nil {
@@ -120,6 +142,10 @@ func buildQuery(params map[string]interface{}) string {
 				values.Add(key, strconv.FormatBool(v))
 			case time.Time:
 				values.Add(key, v.Format(time.RFC3339))
+			case *time.Time:
+				if v != nil {
+					values.Add(key, v.Format(time.RFC3339))
+				}
 			}
 		}
 	}
@@ -0,0 +1,409 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/internal/openapi"
+)
+
+// surface maps spec schemas to the hand-written SDK types that represent them
+var surface = []struct {
+	schema string
+	value  interface{}
+}{
+	{"AccountBucketDetailedResponse", trading212.PieSettings{}},
+	{"AccountBucketInstrumentResult", trading212.PieInstrument{}},
+	{"AccountBucketInstrumentsDetailedResponse", trading212.PieDetails{}},
+	{"AccountBucketResultResponse", trading212.Pie{}},
+	{"AccountSummary", trading212.AccountSummary{}},
+	{"Cash", trading212.AccountCash{}},
+	{"DividendDetails", trading212.PieDividendDetails{}},
+	{"DuplicateBucketRequest", trading212.DuplicatePieRequest{}},
+	{"EnqueuedReportResponse", trading212.EnqueuedReportResponse{}},
+	{"Exchange", trading212.Exchange{}},
+	{"Fill", trading212.Fill{}},
+	{"FillWalletImpact", trading212.FillWalletImpact{}},
+	{"HistoricalOrder", trading212.HistoricalOrder{}},
+	{"HistoryDividendItem", trading212.HistoryDividendItem{}},
+	{"HistoryTransactionItem", trading212.HistoryTransactionItem{}},
+	{"Instrument", trading212.Instrument{}},
+	{"InstrumentIssue", trading212.PieInstrumentIssue{}},
+	{"InvestmentResult", trading212.PieResult{}},
+	{"LimitRequest", trading212.LimitOrderRequest{}},
+	{"MarketRequest", trading212.MarketOrderRequest{}},
+	{"Order", trading212.Order{}},
+	{"PieRequest", trading212.PieRequest{}},
+	{"Position", trading212.Position{}},
+	{"PositionWalletImpact", trading212.PositionWalletImpact{}},
+	{"PublicReportRequest", trading212.PublicReportRequest{}},
+	{"ReportDataIncluded", trading212.ReportDataIncluded{}},
+	{"ReportResponse", trading212.ReportResponse{}},
+	{"StopLimitRequest", trading212.StopLimitOrderRequest{}},
+	{"StopRequest", trading212.StopOrderRequest{}},
+	{"Tax", trading212.Tax{}},
+	{"TimeEvent", trading212.TimeEvent{}},
+	{"TradableInstrument", trading212.TradableInstrument{}},
+	{"WorkingSchedule", trading212.WorkingSchedule{}},
+}
+
+// enums maps spec enums to the SDK types that can tell known values apart
+var enums = []struct {
+	schema   string
+	property string
+	sdkType  string
+	known    func(string) bool
+}{
+	{"Fill", "type", "trading212.FillType", func(v string) bool { return trading212.FillType(v).IsKnown() }},
+	{"Fill", "tradingMethod", "trading212.FillTradingMethod", func(v string) bool { return trading212.FillTradingMethod(v).IsKnown() }},
+	{"HistoryDividendItem", "type", "trading212.DividendType", func(v string) bool { return trading212.DividendType(v).IsKnown() }},
+	{"HistoryTransactionItem", "type", "trading212.TransactionType", func(v string) bool { return trading212.TransactionType(v).IsKnown() }},
+}
+
+// methods maps the API skeleton methods of the generated file to the
+// trading212.Client methods that implement them
+var methods = map[string]string{
+	"CancelOrder":       "CancelOrder",
+	"Create":            "CreatePie",
+	"Delete":            "DeletePie",
+	"Dividends":         "GetDividends",
+	"DuplicatePie":      "DuplicatePie",
+	"Exchanges":         "GetExchanges",
+	"GetAccountSummary": "GetAccountSummary",
+	"GetAll":            "GetPies",
+	"GetDetailed":       "GetPie",
+	"GetPositions":      "GetPositions",
+	"GetReports":        "GetReports",
+	"Instruments":       "GetInstruments",
+	"OrderByID":         "GetOrderByID",
+	"Orders":            "GetOrders",
+	"Orders1":           "GetHistoricalOrders",
+	"PlaceLimitOrder":   "PlaceLimitOrder",
+	"PlaceMarketOrder":  "PlaceMarketOrder",
+	"PlaceStopOrder":    "PlaceStopLimitOrder",
+	"PlaceStopOrder1":   "PlaceStopOrder",
+	"RequestReport":     "RequestReport",
+	"Transactions":      "GetTransactions",
+	"Update":            "UpdatePie",
+}
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	decimalType = reflect.TypeOf(trading212.Decimal{})
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	clientType  = reflect.TypeOf(&trading212.Client{})
+)
+
+// checkSurface returns the differences between the spec and the SDK found in
+// the Go sources of srcDir, one stable line per difference
+func checkSurface(doc *openapi.Document, srcDir string) ([]string, error) {
+	var diffs []string
+	for _, mapping := range surface {
+		schema, err := doc.Resolve(doc.Schema(mapping.schema))
+		if err != nil || schema == nil {
+			return nil, fmt.Errorf("schema %s: not in the spec", mapping.schema)
+		}
+		diffs = append(diffs, compareStruct(doc, mapping.schema, schema, reflect.TypeOf(mapping.value))...)
+	}
+
+	for _, enum := range enums {
+		schema := doc.Schema(enum.schema)
+		if schema == nil || schema.Properties[enum.property] == nil {
+			return nil, fmt.Errorf("enum %s.%s: not in the spec", enum.schema, enum.property)
+		}
+		for _, value := range schema.Properties[enum.property].Enum {
+			if !enum.known(value) {
+				diffs = append(diffs, fmt.Sprintf("%s.%s: value %s is not known to %s", enum.schema, enum.property, value, enum.sdkType))
+			}
+		}
+	}
+
+	diffs = append(diffs, checkMethods(doc)...)
+
+	paths, err := clientPaths(srcDir)
+	if err != nil {
+		return nil, err
+	}
+	specPaths := map[string]bool{}
+	for _, operation := range doc.Operations() {
+		key := normalizePath(operation.Path)
+		specPaths[key] = true
+		if !paths[key] {
+			diffs = append(diffs, fmt.Sprintf("%s %s: no client method uses this path", operation.Method, operation.Path))
+		}
+	}
+	for path := range paths {
+		if !specPaths[path] {
+			diffs = append(diffs, fmt.Sprintf("%s: used by the client but not in the spec", path))
+		}
+	}
+
+	sort.Strings(diffs)
+	return diffs, nil
+}
+
+// checkMethods checks that trading212.Client implements every API skeleton
+// with the SDK types that surface maps the spec schemas to
+func checkMethods(doc *openapi.Document) []string {
+	sdkTypes := map[string]reflect.Type{}
+	for _, mapping := range surface {
+		sdkTypes[mapping.schema] = reflect.TypeOf(mapping.value)
+	}
+
+	var diffs []string
+	skeletons := map[string]bool{}
+	for _, operation := range doc.Operations() {
+		skeleton := goName(operation.OperationID)
+		skeletons[skeleton] = true
+		name, ok := methods[skeleton]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("%s: no trading212.Client method is mapped to %s %s", skeleton, operation.Method, operation.Path))
+			continue
+		}
+		method, ok := clientType.MethodByName(name)
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("%s: trading212.Client has no method %s", skeleton, name))
+			continue
+		}
+		if problem := compareMethod(doc, operation, method.Type, sdkTypes); problem != "" {
+			diffs = append(diffs, fmt.Sprintf("%s: trading212.Client.%s %s", skeleton, name, problem))
+		}
+	}
+	for skeleton := range methods {
+		if !skeletons[skeleton] {
+			diffs = append(diffs, fmt.Sprintf("%s: mapped to a client method but not in the spec", skeleton))
+		}
+	}
+	return diffs
+}
+
+// compareMethod returns why the method type t does not fit operation, or ""
+func compareMethod(doc *openapi.Document, operation *openapi.Operation, t reflect.Type, sdkTypes map[string]reflect.Type) string {
+	// Index 0 is the receiver
+	if t.NumIn() < 2 || t.In(1) != contextType {
+		return "does not take a context.Context first"
+	}
+	if t.NumOut() == 0 || t.Out(t.NumOut()-1) != errorType {
+		return "does not return an error last"
+	}
+
+	in := 2
+	for _, parameter := range operation.PathParameters() {
+		if in >= t.NumIn() || !compatible(doc, parameter.Schema, t.In(in)) {
+			return fmt.Sprintf("does not take path parameter %s as %s", parameter.Name, describe(parameter.Schema))
+		}
+		in++
+	}
+
+	if s := operation.RequestSchema(); s != nil && s.Ref != "" {
+		if want, ok := sdkTypes[openapi.RefName(s.Ref)]; ok && !takes(t, in, want) {
+			return fmt.Sprintf("does not take a %s request", want)
+		}
+	}
+
+	_, s := operation.SuccessSchema()
+	if s == nil {
+		return ""
+	}
+	if t.NumOut() < 2 {
+		return "returns no " + describe(s) + " response"
+	}
+	var want reflect.Type
+	switch {
+	case s.Ref != "":
+		if sdk, ok := sdkTypes[openapi.RefName(s.Ref)]; ok {
+			want = reflect.PtrTo(sdk)
+		}
+	case s.Type == "array" && s.Items != nil && s.Items.Ref != "":
+		if sdk, ok := sdkTypes[openapi.RefName(s.Items.Ref)]; ok {
+			want = reflect.SliceOf(sdk)
+		}
+	}
+	if want != nil && t.Out(0) != want {
+		return fmt.Sprintf("returns %s, spec wants %s", t.Out(0), want)
+	}
+	return ""
+}
+
+// takes reports whether one of the parameters of t from index from on is
+// want or a pointer to it
+func takes(t reflect.Type, from int, want reflect.Type) bool {
+	for i := from; i < t.NumIn(); i++ {
+		if t.In(i) == want || t.In(i) == reflect.PtrTo(want) {
+			return true
+		}
+	}
+	return false
+}
+
+// compareStruct compares the properties of a schema with the JSON fields of t
+func compareStruct(doc *openapi.Document, name string, schema *openapi.Schema, t reflect.Type) []string {
+	fields := jsonFields(t)
+	var diffs []string
+	for property, propertySchema := range schema.Properties {
+		field, ok := fields[property]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("%s: field %s missing from %s", name, property, t))
+			continue
+		}
+		if !compatible(doc, propertySchema, field) {
+			diffs = append(diffs, fmt.Sprintf("%s: field %s is %s in %s, spec wants %s", name, property, field, t, describe(propertySchema)))
+		}
+	}
+	for property := range fields {
+		if _, ok := schema.Properties[property]; !ok {
+			diffs = append(diffs, fmt.Sprintf("%s: field %s of %s is not in the spec", name, property, t))
+		}
+	}
+	return diffs
+}
+
+// jsonFields returns the JSON field names of t, flattening embedded structs
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := strings.Split(field.Tag.Get("json"), ",")[0]
+		if tag == "-" || !field.IsExported() {
+			continue
+		}
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct {
+			for name, typ := range jsonFields(field.Type) {
+				fields[name] = typ
+			}
+			continue
+		}
+		if tag == "" {
+			tag = field.Name
+		}
+		fields[tag] = field.Type
+	}
+	return fields
+}
+
+// compatible reports whether values of t can hold values of schema s
+func compatible(doc *openapi.Document, s *openapi.Schema, t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	resolved, err := doc.Resolve(s)
+	if err != nil || resolved == nil {
+		return false
+	}
+
+	switch resolved.Type {
+	case "string":
+		if resolved.Format == "date-time" {
+			return t == timeType
+		}
+		return t.Kind() == reflect.String
+	case "number":
+		return t == decimalType || t.Kind() == reflect.Float64 || t.Kind() == reflect.Float32
+	case "integer":
+		switch t.Kind() {
+		case reflect.Int, reflect.Int32, reflect.Int64:
+			return true
+		}
+		return false
+	case "boolean":
+		return t.Kind() == reflect.Bool
+	case "array":
+		return t.Kind() == reflect.Slice && compatible(doc, resolved.Items, t.Elem())
+	case "object":
+		if resolved.AdditionalProperties != nil {
+			return t.Kind() == reflect.Map && compatible(doc, resolved.AdditionalProperties, t.Elem())
+		}
+		return t.Kind() == reflect.Struct || t.Kind() == reflect.Map
+	}
+	return true
+}
+
+func describe(s *openapi.Schema) string {
+	switch {
+	case s.Ref != "":
+		return openapi.RefName(s.Ref)
+	case s.Format != "":
+		return s.Type + " (" + s.Format + ")"
+	case s.Type == "array" && s.Items != nil:
+		return "array of " + describe(s.Items)
+	}
+	return s.Type
+}
+
+var formatVerb = regexp.MustCompile(`%[a-z]`)
+
+// clientPaths collects the API paths used as string literals in the
+// non-test Go files of dir
+func clientPaths(dir string) (map[string]bool, error) {
+	fset := token.NewFileSet()
+	packages, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse client sources: %w", err)
+	}
+
+	paths := map[string]bool{}
+	for _, pkg := range packages {
+		ast.Inspect(pkg, func(node ast.Node) bool {
+			literal, ok := node.(*ast.BasicLit)
+			if !ok || literal.Kind != token.STRING {
+				return true
+			}
+			value, err := strconv.Unquote(literal.Value)
+			if err == nil && strings.HasPrefix(value, "/api/") {
+				paths[formatVerb.ReplaceAllString(value, "{}")] = true
+			}
+			return true
+		})
+	}
+	return paths, nil
+}
+
+var pathParameter = regexp.MustCompile(`\{[^}]*\}`)
+
+// normalizePath replaces path parameters so spec paths and client format
+// strings compare equal
+func normalizePath(path string) string {
+	return pathParameter.ReplaceAllString(path, "{}")
+}
+
+// readDrift reads the known drift allowlist, ignoring blank lines and comments
+func readDrift(path string) (map[string]bool, error) {
+	known := map[string]bool{}
+	if path == "" {
+		return known, nil
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return known, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read known drift: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			known[line] = true
+		}
+	}
+	return known, scanner.Err()
+}
@@ -0,0 +1,363 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/internal/openapi"
+)
+
+// generator renders Go source for a spec
+type generator struct {
+	doc *openapi.Document
+	buf bytes.Buffer
+	// enums holds the enum types declared inline in object properties
+	enums map[string][]string
+	names map[string]string
+}
+
+// generate returns the formatted Go source for doc in package pkg
+func generate(doc *openapi.Document, pkg string) ([]byte, error) {
+	g := &generator{doc: doc, enums: map[string][]string{}, names: map[string]string{}}
+
+	for _, name := range doc.SchemaNames() {
+		if err := g.declare(name, "schema "+name); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range doc.SchemaNames() {
+		if err := g.schema(name, doc.Schema(name)); err != nil {
+			return nil, err
+		}
+	}
+	g.enumTypes()
+	if err := g.endpoints(); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by specgen from api.yaml; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\n", pkg)
+	fmt.Fprintf(&out, "import (\n\"context\"\n")
+	if bytes.Contains(g.buf.Bytes(), []byte("time.Time")) {
+		fmt.Fprintf(&out, "\"time\"\n")
+	}
+	fmt.Fprintf(&out, ")\n\n")
+	out.Write(g.buf.Bytes())
+
+	source, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return source, nil
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// declare reserves a Go type name so clashes are reported instead of
+// producing code that does not compile
+func (g *generator) declare(name, origin string) error {
+	if previous, ok := g.names[name]; ok {
+		return fmt.Errorf("type %s is generated for both %s and %s", name, previous, origin)
+	}
+	g.names[name] = origin
+	return nil
+}
+
+// schema declares the Go type of a component schema
+func (g *generator) schema(name string, s *openapi.Schema) error {
+	g.comment(name+" represents the "+name+" schema.", s.Description)
+
+	if len(s.Enum) > 0 {
+		g.printf("type %s string\n\n", name)
+		g.enumConstants(name, s.Enum)
+		return nil
+	}
+
+	if s.Type != "object" || len(s.Properties) == 0 {
+		goType, err := g.goType(name, "", s)
+		if err != nil {
+			return err
+		}
+		g.printf("type %s %s\n\n", name, goType)
+		return nil
+	}
+
+	g.printf("type %s struct {\n", name)
+	for _, property := range sortedKeys(s.Properties) {
+		field := s.Properties[property]
+		goType, err := g.goType(name, property, field)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", name, property, err)
+		}
+		if field.Description != "" {
+			g.printf("// %s\n", oneLine(field.Description))
+		}
+		g.printf("%s %s `json:\"%s,omitempty\"`\n", goName(property), goType, property)
+	}
+	g.printf("}\n\n")
+	return nil
+}
+
+// goType returns the Go type for a schema used in owner.property
+func (g *generator) goType(owner, property string, s *openapi.Schema) (string, error) {
+	if s == nil {
+		return "interface{}", nil
+	}
+	if s.Ref != "" {
+		name := openapi.RefName(s.Ref)
+		if g.doc.Schema(name) == nil {
+			return "", fmt.Errorf("unresolved reference %s", s.Ref)
+		}
+		return name, nil
+	}
+
+	switch s.Type {
+	case "string":
+		switch {
+		case len(s.Enum) > 0:
+			name := owner + goName(property)
+			if _, ok := g.enums[name]; !ok {
+				if err := g.declare(name, "enum "+owner+"."+property); err != nil {
+					return "", err
+				}
+			}
+			g.enums[name] = s.Enum
+			return name, nil
+		case s.Format == "date-time":
+			return "time.Time", nil
+		}
+		return "string", nil
+	case "number":
+		return "float64", nil
+	case "integer":
+		switch s.Format {
+		case "int32":
+			return "int32", nil
+		case "int64":
+			return "int64", nil
+		}
+		return "int", nil
+	case "boolean":
+		return "bool", nil
+	case "array":
+		elem, err := g.goType(owner, property, s.Items)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + elem, nil
+	case "object":
+		if s.AdditionalProperties != nil {
+			elem, err := g.goType(owner, property, s.AdditionalProperties)
+			if err != nil {
+				return "", err
+			}
+			return "map[string]" + elem, nil
+		}
+	}
+	return "interface{}", nil
+}
+
+// enumTypes declares the enum types found in object properties
+func (g *generator) enumTypes() {
+	for _, name := range sortedKeys(g.enums) {
+		g.printf("// %s represents the values of %s.\n", name, g.names[name][len("enum "):])
+		g.printf("type %s string\n\n", name)
+		g.enumConstants(name, g.enums[name])
+	}
+}
+
+func (g *generator) enumConstants(typeName string, values []string) {
+	g.printf("const (\n")
+	for _, value := range values {
+		g.printf("%s%s %s = %q\n", typeName, goName(value), typeName, value)
+	}
+	g.printf(")\n\n")
+}
+
+// endpoints writes the endpoint table and the API method skeletons
+func (g *generator) endpoints() error {
+	operations := g.doc.Operations()
+
+	g.printf("// Endpoint describes an operation in the spec.\n")
+	g.printf("type Endpoint struct {\nOperationID string\nMethod string\nPath string\nRequest string\nResponse string\n}\n\n")
+	g.printf("// Endpoints lists every operation in the spec sorted by path and method.\n")
+	g.printf("var Endpoints = []Endpoint{\n")
+	for _, operation := range operations {
+		request, response, err := g.bodies(operation)
+		if err != nil {
+			return err
+		}
+		g.printf("{OperationID: %q, Method: %q, Path: %q, Request: %q, Response: %q},\n",
+			operation.OperationID, operation.Method, operation.Path, request, response)
+	}
+	g.printf("}\n\n")
+
+	for _, operation := range operations {
+		parameters := operation.QueryParameters()
+		if len(parameters) == 0 {
+			continue
+		}
+		name := goName(operation.OperationID) + "Params"
+		if err := g.declare(name, "parameters of "+operation.OperationID); err != nil {
+			return err
+		}
+		g.printf("// %s represents the query parameters of %s %s.\n", name, operation.Method, operation.Path)
+		g.printf("type %s struct {\n", name)
+		for _, parameter := range parameters {
+			goType, err := g.goType(name, parameter.Name, parameter.Schema)
+			if err != nil {
+				return err
+			}
+			if parameter.Description != "" {
+				g.printf("// %s\n", oneLine(parameter.Description))
+			}
+			g.printf("%s *%s `query:\"%s\"`\n", goName(parameter.Name), goType, parameter.Name)
+		}
+		g.printf("}\n\n")
+	}
+
+	g.printf("// API has a method skeleton for every operation in the spec.\n")
+	g.printf("type API interface {\n")
+	for _, operation := range operations {
+		args := []string{"ctx context.Context"}
+		for _, parameter := range operation.PathParameters() {
+			goType, err := g.goType("", parameter.Name, parameter.Schema)
+			if err != nil {
+				return err
+			}
+			args = append(args, lowerFirst(goName(parameter.Name))+" "+goType)
+		}
+		if len(operation.QueryParameters()) > 0 {
+			args = append(args, "params "+goName(operation.OperationID)+"Params")
+		}
+
+		request, response, err := g.bodies(operation)
+		if err != nil {
+			return err
+		}
+		if request != "" {
+			args = append(args, "body "+request)
+		}
+		results := "error"
+		if response != "" {
+			if !strings.HasPrefix(response, "[]") {
+				response = "*" + response
+			}
+			results = "(" + response + ", error)"
+		}
+
+		summary := operation.Summary
+		if summary == "" {
+			summary = operation.OperationID
+		}
+		g.printf("// %s calls %s %s: %s\n", goName(operation.OperationID), operation.Method, operation.Path, oneLine(summary))
+		g.printf("%s(%s) %s\n", goName(operation.OperationID), strings.Join(args, ", "), results)
+	}
+	g.printf("}\n")
+	return nil
+}
+
+// bodies returns the Go types of the request and success response bodies
+func (g *generator) bodies(operation *openapi.Operation) (request, response string, err error) {
+	if s := operation.RequestSchema(); s != nil {
+		if request, err = g.goType(goName(operation.OperationID), "Request", s); err != nil {
+			return "", "", err
+		}
+	}
+	if _, s := operation.SuccessSchema(); s != nil {
+		if response, err = g.goType(goName(operation.OperationID), "Response", s); err != nil {
+			return "", "", err
+		}
+	}
+	return request, response, nil
+}
+
+func (g *generator) comment(first, description string) {
+	g.printf("// %s\n", first)
+	if description != "" {
+		g.printf("// %s\n", oneLine(description))
+	}
+}
+
+// initialisms are words written in upper case in Go names
+var initialisms = map[string]bool{
+	"API": true, "CVR": true, "ETF": true, "FOP": true, "ID": true,
+	"ISIN": true, "OTC": true, "PTP": true, "TOTV": true, "URL": true, "US": true,
+}
+
+// goName converts camelCase, snake_case and UPPER_CASE names into an
+// exported Go identifier
+func goName(s string) string {
+	var b strings.Builder
+	for _, word := range splitWords(s) {
+		upper := strings.ToUpper(word)
+		switch {
+		case initialisms[upper]:
+			b.WriteString(upper)
+		case unicode.IsDigit(rune(word[0])):
+			b.WriteString(upper)
+		default:
+			b.WriteString(upper[:1] + strings.ToLower(word[1:]))
+		}
+	}
+	if b.Len() == 0 || unicode.IsDigit(rune(b.String()[0])) {
+		return "X" + b.String()
+	}
+	return b.String()
+}
+
+// splitWords splits on separators and on lower to upper case transitions
+func splitWords(s string) []string {
+	var words []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = nil
+		}
+	}
+
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+			continue
+		case unicode.IsUpper(r) && i > 0 && unicode.IsLower(runes[i-1]):
+			flush()
+		}
+		current = append(current, r)
+	}
+	flush()
+	return words
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	if initialisms[s] {
+		return strings.ToLower(s)
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
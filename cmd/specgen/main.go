@@ -0,0 +1,96 @@
+// Command specgen generates Go types, enum constants and endpoint skeletons
+// from api.yaml, and in check mode reports where the hand-written SDK has
+// drifted from the spec.
+//
+// Usage:
+//
+//	go run ./cmd/specgen -spec api.yaml -out spec/zz_generated.go
+//	go run ./cmd/specgen -check
+//
+// Check mode fails when the generated file is stale or when the SDK differs
+// from the spec in a way that is not listed in the known drift file. This
+// includes API skeletons that trading212.Client does not implement.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/internal/openapi"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "specgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("specgen", flag.ContinueOnError)
+	specPath := flags.String("spec", "api.yaml", "OpenAPI spec to read")
+	out := flags.String("out", "spec/zz_generated.go", "generated Go file")
+	pkg := flags.String("pkg", "spec", "package name of the generated file")
+	check := flags.Bool("check", false, "verify the generated file and the SDK surface instead of writing")
+	src := flags.String("src", ".", "directory of the hand-written SDK sources")
+	drift := flags.String("drift", "spec/known_drift.txt", "allowlist of known differences between SDK and spec")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	doc, err := openapi.Load(*specPath)
+	if err != nil {
+		return err
+	}
+	source, err := generate(doc, *pkg)
+	if err != nil {
+		return err
+	}
+
+	if !*check {
+		if err := os.WriteFile(*out, source, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", *out, err)
+		}
+		return nil
+	}
+
+	var problems []string
+	existing, err := os.ReadFile(*out)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read %s: %w", *out, err)
+	}
+	if !bytes.Equal(existing, source) {
+		problems = append(problems, fmt.Sprintf("%s is out of date, run go generate ./spec", *out))
+	}
+
+	diffs, err := checkSurface(doc, *src)
+	if err != nil {
+		return err
+	}
+	known, err := readDrift(*drift)
+	if err != nil {
+		return err
+	}
+	for _, diff := range diffs {
+		if known[diff] {
+			delete(known, diff)
+			continue
+		}
+		problems = append(problems, diff)
+	}
+	for _, stale := range sortedKeys(known) {
+		problems = append(problems, fmt.Sprintf("known drift no longer occurs, remove it from %s: %s", *drift, stale))
+	}
+
+	for _, problem := range problems {
+		fmt.Fprintln(stdout, problem)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%d difference(s) between the SDK and %s", len(problems), *specPath)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/internal/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRepositoryMatchesSpec fails when spec/zz_generated.go is stale or the
+// SDK drifts from api.yaml beyond spec/known_drift.txt
+func TestRepositoryMatchesSpec(t *testing.T) {
+	var out bytes.Buffer
+	err := run([]string{
+		"-check",
+		"-spec", "../../api.yaml",
+		"-out", "../../spec/zz_generated.go",
+		"-src", "../..",
+		"-drift", "../../spec/known_drift.txt",
+	}, &out)
+	assert.NoError(t, err, out.String())
+}
+
+func TestRun_ReportsDrift(t *testing.T) {
+	dir := t.TempDir()
+	generated := filepath.Join(dir, "zz_generated.go")
+	drift := filepath.Join(dir, "drift.txt")
+	require.NoError(t, os.WriteFile(drift, []byte("# none\nOrder: something that is fixed\n"), 0o644))
+
+	var out bytes.Buffer
+	err := run([]string{"-check", "-spec", "../../api.yaml", "-out", generated, "-src", "../..", "-drift", drift}, &out)
+	require.Error(t, err)
+	assert.Contains(t, out.String(), generated+" is out of date")
+	assert.Contains(t, out.String(), "Position: field averagePricePaid missing from trading212.Position")
+	assert.Contains(t, out.String(), "GET /api/v0/equity/positions: no client method uses this path")
+	assert.Contains(t, out.String(), "known drift no longer occurs")
+
+	require.NoError(t, run([]string{"-spec", "../../api.yaml", "-out", generated}, &out))
+	source, err := os.ReadFile(generated)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(source), "// Code generated by specgen"))
+}
+
+func TestGenerate(t *testing.T) {
+	doc, err := openapi.Parse([]byte(`
+paths:
+  /api/v0/widgets/{id}:
+    get:
+      operationId: getWidget
+      parameters:
+        - {in: path, name: id, schema: {type: integer, format: int64}}
+        - {in: query, name: cursor, schema: {type: string}}
+      responses:
+        "200":
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Widget'}
+components:
+  schemas:
+    Widget:
+      type: object
+      properties:
+        widgetId: {type: integer, format: int64}
+        createdAt: {type: string, format: date-time}
+        kind: {type: string, enum: [SMALL_ONE, US_LARGE]}
+        tags: {type: array, items: {type: string}}
+`))
+	require.NoError(t, err)
+
+	source, err := generate(doc, "widgets")
+	require.NoError(t, err)
+	code := string(source)
+	assert.Contains(t, code, "package widgets")
+	assert.Contains(t, code, "WidgetID  int64")
+	assert.Contains(t, code, "CreatedAt time.Time")
+	assert.Contains(t, code, `WidgetKindSmallOne WidgetKind = "SMALL_ONE"`)
+	assert.Contains(t, code, `WidgetKindUSLarge  WidgetKind = "US_LARGE"`)
+	assert.Contains(t, code, "Tags      []string")
+	assert.Contains(t, code, "GetWidget(ctx context.Context, id int64, params GetWidgetParams) (*Widget, error)")
+}
+
+func TestCheckMethods(t *testing.T) {
+	doc, err := openapi.Parse([]byte(`
+paths:
+  /api/v0/equity/orders/{id}:
+    get:
+      operationId: orderById
+      parameters:
+        - {in: path, name: id, schema: {type: string}}
+  /api/v0/equity/pies:
+    get:
+      operationId: getAll
+      responses:
+        "200":
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: '#/components/schemas/Position'}}
+  /api/v0/widgets:
+    get:
+      operationId: getWidgets
+components:
+  schemas:
+    Position: {type: object}
+`))
+	require.NoError(t, err)
+
+	diffs := checkMethods(doc)
+	assert.Contains(t, diffs, "OrderByID: trading212.Client.GetOrderByID does not take path parameter id as string")
+	assert.Contains(t, diffs, "GetAll: trading212.Client.GetPies returns []trading212.Pie, spec wants []trading212.Position")
+	assert.Contains(t, diffs, "GetWidgets: no trading212.Client method is mapped to GET /api/v0/widgets")
+	assert.Contains(t, diffs, "CancelOrder: mapped to a client method but not in the spec")
+}
+
+func TestGoName(t *testing.T) {
+	tests := map[string]string{
+		"workingScheduleId":        "WorkingScheduleID",
+		"isin":                     "ISIN",
+		"fxPpl":                    "FxPpl",
+		"GOOD_TILL_CANCEL":         "GoodTillCancel",
+		"MULTIPLE_1042S_TAX":       "Multiple1042STax",
+		"orders_1":                 "Orders1",
+		"Queued":                   "Queued",
+		"RETURN_OF_CAPITAL_NON_US": "ReturnOfCapitalNonUS",
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, goName(in), in)
+	}
+}
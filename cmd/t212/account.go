@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+)
+
+func (a *app) account(ctx context.Context, args []string) error {
+	return subcommand(ctx, "account", args, map[string]func(context.Context, []string) error{
+		"info": func(ctx context.Context, args []string) error {
+			info, err := a.client.GetAccountInfo(ctx)
+			if err != nil {
+				return err
+			}
+			return a.print(info)
+		},
+		"cash": func(ctx context.Context, args []string) error {
+			cash, err := a.client.GetAccountCash(ctx)
+			if err != nil {
+				return err
+			}
+			return a.print(cash)
+		},
+		"summary": func(ctx context.Context, args []string) error {
+			summary, err := a.client.GetAccountSummary(ctx)
+			if err != nil {
+				return err
+			}
+			return a.print(summary)
+		},
+	})
+}
+
+func (a *app) positions(ctx context.Context, args []string) error {
+	flags := a.newFlags("positions")
+	ticker := flags.String("ticker", "", "only show the position in this ticker")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return &usageError{"positions [-ticker T]"}
+	}
+
+	var opts *trading212.GetPositionsOptions
+	if *ticker != "" {
+		opts = &trading212.GetPositionsOptions{Ticker: *ticker}
+	}
+	positions, err := a.client.GetPositions(ctx, opts)
+	if err != nil {
+		return err
+	}
+	return a.print(positions)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/allocation"
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/format"
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/ledger"
+)
+
+func init() {
+	format.SetDefaultColumns(allocation.Holding{}, "ticker", "type", "currency", "exchange", "value", "weight", "ppl", "fxImpact")
+	format.SetDefaultColumns(allocation.Group{}, "key", "positions", "value", "weight", "ppl", "fxImpact")
+}
+
+func (a *app) allocation(ctx context.Context, args []string) error {
+	flags := a.newFlags("allocation")
+	by := flags.String("by", "holdings", "group by type, currency, exchange or pie, or list holdings")
+	dir := flags.String("dir", "", "ledger directory to take FX rates from")
+	rates := flags.String("rate", "", "FX rates to account currency overriding the ledger, e.g. USD=0.79,EUR=0.85")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return &usageError{"allocation [-by holdings|type|currency|exchange|pie] [-dir DIR] [-rate CUR=RATE,...]"}
+	}
+	var dimension allocation.Dimension
+	if !strings.EqualFold(*by, "holdings") {
+		d, err := allocation.ParseDimension(*by)
+		if err != nil {
+			return err
+		}
+		dimension = d
+	}
+	overrides, err := allocation.ParseRates(*rates)
+	if err != nil {
+		return err
+	}
+
+	info, err := a.client.GetAccountInfo(ctx)
+	if err != nil {
+		return err
+	}
+	instruments, err := a.client.GetInstruments(ctx)
+	if err != nil {
+		return err
+	}
+	exchanges, err := a.client.GetExchanges(ctx)
+	if err != nil {
+		return err
+	}
+	positions, err := a.client.GetPositions(ctx, nil)
+	if err != nil {
+		return err
+	}
+	l, err := a.openLedger(*dir)
+	if err != nil {
+		return err
+	}
+
+	catalog := trading212.NewInstrumentCatalogFrom(instruments)
+	opts := &allocation.Options{Currency: info.Currency, Rates: allocation.RatesFromFills(l.Orders(ledger.Query{}), catalog)}
+	for currency, rate := range overrides {
+		opts.Rates[currency] = rate
+	}
+	report := allocation.Analyze(positions, catalog, exchanges, opts)
+	if len(report.Unpriced) > 0 {
+		fmt.Fprintf(a.stderr, "no FX rate for %s; pass -rate to include them\n", strings.Join(report.Unpriced, ", "))
+	}
+	if dimension == "" {
+		return a.print(report.Holdings)
+	}
+	return a.print(report.Groups[dimension])
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/format"
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/ledger"
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/tax"
+)
+
+func init() {
+	format.SetDefaultColumns(tax.YearSummary{}, "taxYear", "disposals", "proceeds", "allowableCosts", "gains", "losses", "netGain")
+	format.SetDefaultColumns(tax.Disposal{}, "taxYear", "date", "ticker", "quantity", "proceeds", "allowableCost", "gain")
+	format.SetDefaultColumns(tax.Pool{}, "ticker", "quantity", "cost")
+}
+
+// stringList is a flag that may be given more than once
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
+func (a *app) cgt(ctx context.Context, args []string) error {
+	return subcommand(ctx, "cgt", args, map[string]func(context.Context, []string) error{
+		"summary":   a.cgtReport("summary"),
+		"disposals": a.cgtReport("disposals"),
+		"pools":     a.cgtReport("pools"),
+	})
+}
+
+// cgtReport computes UK capital gains from the fills stored in one or more
+// ledgers and prints one view of the result
+func (a *app) cgtReport(view string) func(context.Context, []string) error {
+	return func(ctx context.Context, args []string) error {
+		var dirs, isas stringList
+		flags := a.newFlags("cgt " + view)
+		flags.Var(&dirs, "dir", "ledger directory of a taxable account; may be repeated (default: the ledger of the selected environment)")
+		flags.Var(&isas, "isa", "ledger directory of an ISA account, which is excluded; may be repeated")
+		year := flags.String("year", "", "only show this tax year, e.g. 2024/25")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if flags.NArg() != 0 {
+			return &usageError{"cgt " + view + " [-dir DIR]... [-isa DIR]... [-year YYYY/YY]"}
+		}
+
+		var taxYear tax.TaxYear
+		if *year != "" {
+			var err error
+			if taxYear, err = tax.ParseTaxYear(*year); err != nil {
+				return err
+			}
+		}
+		if len(dirs) == 0 {
+			dirs = append(dirs, "")
+		}
+
+		var accounts []tax.Account
+		for _, group := range []struct {
+			dirs []string
+			isa  bool
+		}{{dirs, false}, {isas, true}} {
+			for _, dir := range group.dirs {
+				l, err := a.openLedger(dir)
+				if err != nil {
+					return err
+				}
+				accounts = append(accounts, tax.Account{Name: dir, ISA: group.isa, Orders: l.Orders(ledger.Query{})})
+			}
+		}
+
+		report, err := tax.Compute(accounts)
+		if err != nil {
+			return err
+		}
+		for _, warning := range report.Warnings {
+			fmt.Fprintln(a.stderr, "warning:", warning)
+		}
+
+		switch view {
+		case "summary":
+			if taxYear != 0 {
+				return a.print([]tax.YearSummary{report.Year(taxYear)})
+			}
+			return a.print(report.Years)
+		case "disposals":
+			disposals := report.Disposals
+			if taxYear != 0 {
+				disposals = nil
+				for _, d := range report.Disposals {
+					if d.TaxYear == taxYear {
+						disposals = append(disposals, d)
+					}
+				}
+			}
+			return a.print(disposals)
+		}
+		return a.print(report.Pools)
+	}
+}
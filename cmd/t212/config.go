@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+)
+
+// config represents the credentials and environment selection
+type config struct {
+	Environment string `json:"environment"`
+	APIKey      string `json:"apiKey"`
+	APISecret   string `json:"apiSecret"`
+}
+
+// loadConfig merges the config file, the environment variables and the -env
+// flag, in increasing order of precedence. A missing default config file is
+// not an error; a missing explicit one is.
+func loadConfig(path, envFlag string, getenv func(string) string) (config, error) {
+	explicit := path != ""
+	if path == "" {
+		path = getenv("T212_CONFIG")
+		explicit = path != ""
+	}
+	if path == "" {
+		if dir, err := os.UserConfigDir(); err == nil {
+			path = filepath.Join(dir, "t212", "config.json")
+		}
+	}
+
+	var cfg config
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case errors.Is(err, os.ErrNotExist) && !explicit:
+		case err != nil:
+			return config{}, fmt.Errorf("failed to read config: %w", err)
+		default:
+			if err := json.Unmarshal(data, &cfg); err != nil {
+				return config{}, fmt.Errorf("failed to decode config %s: %w", path, err)
+			}
+		}
+	}
+
+	if value := getenv("T212_ENV"); value != "" {
+		cfg.Environment = value
+	}
+	if value := getenv("T212_API_KEY"); value != "" {
+		cfg.APIKey = value
+	}
+	if value := getenv("T212_API_SECRET"); value != "" {
+		cfg.APISecret = value
+	}
+	if envFlag != "" {
+		cfg.Environment = envFlag
+	}
+	if cfg.Environment == "" {
+		cfg.Environment = "demo"
+	}
+	return cfg, nil
+}
+
+// environment resolves demo, live or a base URL such as a local fake server
+func environment(name string) (trading212.Environment, error) {
+	switch strings.ToLower(name) {
+	case "demo":
+		return trading212.Demo, nil
+	case "live":
+		return trading212.Live, nil
+	}
+	if strings.HasPrefix(name, "http://") || strings.HasPrefix(name, "https://") {
+		return trading212.Environment(strings.TrimSuffix(name, "/")), nil
+	}
+	return "", fmt.Errorf("unknown environment %q, expected demo, live or a URL", name)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/format"
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/ledger"
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/portfolio"
+)
+
+func init() {
+	format.SetDefaultColumns(portfolio.Holding{}, "ticker", "quantity", "averagePrice", "cost", "realizedPL", "currency")
+	format.SetDefaultColumns(portfolio.Lot{}, "ticker", "acquired", "quantity", "cost", "fillId")
+	format.SetDefaultColumns(portfolio.Disposal{}, "ticker", "date", "quantity", "proceeds", "cost", "realizedPL")
+	format.SetDefaultColumns(portfolio.Discrepancy{}, "ticker", "field", "position", "computed")
+}
+
+func (a *app) costbasis(ctx context.Context, args []string) error {
+	return subcommand(ctx, "costbasis", args, map[string]func(context.Context, []string) error{
+		"holdings":  a.costbasisReport("holdings"),
+		"lots":      a.costbasisReport("lots"),
+		"disposals": a.costbasisReport("disposals"),
+		"reconcile": a.costbasisReport("reconcile"),
+	})
+}
+
+// costbasisReport replays the fills stored in the ledger and prints one view
+// of the result
+func (a *app) costbasisReport(view string) func(context.Context, []string) error {
+	return func(ctx context.Context, args []string) error {
+		flags := a.newFlags("costbasis " + view)
+		dir := flags.String("dir", "", "ledger directory")
+		method := flags.String("method", "average", "cost basis method: average or fifo")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if flags.NArg() != 0 {
+			return &usageError{"costbasis " + view + " [-dir DIR] [-method average|fifo]"}
+		}
+
+		m, err := portfolio.ParseMethod(*method)
+		if err != nil {
+			return err
+		}
+		l, err := a.openLedger(*dir)
+		if err != nil {
+			return err
+		}
+		p, err := portfolio.Replay(l.Orders(ledger.Query{}), m)
+		if err != nil {
+			return err
+		}
+		for _, warning := range p.Warnings() {
+			fmt.Fprintln(a.stderr, "warning:", warning)
+		}
+
+		switch view {
+		case "holdings":
+			return a.print(p.Holdings())
+		case "lots":
+			return a.print(p.Lots())
+		case "disposals":
+			return a.print(p.Disposals())
+		}
+
+		positions, err := a.client.GetPositions(ctx, nil)
+		if err != nil {
+			return err
+		}
+		return a.print(p.Reconcile(positions, nil))
+	}
+}
@@ -0,0 +1,577 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+)
+
+// Rate limits of the endpoints the dashboard polls, as minimum time between
+// requests
+const (
+	cashRateLimit      = 2 * time.Second
+	positionsRateLimit = time.Second
+	ordersRateLimit    = 5 * time.Second
+	fillsRateLimit     = 10 * time.Second
+	// maxBackoff caps the wait after repeated 429 responses
+	maxBackoff = time.Minute
+)
+
+const dashHelp = "tab switch  ↑/↓ select  m market order  c cancel order  r refresh  q quit"
+
+// key is a decoded key press: a printable character or one of the names
+// "enter", "esc", "tab", "backspace", "up", "down" and "ctrl-c"
+type key string
+
+// poller refreshes one part of the dashboard. It polls every interval but
+// never sends requests closer together than limit, and backs off on 429.
+type poller struct {
+	name    string
+	every   time.Duration
+	limit   time.Duration
+	fetch   func(context.Context) error
+	refresh chan struct{}
+}
+
+// prompt is an open question at the bottom of the dashboard. Confirmation
+// prompts take a single y/n key; others collect a line of input.
+type prompt struct {
+	label   string
+	input   string
+	confirm bool
+	submit  func(ctx context.Context, input string)
+}
+
+// dashboard holds the data shown by t212 dash. Pollers update the data
+// fields under mu; the UI fields are only used by the run loop.
+type dashboard struct {
+	a      *app
+	fills  int
+	redraw chan struct{}
+
+	mu        sync.Mutex
+	info      *trading212.AccountInfo
+	cash      *trading212.AccountCash
+	positions []trading212.Position
+	orders    []trading212.Order
+	history   []trading212.HistoricalOrder
+	errs      map[string]error
+	updated   time.Time
+
+	pollers  []*poller
+	focus    int
+	selected [2]int
+	prompt   *prompt
+	status   string
+}
+
+func (a *app) dash(ctx context.Context, args []string) error {
+	flags := a.newFlags("dash")
+	every := flags.Duration("refresh", 5*time.Second, "refresh interval; raised to each endpoint's rate limit")
+	fills := flags.Int("fills", 10, "number of recent fills to show, at most 50")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return &usageError{"dash [-refresh D] [-fills N]"}
+	}
+
+	restore := a.enterTerminal()
+	defer restore()
+	return newDashboard(a, *every, *fills).run(ctx)
+}
+
+func newDashboard(a *app, every time.Duration, fills int) *dashboard {
+	d := &dashboard{a: a, fills: fills, redraw: make(chan struct{}, 1), errs: make(map[string]error)}
+	d.pollers = []*poller{
+		{name: "cash", limit: cashRateLimit, fetch: d.fetchCash},
+		{name: "positions", limit: positionsRateLimit, fetch: d.fetchPositions},
+		{name: "orders", limit: ordersRateLimit, fetch: d.fetchOrders},
+		{name: "fills", limit: fillsRateLimit, fetch: d.fetchFills},
+	}
+	for _, p := range d.pollers {
+		p.every = every
+		if p.name == "fills" {
+			// history allows 6 requests a minute, shared with other tools
+			p.every = 3 * every
+		}
+		if p.every < p.limit {
+			p.every = p.limit
+		}
+		p.refresh = make(chan struct{}, 1)
+	}
+	return d
+}
+
+// run draws the dashboard until q is pressed, the input ends or ctx is done
+func (d *dashboard) run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	info, err := d.a.client.GetAccountInfo(ctx)
+	d.mu.Lock()
+	d.info, d.errs["account"] = info, err
+	d.mu.Unlock()
+
+	for _, p := range d.pollers {
+		go p.run(ctx, d)
+	}
+	keys := make(chan key)
+	go readKeys(d.a.stdin, keys)
+
+	d.draw()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-d.redraw:
+		case k, ok := <-keys:
+			if !ok || d.handle(ctx, k) {
+				return nil
+			}
+		}
+		d.draw()
+	}
+}
+
+func (p *poller) run(ctx context.Context, d *dashboard) {
+	var last time.Time
+	next := time.Now()
+	backoff := p.every
+	for {
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-p.refresh:
+			timer.Stop()
+			if earliest := last.Add(p.limit); earliest.Before(next) {
+				next = earliest
+			}
+			continue
+		case <-timer.C:
+		}
+
+		last = time.Now()
+		err := p.fetch(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		var apiErr *trading212.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests {
+			if backoff *= 2; backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+			err = fmt.Errorf("rate limited, retrying in %s", backoff)
+			next = last.Add(backoff)
+		} else {
+			backoff = p.every
+			next = last.Add(p.every)
+		}
+		d.update(p.name, err)
+	}
+}
+
+func (d *dashboard) fetchCash(ctx context.Context) error {
+	cash, err := d.a.client.GetAccountCash(ctx)
+	if err == nil {
+		d.mu.Lock()
+		d.cash = cash
+		d.mu.Unlock()
+	}
+	return err
+}
+
+func (d *dashboard) fetchPositions(ctx context.Context) error {
+	positions, err := d.a.client.GetPositions(ctx, nil)
+	if err == nil {
+		d.mu.Lock()
+		d.positions = positions
+		d.mu.Unlock()
+	}
+	return err
+}
+
+func (d *dashboard) fetchOrders(ctx context.Context) error {
+	orders, err := d.a.client.GetOrders(ctx)
+	if err == nil {
+		d.mu.Lock()
+		d.orders = orders
+		d.mu.Unlock()
+	}
+	return err
+}
+
+func (d *dashboard) fetchFills(ctx context.Context) error {
+	page, err := d.a.client.GetHistoricalOrders(ctx, &trading212.HistoryOrdersOptions{Limit: d.fills})
+	if err == nil {
+		d.mu.Lock()
+		d.history = page.Items
+		d.mu.Unlock()
+	}
+	return err
+}
+
+// update records the outcome of a poll and asks for a redraw
+func (d *dashboard) update(name string, err error) {
+	d.mu.Lock()
+	d.errs[name] = err
+	if err == nil {
+		d.updated = time.Now()
+	}
+	d.mu.Unlock()
+
+	select {
+	case d.redraw <- struct{}{}:
+	default:
+	}
+}
+
+// refresh asks the named pollers, or all of them, to poll as soon as their
+// rate limit allows
+func (d *dashboard) refresh(names ...string) {
+	for _, p := range d.pollers {
+		if len(names) > 0 && !contains(names, p.name) {
+			continue
+		}
+		select {
+		case p.refresh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// handle processes a key press and reports whether to quit
+func (d *dashboard) handle(ctx context.Context, k key) bool {
+	if d.prompt != nil {
+		d.handlePrompt(ctx, k)
+		return false
+	}
+
+	d.mu.Lock()
+	counts := [2]int{len(d.positions), len(d.orders)}
+	d.mu.Unlock()
+
+	switch k {
+	case "q", "ctrl-c":
+		return true
+	case "tab":
+		d.focus = 1 - d.focus
+	case "up", "k":
+		if d.selected[d.focus] > 0 {
+			d.selected[d.focus]--
+		}
+	case "down", "j":
+		if d.selected[d.focus] < counts[d.focus]-1 {
+			d.selected[d.focus]++
+		}
+	case "r":
+		d.refresh()
+		d.status = "refreshing"
+	case "c":
+		d.cancelSelected()
+	case "m":
+		d.marketOrderPrompt()
+	}
+	return false
+}
+
+func (d *dashboard) handlePrompt(ctx context.Context, k key) {
+	p := d.prompt
+	if p.confirm {
+		d.prompt = nil
+		if k == "y" || k == "Y" {
+			p.submit(ctx, "y")
+		} else {
+			d.status = "cancelled"
+		}
+		return
+	}
+
+	switch k {
+	case "esc", "ctrl-c":
+		d.prompt = nil
+		d.status = "cancelled"
+	case "enter":
+		d.prompt = nil
+		p.submit(ctx, strings.TrimSpace(p.input))
+	case "backspace":
+		if r := []rune(p.input); len(r) > 0 {
+			p.input = string(r[:len(r)-1])
+		}
+	case "tab", "up", "down":
+	default:
+		p.input += string(k)
+	}
+}
+
+func (d *dashboard) cancelSelected() {
+	d.mu.Lock()
+	var order *trading212.Order
+	if i := d.selected[1]; d.focus == 1 && i < len(d.orders) {
+		order = &d.orders[i]
+	}
+	d.mu.Unlock()
+	if order == nil {
+		d.status = "select a pending order first (tab, ↑/↓)"
+		return
+	}
+
+	id := order.ID
+	d.prompt = &prompt{
+		label:   fmt.Sprintf("Cancel %s order %d for %g %s? [y/N] ", order.Type, id, order.Quantity, order.Ticker),
+		confirm: true,
+		submit: func(ctx context.Context, _ string) {
+			if err := d.a.client.CancelOrder(ctx, id); err != nil {
+				d.status = "cancel failed: " + err.Error()
+				return
+			}
+			d.status = fmt.Sprintf("cancelled order %d", id)
+			d.refresh("orders", "cash")
+		},
+	}
+}
+
+func (d *dashboard) marketOrderPrompt() {
+	input := ""
+	d.mu.Lock()
+	if i := d.selected[0]; d.focus == 0 && i < len(d.positions) {
+		input = d.positions[i].Ticker + " "
+	}
+	d.mu.Unlock()
+
+	d.prompt = &prompt{
+		label: "Market order, TICKER QUANTITY (negative to sell): ",
+		input: input,
+		submit: func(ctx context.Context, input string) {
+			fields := strings.Fields(input)
+			if len(fields) != 2 {
+				d.status = "expected TICKER QUANTITY"
+				return
+			}
+			quantity, err := strconv.ParseFloat(fields[1], 64)
+			if err != nil || quantity == 0 {
+				d.status = fmt.Sprintf("invalid quantity %q", fields[1])
+				return
+			}
+			req := trading212.MarketOrderRequest{Ticker: strings.ToUpper(fields[0]), Quantity: quantity}
+			d.prompt = &prompt{
+				label:   fmt.Sprintf("Place %s market order for %g %s? [y/N] ", d.envLabel(), req.Quantity, req.Ticker),
+				confirm: true,
+				submit: func(ctx context.Context, _ string) {
+					order, err := d.a.client.PlaceMarketOrder(ctx, req)
+					if err != nil {
+						d.status = "order failed: " + err.Error()
+						return
+					}
+					d.status = fmt.Sprintf("placed order %d (%s)", order.ID, order.Status)
+					d.refresh("orders", "positions", "cash", "fills")
+				},
+			}
+		},
+	}
+}
+
+func (d *dashboard) envLabel() string {
+	switch d.a.env {
+	case trading212.Live:
+		return "LIVE"
+	case trading212.Demo:
+		return "DEMO"
+	}
+	return string(d.a.env)
+}
+
+// draw repaints the whole screen
+func (d *dashboard) draw() {
+	var out strings.Builder
+	out.WriteString("\x1b[H")
+	for _, line := range strings.Split(strings.TrimRight(d.render(), "\n"), "\n") {
+		out.WriteString(line)
+		out.WriteString("\x1b[K\r\n")
+	}
+	out.WriteString("\x1b[J")
+	io.WriteString(d.a.stdout, out.String())
+}
+
+// render returns the dashboard as plain lines with ANSI colours
+func (d *dashboard) render() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var b strings.Builder
+	env := d.envLabel()
+	if d.a.env == trading212.Live {
+		env = red(env)
+	}
+	fmt.Fprintf(&b, "Trading 212 %s", env)
+	if d.info != nil {
+		fmt.Fprintf(&b, "  account %d (%s)", d.info.ID, d.info.Currency)
+	}
+	if !d.updated.IsZero() {
+		fmt.Fprintf(&b, "  updated %s", d.updated.Format("15:04:05"))
+	}
+	b.WriteString("\n")
+	writeError(&b, "account", d.errs["account"])
+
+	if d.cash != nil {
+		fmt.Fprintf(&b, "Cash  free %.2f  invested %.2f  total %.2f  result %s\n",
+			d.cash.Free, d.cash.Invested, d.cash.Total, signedColor(d.cash.Result, "%+.2f"))
+	}
+	writeError(&b, "cash", d.errs["cash"])
+
+	b.WriteString("\n" + d.title("POSITIONS", 0) + "\n")
+	writeError(&b, "positions", d.errs["positions"])
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  TICKER\tQUANTITY\tAVG PRICE\tPRICE\tP&L")
+	total := 0.0
+	for i, p := range d.positions {
+		change := 0.0
+		if p.AveragePrice != 0 {
+			change = (p.CurrentPrice/p.AveragePrice - 1) * 100
+		}
+		total += p.Ppl
+		fmt.Fprintf(tw, "%s%s\t%g\t%.2f\t%.2f\t%s (%s)\n", d.cursor(0, i), p.Ticker, p.Quantity, p.AveragePrice, p.CurrentPrice,
+			signedColor(p.Ppl, "%+.2f"), signedColor(change, "%+.2f%%"))
+	}
+	tw.Flush()
+	if len(d.positions) > 0 {
+		fmt.Fprintf(&b, "  total P&L %s\n", signedColor(total, "%+.2f"))
+	}
+
+	b.WriteString("\n" + d.title("PENDING ORDERS", 1) + "\n")
+	writeError(&b, "orders", d.errs["orders"])
+	tw = tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  ID\tTICKER\tTYPE\tQUANTITY\tLIMIT\tSTOP\tSTATUS")
+	for i, o := range d.orders {
+		fmt.Fprintf(tw, "%s%d\t%s\t%s\t%g\t%s\t%s\t%s\n", d.cursor(1, i), o.ID, o.Ticker, o.Type, o.Quantity,
+			optionalPrice(o.LimitPrice), optionalPrice(o.StopPrice), o.Status)
+	}
+	tw.Flush()
+
+	b.WriteString("\nRECENT FILLS\n")
+	writeError(&b, "fills", d.errs["fills"])
+	tw = tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  TIME\tTICKER\tQUANTITY\tPRICE\tNET VALUE")
+	for _, h := range d.history {
+		if h.Fill.ID == 0 && h.Fill.FilledAt.IsZero() {
+			continue
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%g\t%g\t%.2f %s\n", h.Fill.FilledAt.Local().Format("Jan 02 15:04"), h.Order.Ticker,
+			h.Fill.Quantity, h.Fill.Price, h.Fill.WalletImpact.NetValue, h.Fill.WalletImpact.Currency)
+	}
+	tw.Flush()
+
+	b.WriteString("\n")
+	switch {
+	case d.prompt != nil:
+		b.WriteString(d.prompt.label + d.prompt.input + "\n")
+	case d.status != "":
+		b.WriteString(d.status + "\n")
+	default:
+		b.WriteString("\n")
+	}
+	b.WriteString(dim(dashHelp) + "\n")
+	return b.String()
+}
+
+func (d *dashboard) title(name string, focus int) string {
+	if d.focus == focus {
+		return bold(name)
+	}
+	return name
+}
+
+func (d *dashboard) cursor(focus, i int) string {
+	if d.focus == focus && d.selected[focus] == i {
+		return "> "
+	}
+	return "  "
+}
+
+// readKeys decodes key presses from r until it fails, then closes keys
+func readKeys(r *bufio.Reader, keys chan<- key) {
+	defer close(keys)
+	for {
+		c, _, err := r.ReadRune()
+		if err != nil {
+			return
+		}
+		switch c {
+		case '\r', '\n':
+			keys <- "enter"
+		case '\t':
+			keys <- "tab"
+		case 3:
+			keys <- "ctrl-c"
+		case 8, 127:
+			keys <- "backspace"
+		case 27:
+			if r.Buffered() >= 2 {
+				if next, _ := r.Peek(2); next[0] == '[' {
+					r.Discard(2)
+					switch next[1] {
+					case 'A':
+						keys <- "up"
+					case 'B':
+						keys <- "down"
+					}
+					continue
+				}
+			}
+			keys <- "esc"
+		default:
+			if c >= ' ' {
+				keys <- key(string(c))
+			}
+		}
+	}
+}
+
+func writeError(b *strings.Builder, name string, err error) {
+	if err != nil {
+		fmt.Fprintf(b, "  %s\n", red(name+": "+err.Error()))
+	}
+}
+
+func optionalPrice(price *float64) string {
+	if price == nil {
+		return "-"
+	}
+	return strconv.FormatFloat(*price, 'f', -1, 64)
+}
+
+func signedColor(value float64, format string) string {
+	s := fmt.Sprintf(format, value)
+	switch {
+	case value > 0:
+		return "\x1b[32m" + s + "\x1b[0m"
+	case value < 0:
+		return red(s)
+	}
+	return s
+}
+
+func red(s string) string  { return "\x1b[31m" + s + "\x1b[0m" }
+func bold(s string) string { return "\x1b[1m" + s + "\x1b[0m" }
+func dim(s string) string  { return "\x1b[2m" + s + "\x1b[0m" }
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer is a bytes.Buffer safe for the dashboard and the test to share
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// fakeAccount is a fake API server for the dashboard
+type fakeAccount struct {
+	mu       sync.Mutex
+	requests map[string]int
+	orders   []map[string]interface{}
+	fills429 bool
+}
+
+func (f *fakeAccount) count(key string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests[key]
+}
+
+func (f *fakeAccount) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests[r.Method+" "+r.URL.Path]++
+
+	switch r.Method + " " + r.URL.Path {
+	case "GET /api/v0/equity/account/info":
+		w.Write([]byte(`{"id": 42, "currencyCode": "GBP"}`))
+	case "GET /api/v0/equity/account/cash":
+		w.Write([]byte(`{"free": 100, "invested": 900, "result": 12.5, "total": 1012.5}`))
+	case "GET /api/v0/equity/portfolio":
+		w.Write([]byte(`[{"ticker": "AAPL_US_EQ", "quantity": 2, "averagePrice": 100, "currentPrice": 110, "ppl": 16.1}]`))
+	case "GET /api/v0/equity/orders":
+		w.Write([]byte(`[{"id": 77, "ticker": "MSFT_US_EQ", "type": "LIMIT", "quantity": 1, "limitPrice": 300, "status": "NEW"}]`))
+	case "GET /api/v0/equity/history/orders":
+		if f.fills429 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"items": [{"order": {"ticker": "AAPL_US_EQ"}, "fill": {"id": 5, "filledAt": "2024-01-02T15:04:05Z", "quantity": 2, "price": 100, "walletImpact": {"netValue": 160.5, "currency": "GBP"}}}], "nextPagePath": null}`))
+	case "POST /api/v0/equity/orders/market":
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		f.orders = append(f.orders, body)
+		w.Write([]byte(`{"id": 88, "status": "NEW"}`))
+	case "DELETE /api/v0/equity/orders/77":
+		w.WriteHeader(http.StatusOK)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// startDash runs t212 dash against a fake server and returns a writer for
+// key presses, the screen output and a function that quits and waits
+func startDash(t *testing.T, fake *fakeAccount) (io.Writer, *syncBuffer, func()) {
+	fake.requests = make(map[string]int)
+	server := httptest.NewServer(fake)
+
+	keys, input := io.Pipe()
+	stdout := &syncBuffer{}
+	a, _ := newTestApp("", credentials())
+	a.stdin = bufio.NewReader(keys)
+	a.stdout = stdout
+
+	done := make(chan error, 1)
+	go func() {
+		done <- a.run(context.Background(), []string{"-env", server.URL, "dash"})
+	}()
+
+	return input, stdout, func() {
+		input.Write([]byte("q"))
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Error("dashboard did not quit")
+		}
+		input.Close()
+		server.Close()
+	}
+}
+
+func TestDashShowsAccount(t *testing.T) {
+	fake := &fakeAccount{}
+	_, stdout, quit := startDash(t, fake)
+	defer quit()
+
+	assert.Eventually(t, func() bool {
+		screen := stdout.String()
+		return strings.Contains(screen, "account 42 (GBP)") &&
+			strings.Contains(screen, "AAPL_US_EQ") &&
+			strings.Contains(screen, "MSFT_US_EQ") &&
+			strings.Contains(screen, "160.50 GBP") &&
+			strings.Contains(screen, "total 1012.50")
+	}, 3*time.Second, 10*time.Millisecond, stdout.String())
+	assert.Contains(t, stdout.String(), "+10.00%")
+}
+
+func TestDashCancelOrder(t *testing.T) {
+	fake := &fakeAccount{}
+	input, stdout, quit := startDash(t, fake)
+	defer quit()
+
+	require.Eventually(t, func() bool { return strings.Contains(stdout.String(), "MSFT_US_EQ") }, 3*time.Second, 10*time.Millisecond)
+	input.Write([]byte("\tc"))
+	require.Eventually(t, func() bool { return strings.Contains(stdout.String(), "Cancel LIMIT order 77") }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 0, fake.count("DELETE /api/v0/equity/orders/77"), "must wait for confirmation")
+
+	input.Write([]byte("y"))
+	assert.Eventually(t, func() bool { return fake.count("DELETE /api/v0/equity/orders/77") == 1 }, time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool { return strings.Contains(stdout.String(), "cancelled order 77") }, time.Second, 10*time.Millisecond)
+}
+
+func TestDashMarketOrder(t *testing.T) {
+	fake := &fakeAccount{}
+	input, stdout, quit := startDash(t, fake)
+	defer quit()
+
+	require.Eventually(t, func() bool { return strings.Contains(stdout.String(), "AAPL_US_EQ") }, 3*time.Second, 10*time.Millisecond)
+
+	// Declined orders are not sent
+	input.Write([]byte("m"))
+	input.Write([]byte("\x7f\x7f\x7f\x7f\x7f\x7f\x7f\x7f\x7f\x7f\x7fMSFT_US_EQ 1\rn"))
+	require.Eventually(t, func() bool { return strings.Contains(stdout.String(), "cancelled") }, time.Second, 10*time.Millisecond)
+
+	// The selected position's ticker is filled in
+	input.Write([]byte("m-1\r"))
+	require.Eventually(t, func() bool { return strings.Contains(stdout.String(), "market order for -1 AAPL_US_EQ?") }, time.Second, 10*time.Millisecond)
+	input.Write([]byte("y"))
+
+	require.Eventually(t, func() bool { return strings.Contains(stdout.String(), "placed order 88") }, time.Second, 10*time.Millisecond)
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	require.Len(t, fake.orders, 1)
+	assert.Equal(t, "AAPL_US_EQ", fake.orders[0]["ticker"])
+	assert.Equal(t, -1.0, fake.orders[0]["quantity"])
+}
+
+func TestDashRespectsRateLimits(t *testing.T) {
+	fake := &fakeAccount{fills429: true}
+	input, stdout, quit := startDash(t, fake)
+	defer quit()
+
+	deadline := time.Now().Add(1500 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		input.Write([]byte("r"))
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	// positions allow 1 request per second, orders 1 per 5 seconds
+	assert.LessOrEqual(t, fake.count("GET /api/v0/equity/portfolio"), 2)
+	assert.Equal(t, 1, fake.count("GET /api/v0/equity/orders"))
+	assert.Equal(t, 1, fake.count("GET /api/v0/equity/history/orders"))
+	assert.Equal(t, 1, fake.count("GET /api/v0/equity/account/info"))
+	assert.Contains(t, stdout.String(), "fills: rate limited")
+}
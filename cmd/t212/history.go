@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+)
+
+// historyFlags are the flags shared by the history commands
+type historyFlags struct {
+	ticker string
+	limit  int
+	cursor string
+	all    bool
+	max    int
+}
+
+func (a *app) history(ctx context.Context, args []string) error {
+	return subcommand(ctx, "history", args, map[string]func(context.Context, []string) error{
+		"orders":       a.historyOrders,
+		"dividends":    a.historyDividends,
+		"transactions": a.historyTransactions,
+	})
+}
+
+func (a *app) parseHistoryFlags(name string, args []string, withTicker bool) (*historyFlags, error) {
+	h := &historyFlags{}
+	flags := a.newFlags("history " + name)
+	if withTicker {
+		flags.StringVar(&h.ticker, "ticker", "", "only show items for this ticker")
+	}
+	flags.IntVar(&h.limit, "limit", 50, "items per page, at most 50")
+	flags.StringVar(&h.cursor, "cursor", "", "start from this cursor")
+	flags.BoolVar(&h.all, "all", false, "follow nextPagePath and print every item")
+	flags.IntVar(&h.max, "max", 0, "stop after this many items with -all (0 means no limit)")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if flags.NArg() != 0 {
+		return nil, &usageError{"history " + name + " [-ticker T] [-limit N] [-cursor C] [-all] [-max N]"}
+	}
+	return h, nil
+}
+
+// int64Cursor parses the cursor of the orders and dividends endpoints
+func (h *historyFlags) int64Cursor() (int64, error) {
+	if h.cursor == "" {
+		return 0, nil
+	}
+	cursor, err := parseID(h.cursor)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cursor %q", h.cursor)
+	}
+	return cursor, nil
+}
+
+func (a *app) historyOrders(ctx context.Context, args []string) error {
+	h, err := a.parseHistoryFlags("orders", args, true)
+	if err != nil {
+		return err
+	}
+	cursor, err := h.int64Cursor()
+	if err != nil {
+		return err
+	}
+	opts := &trading212.HistoryOrdersOptions{Cursor: cursor, Ticker: h.ticker, Limit: h.limit}
+
+	if !h.all {
+		page, err := a.client.GetHistoricalOrders(ctx, opts)
+		if err != nil {
+			return err
+		}
+		return printPage(a, page)
+	}
+	var items []trading212.HistoricalOrder
+	err = a.client.ForEachHistoricalOrder(ctx, opts, func(item trading212.HistoricalOrder) error {
+		items = append(items, item)
+		return h.collected(len(items))
+	})
+	if err != nil {
+		return err
+	}
+	return a.print(items)
+}
+
+func (a *app) historyDividends(ctx context.Context, args []string) error {
+	h, err := a.parseHistoryFlags("dividends", args, true)
+	if err != nil {
+		return err
+	}
+	cursor, err := h.int64Cursor()
+	if err != nil {
+		return err
+	}
+	opts := &trading212.HistoryDividendsOptions{Cursor: cursor, Ticker: h.ticker, Limit: h.limit}
+
+	if !h.all {
+		page, err := a.client.GetDividends(ctx, opts)
+		if err != nil {
+			return err
+		}
+		return printPage(a, page)
+	}
+	var items []trading212.HistoryDividendItem
+	err = a.client.ForEachDividend(ctx, opts, func(item trading212.HistoryDividendItem) error {
+		items = append(items, item)
+		return h.collected(len(items))
+	})
+	if err != nil {
+		return err
+	}
+	return a.print(items)
+}
+
+func (a *app) historyTransactions(ctx context.Context, args []string) error {
+	h, err := a.parseHistoryFlags("transactions", args, false)
+	if err != nil {
+		return err
+	}
+	opts := &trading212.HistoryTransactionsOptions{Cursor: h.cursor, Limit: h.limit}
+
+	if !h.all {
+		page, err := a.client.GetTransactions(ctx, opts)
+		if err != nil {
+			return err
+		}
+		return printPage(a, page)
+	}
+	var items []trading212.HistoryTransactionItem
+	err = a.client.ForEachTransaction(ctx, opts, func(item trading212.HistoryTransactionItem) error {
+		items = append(items, item)
+		return h.collected(len(items))
+	})
+	if err != nil {
+		return err
+	}
+	return a.print(items)
+}
+
+// collected stops paging once -max items have been gathered
+func (h *historyFlags) collected(n int) error {
+	if h.max > 0 && n >= h.max {
+		return trading212.ErrStopPaging
+	}
+	return nil
+}
+
+// printPage prints the items of a page and tells where the next one starts
+func printPage[T any](a *app, page *trading212.PaginatedResponse[T]) error {
+	if cursor, ok := page.NextCursor(); ok {
+		fmt.Fprintf(a.stderr, "more items: use -cursor %s or -all\n", cursor)
+	}
+	return a.print(page.Items)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/dividends"
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/format"
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/ledger"
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/portfolio"
+)
+
+func init() {
+	format.SetDefaultColumns(dividends.Total{}, "key", "payments", "amount", "manufactured")
+	format.SetDefaultColumns(dividends.Yield{}, "ticker", "income", "cost", "yieldOnCost")
+	format.SetDefaultColumns(dividends.Projection{}, "ticker", "quantity", "frequency", "netPerShare", "annualIncome", "lastPaidOn")
+}
+
+func (a *app) income(ctx context.Context, args []string) error {
+	return subcommand(ctx, "income", args, map[string]func(context.Context, []string) error{
+		"tickers": a.incomeReport("tickers"),
+		"periods": a.incomeReport("periods"),
+		"types":   a.incomeReport("types"),
+		"yield":   a.incomeReport("yield"),
+		"forward": a.incomeReport("forward"),
+	})
+}
+
+// incomeReport analyses the dividends stored in the ledger and prints one
+// view of the result
+func (a *app) incomeReport(view string) func(context.Context, []string) error {
+	return func(ctx context.Context, args []string) error {
+		flags := a.newFlags("income " + view)
+		dir := flags.String("dir", "", "ledger directory")
+		from := flags.String("from", "", "only count payments at or after this date")
+		to := flags.String("to", "", "only count payments before this date")
+		period := flags.String("period", "month", "periods: month, year or tax-year")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if flags.NArg() != 0 {
+			return &usageError{"income " + view + " [-dir DIR] [-from DATE] [-to DATE] [-period month|year|tax-year]"}
+		}
+
+		var start, end time.Time
+		var err error
+		if *from != "" {
+			if start, err = parseTime(*from); err != nil {
+				return err
+			}
+		}
+		if *to != "" {
+			if end, err = parseTime(*to); err != nil {
+				return err
+			}
+		}
+		l, err := a.openLedger(*dir)
+		if err != nil {
+			return err
+		}
+		analysis := dividends.New(l.Dividends(ledger.Query{}))
+
+		switch view {
+		case "tickers":
+			return a.print(analysis.Between(start, end).ByTicker())
+		case "periods":
+			p, err := dividends.ParsePeriod(*period)
+			if err != nil {
+				return err
+			}
+			return a.print(analysis.Between(start, end).ByPeriod(p))
+		case "types":
+			return a.print(analysis.Between(start, end).ByType())
+		}
+
+		// yield and forward look at the year to -to, or to today
+		if end.IsZero() {
+			end = time.Now()
+		}
+		if view == "yield" {
+			p, err := portfolio.Replay(l.Orders(ledger.Query{}), portfolio.MethodAverage)
+			if err != nil {
+				return err
+			}
+			return a.print(analysis.YieldOnCost(p.Holdings(), end))
+		}
+		positions, err := a.client.GetPositions(ctx, nil)
+		if err != nil {
+			return err
+		}
+		return a.print(analysis.Forward(positions, end))
+	}
+}
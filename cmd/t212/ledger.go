@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/ledger"
+)
+
+func (a *app) ledger(ctx context.Context, args []string) error {
+	return subcommand(ctx, "ledger", args, map[string]func(context.Context, []string) error{
+		"sync":         a.ledgerSync,
+		"orders":       a.ledgerQuery(ledger.StreamOrders),
+		"dividends":    a.ledgerQuery(ledger.StreamDividends),
+		"transactions": a.ledgerQuery(ledger.StreamTransactions),
+	})
+}
+
+func (a *app) ledgerSync(ctx context.Context, args []string) error {
+	flags := a.newFlags("ledger sync")
+	dir := flags.String("dir", "", "ledger directory (default t212/ledger/<environment> under the user config directory)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return &usageError{"ledger sync [-dir DIR]"}
+	}
+
+	l, err := a.openLedger(*dir)
+	if err != nil {
+		return err
+	}
+	result, err := l.Sync(ctx, a.client)
+	fmt.Fprintf(a.stderr, "added %d orders, %d dividends, %d transactions\n", result.Orders, result.Dividends, result.Transactions)
+	return err
+}
+
+func (a *app) ledgerQuery(stream ledger.Stream) func(context.Context, []string) error {
+	return func(ctx context.Context, args []string) error {
+		flags := a.newFlags("ledger " + string(stream))
+		dir := flags.String("dir", "", "ledger directory")
+		ticker := flags.String("ticker", "", "only show items for this ticker")
+		from := flags.String("from", "", "only show items at or after this date")
+		to := flags.String("to", "", "only show items before this date")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if flags.NArg() != 0 {
+			return &usageError{"ledger " + string(stream) + " [-dir DIR] [-ticker T] [-from DATE] [-to DATE]"}
+		}
+
+		q := ledger.Query{Ticker: *ticker}
+		var err error
+		if *from != "" {
+			if q.From, err = parseTime(*from); err != nil {
+				return err
+			}
+		}
+		if *to != "" {
+			if q.To, err = parseTime(*to); err != nil {
+				return err
+			}
+		}
+
+		l, err := a.openLedger(*dir)
+		if err != nil {
+			return err
+		}
+		switch stream {
+		case ledger.StreamOrders:
+			return a.print(l.Orders(q))
+		case ledger.StreamDividends:
+			return a.print(l.Dividends(q))
+		}
+		return a.print(l.Transactions(q))
+	}
+}
+
+// openLedger opens the ledger in dir, or in the default directory of the
+// selected environment
+func (a *app) openLedger(dir string) (*ledger.Ledger, error) {
+	dir, err := a.dataDir("ledger", dir)
+	if err != nil {
+		return nil, err
+	}
+	store, err := ledger.NewFileStore(dir)
+	if err != nil {
+		return nil, err
+	}
+	return ledger.Open(store, nil)
+}
+
+// dataDir returns dir, or the default directory of kind for the selected
+// environment under the user config directory
+func (a *app) dataDir(kind, dir string) (string, error) {
+	if dir != "" {
+		return dir, nil
+	}
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("no %s directory: use -dir", kind)
+	}
+	return filepath.Join(base, "t212", kind, environmentName(a.env)), nil
+}
+
+// environmentName returns a directory-safe name for env
+func environmentName(env trading212.Environment) string {
+	switch env {
+	case trading212.Demo:
+		return "demo"
+	case trading212.Live:
+		return "live"
+	}
+	if u, err := url.Parse(string(env)); err == nil && u.Host != "" {
+		return url.PathEscape(u.Host)
+	}
+	return "custom"
+}
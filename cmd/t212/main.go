@@ -0,0 +1,233 @@
+// Command t212 is a command-line client for the Trading 212 API.
+//
+// Usage:
+//
+//	t212 [-env demo|live] [-config file] [-yes] [-format F] [-columns C] <command> [flags] [args]
+//
+// Credentials are read from T212_API_KEY and T212_API_SECRET, falling back
+// to the config file, a JSON object with apiKey, apiSecret and environment
+// fields stored at $XDG_CONFIG_HOME/t212/config.json by default. The
+// environment defaults to demo; live must be selected explicitly with -env,
+// T212_ENV or the config file. Output is JSON unless -format selects table,
+// jsonl or csv.
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/format"
+)
+
+const usage = `usage: t212 [-env demo|live] [-config file] [-yes] [-format F] [-columns C] <command> [flags] [args]
+
+Commands:
+  account info|cash|summary
+  positions [-ticker T]
+  orders list
+  orders get ID
+  orders market [-extended] [-sell] TICKER QUANTITY
+  orders limit [-tif day|gtc] [-sell] TICKER QUANTITY LIMIT_PRICE
+  orders stop [-tif day|gtc] [-sell] TICKER QUANTITY STOP_PRICE
+  orders stop-limit [-tif day|gtc] [-sell] TICKER QUANTITY STOP_PRICE LIMIT_PRICE
+  orders cancel ID
+  history orders|dividends|transactions [-ticker T] [-limit N] [-cursor C] [-all] [-max N]
+  reports request [-from DATE] [-to DATE] [-orders] [-dividends] [-transactions] [-interest]
+  reports list
+  reports wait [-interval D] [-timeout D] ID
+  reports download [-o FILE] ID
+  instruments [-type T] [-currency C] [-search QUERY] [-limit N]
+  exchanges
+  pies list
+  pies get ID
+  pies create -name NAME -shares T1=W1,T2=W2 [-goal N] [-icon I] [-end DATE] [-dividends reinvest|cash]
+  pies update [same flags as create] ID
+  pies duplicate [-name NAME] [-icon I] ID
+  pies delete ID
+  dash [-refresh D] [-fills N]
+  ledger sync [-dir DIR]
+  ledger orders|dividends|transactions [-dir DIR] [-ticker T] [-from DATE] [-to DATE]
+  costbasis holdings|lots|disposals|reconcile [-dir DIR] [-method average|fifo]
+  cgt summary|disposals|pools [-dir DIR]... [-isa DIR]... [-year YYYY/YY]
+  income tickers|periods|types|yield|forward [-dir DIR] [-from DATE] [-to DATE] [-period month|year|tax-year]
+  performance [-valuations FILE | -snapshots DIR] [-dir DIR] [-from DATE] [-to DATE] [-contributions]
+  snapshot record [-dir DIR] [-interval D] [-once]
+  snapshot equity [-dir DIR] [-from DATE] [-to DATE]
+  snapshot position [-dir DIR] [-from DATE] [-to DATE] TICKER
+  allocation [-by holdings|type|currency|exchange|pie] [-dir DIR] [-rate CUR=RATE,...]
+
+-format is one of json (default), jsonl, table or csv. -columns selects and
+orders the output fields by JSON path, e.g. ticker,quantity or
+fill.walletImpact.netValue.
+
+Orders, cancellations and pie changes on the live environment ask for
+confirmation unless -yes is given. dash shows a live dashboard of the
+account, positions, pending orders and recent fills; it always asks before
+placing or cancelling an order. Negative numbers are parsed as flags, so
+use -sell instead of a negative quantity.
+`
+
+// errAborted is returned when the user declines a confirmation
+var errAborted = errors.New("aborted")
+
+// usageError reports a malformed command line
+type usageError struct {
+	usage string
+}
+
+func (e *usageError) Error() string {
+	return "usage: t212 " + e.usage
+}
+
+// app holds what commands need to run
+type app struct {
+	client     *trading212.Client
+	env        trading212.Environment
+	yes        bool
+	tty        bool
+	format     format.Format
+	columns    []string
+	stdin      *bufio.Reader
+	stdout     io.Writer
+	stderr     io.Writer
+	getenv     func(string) string
+	httpClient *http.Client
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	a := &app{
+		stdin:      bufio.NewReader(os.Stdin),
+		stdout:     os.Stdout,
+		stderr:     os.Stderr,
+		getenv:     os.Getenv,
+		httpClient: http.DefaultClient,
+		tty:        isTerminal(os.Stdin) && isTerminal(os.Stdout),
+	}
+	if err := a.run(ctx, os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		fmt.Fprintln(os.Stderr, "t212:", err)
+		os.Exit(1)
+	}
+}
+
+func (a *app) run(ctx context.Context, args []string) error {
+	global := flag.NewFlagSet("t212", flag.ContinueOnError)
+	global.SetOutput(a.stderr)
+	global.Usage = func() { fmt.Fprint(a.stderr, usage) }
+	envName := global.String("env", "", "environment: demo, live or a base URL")
+	configPath := global.String("config", "", "config file")
+	global.BoolVar(&a.yes, "yes", false, "do not ask for confirmation on live")
+	formatName := global.String("format", "json", "output format: json, jsonl, table or csv")
+	columns := global.String("columns", "", "comma separated output columns")
+	if err := global.Parse(args); err != nil {
+		return err
+	}
+	var err error
+	if a.format, err = format.ParseFormat(*formatName); err != nil {
+		return err
+	}
+	if *columns != "" {
+		a.columns = strings.Split(*columns, ",")
+	}
+
+	commands := map[string]func(context.Context, []string) error{
+		"account":     a.account,
+		"positions":   a.positions,
+		"orders":      a.orders,
+		"history":     a.history,
+		"reports":     a.reports,
+		"instruments": a.instruments,
+		"exchanges":   a.exchanges,
+		"pies":        a.pies,
+		"dash":        a.dash,
+		"ledger":      a.ledger,
+		"costbasis":   a.costbasis,
+		"cgt":         a.cgt,
+		"income":      a.income,
+		"performance": a.performance,
+		"snapshot":    a.snapshot,
+		"allocation":  a.allocation,
+	}
+	rest := global.Args()
+	if len(rest) == 0 || rest[0] == "help" {
+		global.Usage()
+		return flag.ErrHelp
+	}
+	command, ok := commands[rest[0]]
+	if !ok {
+		return fmt.Errorf("unknown command %q, run t212 help", rest[0])
+	}
+
+	cfg, err := loadConfig(*configPath, *envName, a.getenv)
+	if err != nil {
+		return err
+	}
+	if a.env, err = environment(cfg.Environment); err != nil {
+		return err
+	}
+	if cfg.APIKey == "" || cfg.APISecret == "" {
+		return errors.New("missing credentials: set T212_API_KEY and T212_API_SECRET or add them to the config file")
+	}
+	a.client = trading212.NewClient(a.env, cfg.APIKey, cfg.APISecret)
+
+	return command(ctx, rest[1:])
+}
+
+// print writes v to stdout in the selected format
+func (a *app) print(v interface{}) error {
+	return format.Write(a.stdout, a.format, v, &format.Options{Columns: a.columns})
+}
+
+// confirm asks before changing anything on the live environment
+func (a *app) confirm(format string, args ...interface{}) error {
+	if a.env != trading212.Live || a.yes {
+		return nil
+	}
+	fmt.Fprintf(a.stderr, format+" on the LIVE account? [y/N] ", args...)
+	answer, _ := a.stdin.ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	}
+	return errAborted
+}
+
+// subcommand dispatches args[0] to one of handlers
+func subcommand(ctx context.Context, name string, args []string, handlers map[string]func(context.Context, []string) error) error {
+	names := make([]string, 0, len(handlers))
+	for key := range handlers {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+
+	if len(args) == 0 {
+		return &usageError{name + " " + strings.Join(names, "|")}
+	}
+	handler, ok := handlers[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown %s command %q, expected one of %s", name, args[0], strings.Join(names, ", "))
+	}
+	return handler(ctx, args[1:])
+}
+
+// newFlags returns a flag set that reports errors instead of exiting
+func (a *app) newFlags(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(a.stderr)
+	return flags
+}
@@ -0,0 +1,391 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestApp returns an app with credentials from a fake environment
+func newTestApp(stdin string, env map[string]string) (*app, *bytes.Buffer) {
+	stdout := &bytes.Buffer{}
+	if env == nil {
+		env = map[string]string{}
+	}
+	if _, ok := env["T212_CONFIG"]; !ok {
+		env["T212_CONFIG"] = ""
+	}
+	a := &app{
+		stdin:      bufio.NewReader(strings.NewReader(stdin)),
+		stdout:     stdout,
+		stderr:     io.Discard,
+		getenv:     func(key string) string { return env[key] },
+		httpClient: http.DefaultClient,
+	}
+	return a, stdout
+}
+
+func credentials() map[string]string {
+	return map[string]string{"T212_API_KEY": "key", "T212_API_SECRET": "secret"}
+}
+
+func TestAccountSummary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v0/equity/account/info":
+			w.Write([]byte(`{"id": 42, "currencyCode": "GBP"}`))
+		case "/api/v0/equity/account/cash":
+			w.Write([]byte(`{"free": 100.25, "total": 1000.5}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	a, stdout := newTestApp("", credentials())
+	require.NoError(t, a.run(context.Background(), []string{"-env", server.URL, "account", "summary"}))
+	assert.Contains(t, stdout.String(), `"currencyCode": "GBP"`)
+	assert.Contains(t, stdout.String(), `"total": 1000.5`)
+}
+
+func TestPlaceSellOrder(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v0/equity/orders/limit", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.Write([]byte(`{"id": 7, "ticker": "AAPL_US_EQ", "status": "NEW"}`))
+	}))
+	defer server.Close()
+
+	a, stdout := newTestApp("", credentials())
+	err := a.run(context.Background(), []string{"-env", server.URL, "orders", "limit", "-tif", "gtc", "-sell", "AAPL_US_EQ", "2", "150.5"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "AAPL_US_EQ", body["ticker"])
+	assert.Equal(t, -2.0, body["quantity"])
+	assert.Equal(t, 150.5, body["limitPrice"])
+	assert.Equal(t, "GOOD_TILL_CANCEL", body["timeValidity"])
+	assert.Contains(t, stdout.String(), `"id": 7`)
+}
+
+func TestLiveOrderNeedsConfirmation(t *testing.T) {
+	a, _ := newTestApp("n\n", credentials())
+	err := a.run(context.Background(), []string{"-env", "live", "orders", "market", "AAPL_US_EQ", "1"})
+	assert.ErrorIs(t, err, errAborted)
+}
+
+func TestHistoryAllFollowsPages(t *testing.T) {
+	var cursors []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v0/equity/history/dividends", r.URL.Path)
+		cursor := r.URL.Query().Get("cursor")
+		cursors = append(cursors, cursor)
+		if cursor == "" || cursor == "0" {
+			w.Write([]byte(`{"items": [{"reference": "a"}], "nextPagePath": "/api/v0/equity/history/dividends?limit=50&cursor=123"}`))
+			return
+		}
+		w.Write([]byte(`{"items": [{"reference": "b"}], "nextPagePath": null}`))
+	}))
+	defer server.Close()
+
+	a, stdout := newTestApp("", credentials())
+	require.NoError(t, a.run(context.Background(), []string{"-env", server.URL, "history", "dividends", "-all"}))
+
+	var items []map[string]interface{}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &items))
+	require.Len(t, items, 2)
+	assert.Equal(t, "a", items[0]["reference"])
+	assert.Equal(t, "b", items[1]["reference"])
+	assert.Equal(t, "123", cursors[1])
+}
+
+func TestCreatePie(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v0/equity/pies", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.Write([]byte(`{"instruments": [], "settings": {"id": 3, "name": "Tech"}}`))
+	}))
+	defer server.Close()
+
+	a, _ := newTestApp("", credentials())
+	err := a.run(context.Background(), []string{"-env", server.URL, "pies", "create",
+		"-name", "Tech", "-shares", "AAPL_US_EQ=0.6,MSFT_US_EQ=0.4", "-dividends", "reinvest", "-end", "2030-01-01"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Tech", body["name"])
+	assert.Equal(t, "REINVEST", body["dividendCashAction"])
+	assert.Equal(t, "2030-01-01T00:00:00Z", body["endDate"])
+	assert.Equal(t, map[string]interface{}{"AAPL_US_EQ": 0.6, "MSFT_US_EQ": 0.4}, body["instrumentShares"])
+}
+
+func TestParseSharesRejectsBadWeights(t *testing.T) {
+	_, err := parseShares("AAPL_US_EQ=0.5,MSFT_US_EQ=0.4")
+	assert.Error(t, err)
+	_, err = parseShares("AAPL_US_EQ")
+	assert.Error(t, err)
+}
+
+func TestReportDownload(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v0/equity/history/exports":
+			w.Write([]byte(`[{"reportId": 9, "status": "Finished", "downloadLink": "` + server.URL + `/files/report.csv"}]`))
+		case "/files/report.csv":
+			w.Write([]byte("Action,Time\nMarket buy,2024-01-02\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	output := filepath.Join(t.TempDir(), "report.csv")
+	a, _ := newTestApp("", credentials())
+	require.NoError(t, a.run(context.Background(), []string{"-env", server.URL, "reports", "download", "-o", output, "9"}))
+
+	data, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Equal(t, "Action,Time\nMarket buy,2024-01-02\n", string(data))
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"environment": "live", "apiKey": "file-key", "apiSecret": "file-secret"}`), 0o600))
+
+	env := map[string]string{"T212_API_KEY": "env-key"}
+	cfg, err := loadConfig(path, "", func(key string) string { return env[key] })
+	require.NoError(t, err)
+	assert.Equal(t, config{Environment: "live", APIKey: "env-key", APISecret: "file-secret"}, cfg)
+
+	cfg, err = loadConfig(path, "demo", func(key string) string { return env[key] })
+	require.NoError(t, err)
+	assert.Equal(t, "demo", cfg.Environment)
+
+	_, err = loadConfig(filepath.Join(t.TempDir(), "missing.json"), "", func(string) string { return "" })
+	assert.Error(t, err)
+}
+
+func TestMissingCredentials(t *testing.T) {
+	a, _ := newTestApp("", map[string]string{"T212_CONFIG": filepath.Join(t.TempDir(), "none.json")})
+	err := a.run(context.Background(), []string{"account", "info"})
+	assert.Error(t, err)
+}
+
+func TestPositionsAsCSV(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"ticker": "AAPL_US_EQ", "quantity": 2, "averagePrice": 150.5}]`))
+	}))
+	defer server.Close()
+
+	a, stdout := newTestApp("", credentials())
+	err := a.run(context.Background(), []string{"-env", server.URL, "-format", "csv", "-columns", "ticker,quantity,averagePrice", "positions"})
+	require.NoError(t, err)
+	assert.Equal(t, "ticker,quantity,averagePrice\nAAPL_US_EQ,2,150.5\n", stdout.String())
+}
+
+func TestUnknownFormat(t *testing.T) {
+	a, _ := newTestApp("", credentials())
+	err := a.run(context.Background(), []string{"-format", "xml", "positions"})
+	assert.Error(t, err)
+}
+
+func TestLedgerSyncAndQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v0/equity/history/orders":
+			w.Write([]byte(`{"items": [{"order": {"id": 1, "ticker": "AAPL_US_EQ"}, "fill": {"id": 10, "filledAt": "2024-03-01T10:00:00Z"}}, {"order": {"id": 2, "ticker": "MSFT_US_EQ"}, "fill": {"id": 11, "filledAt": "2024-02-01T10:00:00Z"}}], "nextPagePath": null}`))
+		default:
+			w.Write([]byte(`{"items": [], "nextPagePath": null}`))
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	a, _ := newTestApp("", credentials())
+	require.NoError(t, a.run(context.Background(), []string{"-env", server.URL, "ledger", "sync", "-dir", dir}))
+
+	a, stdout := newTestApp("", credentials())
+	err := a.run(context.Background(), []string{"-env", server.URL, "-format", "csv", "-columns", "order.ticker,fill.id",
+		"ledger", "orders", "-dir", dir, "-from", "2024-01-01"})
+	require.NoError(t, err)
+	assert.Equal(t, "order.ticker,fill.id\nMSFT_US_EQ,11\nAAPL_US_EQ,10\n", stdout.String())
+}
+
+func TestCostBasisReconcile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v0/equity/history/orders":
+			w.Write([]byte(`{"items": [
+				{"order": {"id": 2, "ticker": "AAPL_US_EQ", "side": "SELL"}, "fill": {"id": 12, "type": "TRADE", "quantity": -5, "price": 14, "filledAt": "2024-03-01T10:00:00Z", "walletImpact": {"currency": "GBP", "netValue": 70}}},
+				{"order": {"id": 1, "ticker": "AAPL_US_EQ", "side": "BUY"}, "fill": {"id": 11, "type": "TRADE", "quantity": 10, "price": 10, "filledAt": "2024-02-01T10:00:00Z", "walletImpact": {"currency": "GBP", "netValue": 100}}}
+			], "nextPagePath": null}`))
+		case "/api/v0/equity/portfolio":
+			w.Write([]byte(`[{"ticker": "AAPL_US_EQ", "quantity": 6, "averagePrice": 10}]`))
+		default:
+			w.Write([]byte(`{"items": [], "nextPagePath": null}`))
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	a, _ := newTestApp("", credentials())
+	require.NoError(t, a.run(context.Background(), []string{"-env", server.URL, "ledger", "sync", "-dir", dir}))
+
+	a, stdout := newTestApp("", credentials())
+	require.NoError(t, a.run(context.Background(), []string{"-env", server.URL, "-format", "csv", "costbasis", "holdings", "-dir", dir}))
+	assert.Equal(t, "ticker,quantity,averagePrice,cost,realizedPL,currency\nAAPL_US_EQ,5,10,50,20,GBP\n", stdout.String())
+
+	a, stdout = newTestApp("", credentials())
+	require.NoError(t, a.run(context.Background(), []string{"-env", server.URL, "-format", "csv", "costbasis", "reconcile", "-dir", dir}))
+	assert.Equal(t, "ticker,field,position,computed\nAAPL_US_EQ,quantity,6,5\n", stdout.String())
+}
+
+func TestCGTSummary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v0/equity/history/orders":
+			w.Write([]byte(`{"items": [
+				{"order": {"id": 2, "ticker": "VOD_EQ", "side": "SELL"}, "fill": {"id": 12, "type": "TRADE", "quantity": -5, "price": 14, "filledAt": "2024-05-01T10:00:00Z", "walletImpact": {"currency": "GBP", "taxes": [{"name": "COMMISSION", "quantity": -1, "currency": "GBP"}]}}},
+				{"order": {"id": 1, "ticker": "VOD_EQ", "side": "BUY"}, "fill": {"id": 11, "type": "TRADE", "quantity": 10, "price": 10, "filledAt": "2024-02-01T10:00:00Z", "walletImpact": {"currency": "GBP"}}}
+			], "nextPagePath": null}`))
+		default:
+			w.Write([]byte(`{"items": [], "nextPagePath": null}`))
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	a, _ := newTestApp("", credentials())
+	require.NoError(t, a.run(context.Background(), []string{"-env", server.URL, "ledger", "sync", "-dir", dir}))
+
+	a, stdout := newTestApp("", credentials())
+	require.NoError(t, a.run(context.Background(), []string{"-env", server.URL, "-format", "csv", "cgt", "summary", "-dir", dir, "-year", "2024/25"}))
+	assert.Equal(t, "taxYear,disposals,proceeds,allowableCosts,gains,losses,netGain\n2024/25,1,70,51,19,0,19\n", stdout.String())
+}
+
+func TestIncomeByTaxYear(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v0/equity/history/dividends":
+			w.Write([]byte(`{"items": [
+				{"reference": "d3", "ticker": "VOD_EQ", "type": "ORDINARY_MANUFACTURED_PAYMENT", "amount": 1.5, "quantity": 100, "paidOn": "2024-08-01T10:00:00Z"},
+				{"reference": "d2", "ticker": "VOD_EQ", "type": "ORDINARY", "amount": 2, "quantity": 100, "paidOn": "2024-04-10T10:00:00Z"},
+				{"reference": "d1", "ticker": "VOD_EQ", "type": "ORDINARY", "amount": 3, "quantity": 100, "paidOn": "2024-02-01T10:00:00Z"}
+			], "nextPagePath": null}`))
+		default:
+			w.Write([]byte(`{"items": [], "nextPagePath": null}`))
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	a, _ := newTestApp("", credentials())
+	require.NoError(t, a.run(context.Background(), []string{"-env", server.URL, "ledger", "sync", "-dir", dir}))
+
+	a, stdout := newTestApp("", credentials())
+	require.NoError(t, a.run(context.Background(), []string{"-env", server.URL, "-format", "csv", "income", "periods", "-dir", dir, "-period", "tax-year"}))
+	assert.Equal(t, "key,payments,amount,manufactured\n2023/24,1,3,0\n2024/25,2,3.5,1.5\n", stdout.String())
+}
+
+func TestPerformanceReport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v0/equity/history/transactions":
+			w.Write([]byte(`{"items": [{"reference": "t1", "type": "DEPOSIT", "amount": 1000, "dateTime": "2024-01-11T00:00:00Z"}], "nextPagePath": null}`))
+		default:
+			w.Write([]byte(`{"items": [], "nextPagePath": null}`))
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	valuations := filepath.Join(dir, "valuations.jsonl")
+	require.NoError(t, os.WriteFile(valuations, []byte(`{"time": "2024-01-01T00:00:00Z", "value": "1000"}
+{"time": "2024-01-11T00:00:00Z", "value": "2100"}
+{"time": "2024-01-21T00:00:00Z", "value": "2310"}
+`), 0o600))
+
+	a, _ := newTestApp("", credentials())
+	require.NoError(t, a.run(context.Background(), []string{"-env", server.URL, "ledger", "sync", "-dir", dir}))
+
+	a, stdout := newTestApp("", credentials())
+	require.NoError(t, a.run(context.Background(), []string{"-env", server.URL, "-format", "csv", "-columns", "netDeposits,gain,twr",
+		"performance", "-dir", dir, "-valuations", valuations}))
+	assert.Regexp(t, `^netDeposits,gain,twr\n1000,310,0\.2100000`, stdout.String())
+}
+
+func TestSnapshotRecordAndEquity(t *testing.T) {
+	total := 1000
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v0/equity/account/info":
+			w.Write([]byte(`{"currencyCode": "GBP", "id": 1}`))
+		case "/api/v0/equity/account/cash":
+			fmt.Fprintf(w, `{"total": %d, "free": 100}`, total)
+		case "/api/v0/equity/portfolio":
+			w.Write([]byte(`[{"ticker": "AAPL_US_EQ", "quantity": 2, "currentPrice": 150}]`))
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	for i := 0; i < 2; i++ {
+		a, _ := newTestApp("", credentials())
+		require.NoError(t, a.run(context.Background(), []string{"-env", server.URL, "snapshot", "record", "-once", "-dir", dir}))
+		total += 100
+	}
+
+	a, stdout := newTestApp("", credentials())
+	require.NoError(t, a.run(context.Background(), []string{"-env", server.URL, "-format", "csv", "-columns", "total,free",
+		"snapshot", "equity", "-dir", dir}))
+	assert.Equal(t, "total,free\n1000,100\n1100,100\n", stdout.String())
+
+	a, stdout = newTestApp("", credentials())
+	require.NoError(t, a.run(context.Background(), []string{"-env", server.URL, "-format", "csv", "-columns", "quantity,value",
+		"snapshot", "position", "-dir", dir, "AAPL_US_EQ"}))
+	assert.Equal(t, "quantity,value\n2,300\n2,300\n", stdout.String())
+}
+
+func TestAllocationByCurrency(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v0/equity/account/info":
+			w.Write([]byte(`{"currencyCode": "GBP", "id": 1}`))
+		case "/api/v0/equity/metadata/instruments":
+			w.Write([]byte(`[
+				{"ticker": "AAPL_US_EQ", "type": "STOCK", "currencyCode": "USD", "workingScheduleId": 1},
+				{"ticker": "VUSAl_EQ", "type": "ETF", "currencyCode": "GBX", "workingScheduleId": 2}
+			]`))
+		case "/api/v0/equity/metadata/exchanges":
+			w.Write([]byte(`[{"id": 1, "name": "NASDAQ", "workingSchedules": [{"id": 1}]}]`))
+		case "/api/v0/equity/portfolio":
+			w.Write([]byte(`[
+				{"ticker": "AAPL_US_EQ", "quantity": 4, "currentPrice": 400},
+				{"ticker": "VUSAl_EQ", "quantity": 10, "currentPrice": 8000}
+			]`))
+		default:
+			w.Write([]byte(`{"items": [], "nextPagePath": null}`))
+		}
+	}))
+	defer server.Close()
+
+	a, stdout := newTestApp("", credentials())
+	require.NoError(t, a.run(context.Background(), []string{"-env", server.URL, "-format", "csv", "-columns", "key,value,weight",
+		"allocation", "-by", "currency", "-dir", t.TempDir(), "-rate", "USD=0.75"}))
+	assert.Equal(t, "key,value,weight\nUSD,1200,0.6\nGBX,800,0.4\n", stdout.String())
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+)
+
+func (a *app) instruments(ctx context.Context, args []string) error {
+	flags := a.newFlags("instruments")
+	instrumentType := flags.String("type", "", "only show instruments of this type, e.g. STOCK or ETF")
+	currency := flags.String("currency", "", "only show instruments traded in this currency")
+	search := flags.String("search", "", "rank instruments against a free-text query")
+	limit := flags.Int("limit", 0, "show at most this many instruments (0 means all, 20 with -search)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return &usageError{"instruments [-type T] [-currency C] [-search QUERY] [-limit N]"}
+	}
+
+	all, err := a.client.GetInstruments(ctx)
+	if err != nil {
+		return err
+	}
+	catalog := trading212.NewInstrumentCatalogFrom(all)
+	filter := trading212.InstrumentFilter{Type: trading212.InstrumentType(*instrumentType), Currency: *currency}
+	instruments := catalog.Filter(filter)
+
+	if *search != "" {
+		results := trading212.NewInstrumentCatalogFrom(instruments).Search(*search, &trading212.SearchOptions{Limit: *limit})
+		instruments = make([]trading212.TradableInstrument, len(results))
+		for i, result := range results {
+			instruments[i] = result.Instrument
+		}
+	}
+	if *limit > 0 && len(instruments) > *limit {
+		instruments = instruments[:*limit]
+	}
+	return a.print(instruments)
+}
+
+func (a *app) exchanges(ctx context.Context, args []string) error {
+	if len(args) != 0 {
+		return &usageError{"exchanges"}
+	}
+	exchanges, err := a.client.GetExchanges(ctx)
+	if err != nil {
+		return err
+	}
+	return a.print(exchanges)
+}
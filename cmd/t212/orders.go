@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+)
+
+func (a *app) orders(ctx context.Context, args []string) error {
+	return subcommand(ctx, "orders", args, map[string]func(context.Context, []string) error{
+		"list":       a.ordersList,
+		"get":        a.ordersGet,
+		"market":     a.ordersMarket,
+		"limit":      a.ordersLimit,
+		"stop":       a.ordersStop,
+		"stop-limit": a.ordersStopLimit,
+		"cancel":     a.ordersCancel,
+	})
+}
+
+func (a *app) ordersList(ctx context.Context, args []string) error {
+	if len(args) != 0 {
+		return &usageError{"orders list"}
+	}
+	orders, err := a.client.GetOrders(ctx)
+	if err != nil {
+		return err
+	}
+	return a.print(orders)
+}
+
+func (a *app) ordersGet(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return &usageError{"orders get ID"}
+	}
+	id, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+	order, err := a.client.GetOrderByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	return a.print(order)
+}
+
+func (a *app) ordersMarket(ctx context.Context, args []string) error {
+	const usage = "orders market [-extended] [-sell] TICKER QUANTITY"
+	flags := a.newFlags("orders market")
+	extended := flags.Bool("extended", false, "allow execution in extended hours")
+	sell := flags.Bool("sell", false, "sell instead of buy")
+	values, err := parseOrderArgs(flags, args, 0, usage)
+	if err != nil {
+		return err
+	}
+
+	req := trading212.MarketOrderRequest{Ticker: flags.Arg(0), Quantity: signed(values[0], *sell), ExtendedHours: *extended}
+	if err := a.confirm("Place market order for %g %s", req.Quantity, req.Ticker); err != nil {
+		return err
+	}
+	order, err := a.client.PlaceMarketOrder(ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.print(order)
+}
+
+func (a *app) ordersLimit(ctx context.Context, args []string) error {
+	const usage = "orders limit [-tif day|gtc] [-sell] TICKER QUANTITY LIMIT_PRICE"
+	flags := a.newFlags("orders limit")
+	tif := flags.String("tif", "day", "time in force: day or gtc")
+	sell := flags.Bool("sell", false, "sell instead of buy")
+	values, err := parseOrderArgs(flags, args, 1, usage)
+	if err != nil {
+		return err
+	}
+	validity, err := parseValidity(*tif)
+	if err != nil {
+		return err
+	}
+
+	req := trading212.LimitOrderRequest{Ticker: flags.Arg(0), Quantity: signed(values[0], *sell), LimitPrice: values[1], TimeValidity: validity}
+	if err := a.confirm("Place limit order for %g %s at %g", req.Quantity, req.Ticker, req.LimitPrice); err != nil {
+		return err
+	}
+	order, err := a.client.PlaceLimitOrder(ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.print(order)
+}
+
+func (a *app) ordersStop(ctx context.Context, args []string) error {
+	const usage = "orders stop [-tif day|gtc] [-sell] TICKER QUANTITY STOP_PRICE"
+	flags := a.newFlags("orders stop")
+	tif := flags.String("tif", "day", "time in force: day or gtc")
+	sell := flags.Bool("sell", false, "sell instead of buy")
+	values, err := parseOrderArgs(flags, args, 1, usage)
+	if err != nil {
+		return err
+	}
+	validity, err := parseValidity(*tif)
+	if err != nil {
+		return err
+	}
+
+	req := trading212.StopOrderRequest{Ticker: flags.Arg(0), Quantity: signed(values[0], *sell), StopPrice: values[1], TimeValidity: validity}
+	if err := a.confirm("Place stop order for %g %s at %g", req.Quantity, req.Ticker, req.StopPrice); err != nil {
+		return err
+	}
+	order, err := a.client.PlaceStopOrder(ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.print(order)
+}
+
+func (a *app) ordersStopLimit(ctx context.Context, args []string) error {
+	const usage = "orders stop-limit [-tif day|gtc] [-sell] TICKER QUANTITY STOP_PRICE LIMIT_PRICE"
+	flags := a.newFlags("orders stop-limit")
+	tif := flags.String("tif", "day", "time in force: day or gtc")
+	sell := flags.Bool("sell", false, "sell instead of buy")
+	values, err := parseOrderArgs(flags, args, 2, usage)
+	if err != nil {
+		return err
+	}
+	validity, err := parseValidity(*tif)
+	if err != nil {
+		return err
+	}
+
+	req := trading212.StopLimitOrderRequest{
+		Ticker:       flags.Arg(0),
+		Quantity:     signed(values[0], *sell),
+		StopPrice:    values[1],
+		LimitPrice:   values[2],
+		TimeValidity: validity,
+	}
+	if err := a.confirm("Place stop-limit order for %g %s at %g/%g", req.Quantity, req.Ticker, req.StopPrice, req.LimitPrice); err != nil {
+		return err
+	}
+	order, err := a.client.PlaceStopLimitOrder(ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.print(order)
+}
+
+func (a *app) ordersCancel(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return &usageError{"orders cancel ID"}
+	}
+	id, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+	if err := a.confirm("Cancel order %d", id); err != nil {
+		return err
+	}
+	if err := a.client.CancelOrder(ctx, id); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stderr, "cancelled order %d\n", id)
+	return nil
+}
+
+// parseOrderArgs parses TICKER QUANTITY followed by prices numbers
+func parseOrderArgs(flags *flag.FlagSet, args []string, prices int, usage string) ([]float64, error) {
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if flags.NArg() != 2+prices {
+		return nil, &usageError{usage}
+	}
+
+	values := make([]float64, 0, 1+prices)
+	for _, arg := range flags.Args()[1:] {
+		value, err := strconv.ParseFloat(arg, 64)
+		if err != nil || value <= 0 {
+			return nil, fmt.Errorf("invalid number %q: must be positive", arg)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func parseValidity(s string) (trading212.TimeValidity, error) {
+	switch strings.ToLower(s) {
+	case "day":
+		return trading212.TimeValidityDay, nil
+	case "gtc", "good_till_cancel":
+		return trading212.TimeValidityGoodTillCancel, nil
+	}
+	return "", fmt.Errorf("invalid time in force %q, expected day or gtc", s)
+}
+
+func parseID(s string) (int64, error) {
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid ID %q", s)
+	}
+	return id, nil
+}
+
+func signed(quantity float64, sell bool) float64 {
+	if sell {
+		return -quantity
+	}
+	return quantity
+}
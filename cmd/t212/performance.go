@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/format"
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/ledger"
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/performance"
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/snapshot"
+)
+
+func init() {
+	format.SetDefaultColumns(performance.Report{}, "from", "to", "startValue", "endValue", "netDeposits", "fees", "dividends", "gain", "twr", "mwr")
+	format.SetDefaultColumns(performance.Contribution{}, "ticker", "startValue", "endValue", "netBought", "dividends", "gain", "contribution")
+}
+
+func (a *app) performance(ctx context.Context, args []string) error {
+	flags := a.newFlags("performance")
+	dir := flags.String("dir", "", "ledger directory")
+	valuations := flags.String("valuations", "", "JSON Lines file of valuations with time, value and optional holdings")
+	snapshots := flags.String("snapshots", "", "snapshot directory to take valuations from when -valuations is not given")
+	from := flags.String("from", "", "start of the period (default: the first valuation)")
+	to := flags.String("to", "", "end of the period (default: the last valuation)")
+	byInstrument := flags.Bool("contributions", false, "show the contribution of each instrument instead of the summary")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return &usageError{"performance [-valuations FILE | -snapshots DIR] [-dir DIR] [-from DATE] [-to DATE] [-contributions]"}
+	}
+
+	var start, end time.Time
+	var err error
+	if *from != "" {
+		if start, err = parseTime(*from); err != nil {
+			return err
+		}
+	}
+	if *to != "" {
+		if end, err = parseTime(*to); err != nil {
+			return err
+		}
+	}
+
+	in := performance.Input{}
+	if *valuations != "" {
+		in.Valuations, err = readValuations(*valuations)
+	} else {
+		var recorded []snapshot.Snapshot
+		recorded, err = a.loadSnapshots(*snapshots, "", "")
+		in.Valuations = snapshot.Valuations(recorded)
+	}
+	if err != nil {
+		return err
+	}
+	l, err := a.openLedger(*dir)
+	if err != nil {
+		return err
+	}
+	in.Transactions = l.Transactions(ledger.Query{})
+	in.Dividends = l.Dividends(ledger.Query{})
+	in.Orders = l.Orders(ledger.Query{})
+
+	report, err := performance.Compute(in, start, end)
+	if err != nil {
+		return err
+	}
+	if *byInstrument {
+		return a.print(report.Contributions)
+	}
+	return a.print(report)
+}
+
+// readValuations reads a JSON Lines file of performance.Valuation
+func readValuations(path string) ([]performance.Valuation, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open valuations: %w", err)
+	}
+	defer f.Close()
+
+	var valuations []performance.Valuation
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var v performance.Valuation
+		if err := json.Unmarshal(scanner.Bytes(), &v); err != nil {
+			return nil, fmt.Errorf("failed to parse valuation on line %d: %w", line, err)
+		}
+		valuations = append(valuations, v)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read valuations: %w", err)
+	}
+	return valuations, nil
+}
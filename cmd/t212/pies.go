@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+)
+
+const pieFlagsUsage = "-name NAME -shares T1=W1,T2=W2 [-goal N] [-icon I] [-end DATE] [-dividends reinvest|cash]"
+
+func (a *app) pies(ctx context.Context, args []string) error {
+	return subcommand(ctx, "pies", args, map[string]func(context.Context, []string) error{
+		"list":      a.piesList,
+		"get":       a.piesGet,
+		"create":    a.piesCreate,
+		"update":    a.piesUpdate,
+		"duplicate": a.piesDuplicate,
+		"delete":    a.piesDelete,
+	})
+}
+
+func (a *app) piesList(ctx context.Context, args []string) error {
+	if len(args) != 0 {
+		return &usageError{"pies list"}
+	}
+	pies, err := a.client.GetPies(ctx)
+	if err != nil {
+		return err
+	}
+	return a.print(pies)
+}
+
+func (a *app) piesGet(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return &usageError{"pies get ID"}
+	}
+	id, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+	pie, err := a.client.GetPie(ctx, id)
+	if err != nil {
+		return err
+	}
+	return a.print(pie)
+}
+
+func (a *app) piesCreate(ctx context.Context, args []string) error {
+	flags := a.newFlags("pies create")
+	parse := pieRequestFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return &usageError{"pies create " + pieFlagsUsage}
+	}
+	req, err := parse()
+	if err != nil {
+		return err
+	}
+	if req.Name == "" || len(req.InstrumentShares) == 0 {
+		return &usageError{"pies create " + pieFlagsUsage}
+	}
+
+	if err := a.confirm("Create pie %q", req.Name); err != nil {
+		return err
+	}
+	pie, err := a.client.CreatePie(ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.print(pie)
+}
+
+func (a *app) piesUpdate(ctx context.Context, args []string) error {
+	flags := a.newFlags("pies update")
+	parse := pieRequestFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return &usageError{"pies update " + pieFlagsUsage + " ID"}
+	}
+	id, err := parseID(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	req, err := parse()
+	if err != nil {
+		return err
+	}
+
+	if err := a.confirm("Update pie %d", id); err != nil {
+		return err
+	}
+	pie, err := a.client.UpdatePie(ctx, id, req)
+	if err != nil {
+		return err
+	}
+	return a.print(pie)
+}
+
+func (a *app) piesDuplicate(ctx context.Context, args []string) error {
+	flags := a.newFlags("pies duplicate")
+	name := flags.String("name", "", "name of the copy")
+	icon := flags.String("icon", "", "icon of the copy")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return &usageError{"pies duplicate [-name NAME] [-icon I] ID"}
+	}
+	id, err := parseID(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if err := a.confirm("Duplicate pie %d", id); err != nil {
+		return err
+	}
+	pie, err := a.client.DuplicatePie(ctx, id, trading212.DuplicatePieRequest{Name: *name, Icon: *icon})
+	if err != nil {
+		return err
+	}
+	return a.print(pie)
+}
+
+func (a *app) piesDelete(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return &usageError{"pies delete ID"}
+	}
+	id, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+	if err := a.confirm("Delete pie %d", id); err != nil {
+		return err
+	}
+	if err := a.client.DeletePie(ctx, id); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stderr, "deleted pie %d\n", id)
+	return nil
+}
+
+// pieRequestFlags registers the create and update flags on flags and returns
+// a function that builds the request once they are parsed
+func pieRequestFlags(flags *flag.FlagSet) func() (trading212.PieRequest, error) {
+	name := flags.String("name", "", "pie name")
+	shares := flags.String("shares", "", "target weights as TICKER=WEIGHT pairs separated by commas")
+	goal := flags.Float64("goal", 0, "goal value in account currency")
+	icon := flags.String("icon", "", "pie icon")
+	end := flags.String("end", "", "end date, YYYY-MM-DD or RFC 3339")
+	dividends := flags.String("dividends", "", "what to do with dividends: reinvest or cash")
+
+	return func() (trading212.PieRequest, error) {
+		req := trading212.PieRequest{Name: *name, Goal: *goal, Icon: *icon}
+		if *shares != "" {
+			parsed, err := parseShares(*shares)
+			if err != nil {
+				return req, err
+			}
+			req.InstrumentShares = parsed
+		}
+		if *end != "" {
+			t, err := parseTime(*end)
+			if err != nil {
+				return req, err
+			}
+			req.EndDate = &t
+		}
+		switch strings.ToLower(*dividends) {
+		case "":
+		case "reinvest":
+			req.DividendCashAction = trading212.DividendCashActionReinvest
+		case "cash":
+			req.DividendCashAction = trading212.DividendCashActionToAccountCash
+		default:
+			return req, fmt.Errorf("invalid dividend action %q, expected reinvest or cash", *dividends)
+		}
+		return req, nil
+	}
+}
+
+// parseShares parses "AAPL_US_EQ=0.6,MSFT_US_EQ=0.4"
+func parseShares(s string) (map[string]float64, error) {
+	shares := make(map[string]float64)
+	total := 0.0
+	for _, pair := range strings.Split(s, ",") {
+		ticker, weight, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || ticker == "" {
+			return nil, fmt.Errorf("invalid share %q, expected TICKER=WEIGHT", pair)
+		}
+		value, err := strconv.ParseFloat(weight, 64)
+		if err != nil || value <= 0 {
+			return nil, fmt.Errorf("invalid weight %q for %s", weight, ticker)
+		}
+		shares[ticker] = value
+		total += value
+	}
+	if total < 0.9999 || total > 1.0001 {
+		return nil, fmt.Errorf("share weights sum to %g, expected 1", total)
+	}
+	return shares, nil
+}
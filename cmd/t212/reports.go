@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+)
+
+func (a *app) reports(ctx context.Context, args []string) error {
+	return subcommand(ctx, "reports", args, map[string]func(context.Context, []string) error{
+		"request":  a.reportsRequest,
+		"list":     a.reportsList,
+		"wait":     a.reportsWait,
+		"download": a.reportsDownload,
+	})
+}
+
+func (a *app) reportsRequest(ctx context.Context, args []string) error {
+	flags := a.newFlags("reports request")
+	from := flags.String("from", "", "start date, YYYY-MM-DD or RFC 3339 (default one year ago)")
+	to := flags.String("to", "", "end date, YYYY-MM-DD or RFC 3339 (default now)")
+	orders := flags.Bool("orders", true, "include orders")
+	dividends := flags.Bool("dividends", true, "include dividends")
+	transactions := flags.Bool("transactions", true, "include transactions")
+	interest := flags.Bool("interest", true, "include interest")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return &usageError{"reports request [-from DATE] [-to DATE] [-orders] [-dividends] [-transactions] [-interest]"}
+	}
+
+	req := trading212.PublicReportRequest{
+		DataIncluded: trading212.ReportDataIncluded{
+			IncludeDividends:    *dividends,
+			IncludeInterest:     *interest,
+			IncludeOrders:       *orders,
+			IncludeTransactions: *transactions,
+		},
+		TimeTo: time.Now().UTC(),
+	}
+	req.TimeFrom = req.TimeTo.AddDate(-1, 0, 0)
+	var err error
+	if *from != "" {
+		if req.TimeFrom, err = parseTime(*from); err != nil {
+			return err
+		}
+	}
+	if *to != "" {
+		if req.TimeTo, err = parseTime(*to); err != nil {
+			return err
+		}
+	}
+
+	report, err := a.client.RequestReport(ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.print(report)
+}
+
+func (a *app) reportsList(ctx context.Context, args []string) error {
+	if len(args) != 0 {
+		return &usageError{"reports list"}
+	}
+	reports, err := a.client.GetReports(ctx)
+	if err != nil {
+		return err
+	}
+	return a.print(reports)
+}
+
+func (a *app) reportsWait(ctx context.Context, args []string) error {
+	flags := a.newFlags("reports wait")
+	interval := flags.Duration("interval", time.Minute, "time between status checks; the endpoint allows 1 request per minute")
+	timeout := flags.Duration("timeout", 30*time.Minute, "give up after this long")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return &usageError{"reports wait [-interval D] [-timeout D] ID"}
+	}
+	id, err := parseID(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	report, err := a.waitForReport(ctx, id, *interval, *timeout)
+	if err != nil {
+		return err
+	}
+	return a.print(report)
+}
+
+// waitForReport polls the report list until the report has finished
+func (a *app) waitForReport(ctx context.Context, id int64, interval, timeout time.Duration) (*trading212.ReportResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		reports, err := a.client.GetReports(ctx)
+		if err != nil {
+			return nil, err
+		}
+		report, err := findReport(reports, id)
+		if err != nil {
+			return nil, err
+		}
+		switch report.Status {
+		case trading212.ReportStatusFinished:
+			return report, nil
+		case trading212.ReportStatusFailed, trading212.ReportStatusCanceled:
+			return nil, fmt.Errorf("report %d %s", id, report.Status)
+		}
+		fmt.Fprintf(a.stderr, "report %d is %s\n", id, report.Status)
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for report %d: %w", id, ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (a *app) reportsDownload(ctx context.Context, args []string) error {
+	flags := a.newFlags("reports download")
+	output := flags.String("o", "", "output file (default stdout)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return &usageError{"reports download [-o FILE] ID"}
+	}
+	id, err := parseID(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	reports, err := a.client.GetReports(ctx)
+	if err != nil {
+		return err
+	}
+	report, err := findReport(reports, id)
+	if err != nil {
+		return err
+	}
+	if report.DownloadLink == nil || *report.DownloadLink == "" {
+		return fmt.Errorf("report %d has no download link yet (status %s)", id, report.Status)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, *report.DownloadLink, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create download request: %w", err)
+	}
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download report: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download report: status %d", resp.StatusCode)
+	}
+
+	if *output == "" {
+		if _, err := io.Copy(a.stdout, resp.Body); err != nil {
+			return fmt.Errorf("failed to download report: %w", err)
+		}
+		return nil
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to download report: %w", err)
+	}
+	return file.Close()
+}
+
+func findReport(reports []trading212.ReportResponse, id int64) (*trading212.ReportResponse, error) {
+	for i := range reports {
+		if reports[i].ReportID == id {
+			return &reports[i], nil
+		}
+	}
+	return nil, fmt.Errorf("report %d not found", id)
+}
+
+// parseTime accepts a date (YYYY-MM-DD, midnight UTC) or an RFC 3339 time
+func parseTime(s string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, errors.New("invalid time " + s + ", expected YYYY-MM-DD or RFC 3339")
+	}
+	return t, nil
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/format"
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/snapshot"
+)
+
+func init() {
+	format.SetDefaultColumns(snapshot.Snapshot{}, "time", "currency", "cash.total", "cash.free", "cash.invested", "cash.result")
+}
+
+func (a *app) snapshot(ctx context.Context, args []string) error {
+	return subcommand(ctx, "snapshot", args, map[string]func(context.Context, []string) error{
+		"record":   a.snapshotRecord,
+		"equity":   a.snapshotQuery("equity"),
+		"position": a.snapshotQuery("position"),
+	})
+}
+
+func (a *app) snapshotRecord(ctx context.Context, args []string) error {
+	flags := a.newFlags("snapshot record")
+	dir := flags.String("dir", "", "snapshot directory (default t212/snapshots/<environment> under the user config directory)")
+	interval := flags.Duration("interval", 15*time.Minute, fmt.Sprintf("time between snapshots, at least %s", snapshot.MinInterval))
+	once := flags.Bool("once", false, "take one snapshot and exit")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return &usageError{"snapshot record [-dir DIR] [-interval D] [-once]"}
+	}
+
+	store, err := a.openSnapshots(*dir)
+	if err != nil {
+		return err
+	}
+	if *once {
+		s, err := snapshot.NewRecorder(a.client, store, nil).Record(ctx)
+		if err != nil {
+			return err
+		}
+		return a.print(s)
+	}
+
+	recorder := snapshot.NewRecorder(a.client, store, &snapshot.Options{
+		Interval: *interval,
+		OnError: func(err error) {
+			fmt.Fprintln(a.stderr, "snapshot failed:", err)
+		},
+	})
+	err = recorder.Run(ctx)
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+func (a *app) snapshotQuery(view string) func(context.Context, []string) error {
+	return func(ctx context.Context, args []string) error {
+		flags := a.newFlags("snapshot " + view)
+		dir := flags.String("dir", "", "snapshot directory")
+		from := flags.String("from", "", "only show snapshots at or after this date")
+		to := flags.String("to", "", "only show snapshots before this date")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		usage, want := "snapshot equity [-dir DIR] [-from DATE] [-to DATE]", 0
+		if view == "position" {
+			usage, want = "snapshot position [-dir DIR] [-from DATE] [-to DATE] TICKER", 1
+		}
+		if flags.NArg() != want {
+			return &usageError{usage}
+		}
+
+		snapshots, err := a.loadSnapshots(*dir, *from, *to)
+		if err != nil {
+			return err
+		}
+		if view == "equity" {
+			return a.print(snapshot.EquityCurve(snapshots))
+		}
+		return a.print(snapshot.PositionSeries(snapshots, flags.Arg(0)))
+	}
+}
+
+// loadSnapshots reads the snapshots between two optional dates
+func (a *app) loadSnapshots(dir, from, to string) ([]snapshot.Snapshot, error) {
+	var start, end time.Time
+	var err error
+	if from != "" {
+		if start, err = parseTime(from); err != nil {
+			return nil, err
+		}
+	}
+	if to != "" {
+		if end, err = parseTime(to); err != nil {
+			return nil, err
+		}
+	}
+	store, err := a.openSnapshots(dir)
+	if err != nil {
+		return nil, err
+	}
+	return store.Load(start, end)
+}
+
+// openSnapshots opens the snapshot store in dir, or in the default
+// directory of the selected environment
+func (a *app) openSnapshots(dir string) (*snapshot.FileStore, error) {
+	dir, err := a.dataDir("snapshots", dir)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.NewFileStore(dir)
+}
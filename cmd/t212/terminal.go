@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// enterTerminal puts the terminal in raw mode on the alternate screen so the
+// dashboard gets single key presses, and returns a function that restores
+// it. Without a terminal, or where stty is not available, keys are read a
+// line at a time instead.
+func (a *app) enterTerminal() func() {
+	if !a.tty {
+		return func() {}
+	}
+	state, err := stty("-g")
+	if err != nil {
+		return func() {}
+	}
+	if _, err := stty("raw", "-echo"); err != nil {
+		return func() {}
+	}
+	fmt.Fprint(a.stdout, "\x1b[?1049h\x1b[?25l")
+
+	return func() {
+		fmt.Fprint(a.stdout, "\x1b[?25h\x1b[?1049l")
+		stty(strings.TrimSpace(state))
+	}
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+// isTerminal reports whether f is a character device such as a terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
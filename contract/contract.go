@@ -0,0 +1,473 @@
+// Package contract validates JSON bodies against the schemas in api.yaml. It
+// is meant for tests: check responses from fake servers, recorded fixtures or
+// live runs, and check that the request bodies the SDK sends match the spec.
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/internal/openapi"
+)
+
+// Options represents options for a Validator
+type Options struct {
+	// AllowNull accepts null for any property. The spec marks nothing as
+	// nullable although the API returns null for absent optional values.
+	AllowNull bool
+	// DisallowAdditionalProperties reports object properties the spec does
+	// not define
+	DisallowAdditionalProperties bool
+}
+
+// Validator validates JSON documents against a spec
+type Validator struct {
+	doc  *openapi.Document
+	opts Options
+}
+
+// Issue represents a single validation failure
+type Issue struct {
+	// Pointer is the JSON pointer of the offending value, "" for the root
+	Pointer string
+	Message string
+}
+
+// ValidationError represents every issue found in a document
+type ValidationError struct {
+	// Subject names what was validated, e.g. "POST /api/v0/equity/orders/market request"
+	Subject string
+	Issues  []Issue
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		pointer := issue.Pointer
+		if pointer == "" {
+			pointer = "/"
+		}
+		lines[i] = pointer + ": " + issue.Message
+	}
+	return fmt.Sprintf("%s does not match the spec: %s", e.Subject, strings.Join(lines, "; "))
+}
+
+// Load creates a Validator for the spec at path
+func Load(path string, opts *Options) (*Validator, error) {
+	doc, err := openapi.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return newValidator(doc, opts), nil
+}
+
+// Parse creates a Validator for a spec held in memory
+func Parse(spec []byte, opts *Options) (*Validator, error) {
+	doc, err := openapi.Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+	return newValidator(doc, opts), nil
+}
+
+func newValidator(doc *openapi.Document, opts *Options) *Validator {
+	v := &Validator{doc: doc}
+	if opts != nil {
+		v.opts = *opts
+	}
+	return v
+}
+
+// ValidateSchema validates body against the named component schema, e.g.
+// MarketRequest or Order
+func (v *Validator) ValidateSchema(name string, body []byte) error {
+	schema := v.doc.Schema(name)
+	if schema == nil {
+		return fmt.Errorf("schema %s is not in the spec", name)
+	}
+	return v.validate(name, schema, body)
+}
+
+// ValidateRequest validates a request body against the operation matching
+// method and the concrete path, e.g. POST /api/v0/equity/orders/market
+func (v *Validator) ValidateRequest(method, path string, body []byte) error {
+	operation, err := v.operation(method, path)
+	if err != nil {
+		return err
+	}
+	subject := operation.Method + " " + operation.Path + " request"
+
+	schema := operation.RequestSchema()
+	if schema == nil {
+		if len(bytes.TrimSpace(body)) > 0 {
+			return &ValidationError{Subject: subject, Issues: []Issue{{Message: "operation takes no request body"}}}
+		}
+		return nil
+	}
+	return v.validate(subject, schema, body)
+}
+
+// ValidateResponse validates a response body against the operation matching
+// method and the concrete path for the given status code
+func (v *Validator) ValidateResponse(method, path string, status int, body []byte) error {
+	operation, err := v.operation(method, path)
+	if err != nil {
+		return err
+	}
+	subject := fmt.Sprintf("%s %s %d response", operation.Method, operation.Path, status)
+
+	schema, ok := operation.ResponseSchema(status)
+	if !ok {
+		return &ValidationError{Subject: subject, Issues: []Issue{{Message: "status is not documented"}}}
+	}
+	if schema == nil {
+		return nil
+	}
+	return v.validate(subject, schema, body)
+}
+
+// operation finds the operation for a concrete path. Literal path segments
+// win over parameters, so /orders/market matches before /orders/{id}.
+func (v *Validator) operation(method, path string) (*openapi.Operation, error) {
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	var best *openapi.Operation
+	bestParams := -1
+	for _, operation := range v.doc.Operations() {
+		if operation.Method != strings.ToUpper(method) {
+			continue
+		}
+		params, ok := matchPath(strings.Split(strings.Trim(operation.Path, "/"), "/"), segments)
+		if ok && (best == nil || params < bestParams) {
+			best, bestParams = operation, params
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("no operation in the spec matches %s %s", strings.ToUpper(method), path)
+	}
+	return best, nil
+}
+
+// matchPath matches template segments against concrete ones and returns the
+// number of parameters used
+func matchPath(template, segments []string) (int, bool) {
+	if len(template) != len(segments) {
+		return 0, false
+	}
+	params := 0
+	for i, segment := range template {
+		switch {
+		case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}"):
+			params++
+		case segment != segments[i]:
+			return 0, false
+		}
+	}
+	return params, true
+}
+
+func (v *Validator) validate(subject string, schema *openapi.Schema, body []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return &ValidationError{Subject: subject, Issues: []Issue{{Message: "invalid JSON: " + err.Error()}}}
+	}
+
+	var issues []Issue
+	v.walk(schema, value, "", &issues)
+	if len(issues) > 0 {
+		return &ValidationError{Subject: subject, Issues: issues}
+	}
+	return nil
+}
+
+// walk validates value against schema and appends what it finds to issues
+func (v *Validator) walk(schema *openapi.Schema, value interface{}, pointer string, issues *[]Issue) {
+	report := func(format string, args ...interface{}) {
+		*issues = append(*issues, Issue{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+	}
+
+	schema, err := v.doc.Resolve(schema)
+	if err != nil {
+		report("%v", err)
+		return
+	}
+	if schema == nil {
+		return
+	}
+	if value == nil {
+		if !schema.Nullable && !v.opts.AllowNull {
+			report("null is not allowed")
+		}
+		return
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			report("expected object, got %s", kindOf(value))
+			return
+		}
+		for _, name := range schema.Required {
+			if _, ok := object[name]; !ok {
+				report("missing required property %q", name)
+			}
+		}
+		for _, name := range sortedKeys(object) {
+			child := pointer + "/" + escapePointer(name)
+			if property, ok := schema.Properties[name]; ok {
+				v.walk(property, object[name], child, issues)
+				continue
+			}
+			switch {
+			case schema.AdditionalProperties != nil:
+				v.walk(schema.AdditionalProperties, object[name], child, issues)
+			case v.opts.DisallowAdditionalProperties && len(schema.Properties) > 0:
+				*issues = append(*issues, Issue{Pointer: child, Message: "property is not in the spec"})
+			}
+		}
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			report("expected array, got %s", kindOf(value))
+			return
+		}
+		for i, item := range array {
+			v.walk(schema.Items, item, pointer+"/"+strconv.Itoa(i), issues)
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			report("expected string, got %s", kindOf(value))
+			return
+		}
+		if len(schema.Enum) > 0 && !contains(schema.Enum, s) {
+			report("%q is not one of %s", s, strings.Join(schema.Enum, ", "))
+		}
+		if schema.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+				report("%q is not an RFC 3339 date-time", s)
+			}
+		}
+		if n := len([]rune(s)); schema.MinLength != nil && n < *schema.MinLength {
+			report("length %d is below the minimum %d", n, *schema.MinLength)
+		} else if schema.MaxLength != nil && n > *schema.MaxLength {
+			report("length %d is above the maximum %d", n, *schema.MaxLength)
+		}
+	case "number", "integer":
+		number, ok := value.(json.Number)
+		if !ok {
+			report("expected %s, got %s", schema.Type, kindOf(value))
+			return
+		}
+		if schema.Type == "integer" {
+			if _, err := number.Int64(); err != nil {
+				report("%s is not an integer", number)
+				return
+			}
+		}
+		f, _ := number.Float64()
+		if schema.Minimum != nil && f < *schema.Minimum {
+			report("%s is below the minimum %v", number, *schema.Minimum)
+		}
+		if schema.Maximum != nil && f > *schema.Maximum {
+			report("%s is above the maximum %v", number, *schema.Maximum)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			report("expected boolean, got %s", kindOf(value))
+		}
+	}
+}
+
+// UndecodedFields returns the JSON pointers of object properties in body that
+// have no field to decode into in the Go value v, revealing data the SDK
+// silently drops
+func UndecodedFields(body []byte, v interface{}) ([]string, error) {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return nil, fmt.Errorf("failed to decode body: %w", err)
+	}
+	var missing []string
+	undecoded(value, reflect.TypeOf(v), "", &missing)
+	sort.Strings(missing)
+	return missing, nil
+}
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+func undecoded(value interface{}, t reflect.Type, pointer string, missing *[]string) {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || reflect.PtrTo(t).Implements(unmarshalerType) {
+		return
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		switch t.Kind() {
+		case reflect.Map:
+			for key, item := range value {
+				undecoded(item, t.Elem(), pointer+"/"+escapePointer(key), missing)
+			}
+		case reflect.Struct:
+			fields := structFields(t)
+			for key, item := range value {
+				child := pointer + "/" + escapePointer(key)
+				field, ok := fields[strings.ToLower(key)]
+				if !ok {
+					*missing = append(*missing, child)
+					continue
+				}
+				undecoded(item, field, child, missing)
+			}
+		}
+	case []interface{}:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i, item := range value {
+				undecoded(item, t.Elem(), pointer+"/"+strconv.Itoa(i), missing)
+			}
+		}
+	}
+}
+
+// structFields returns the JSON field names of t, lower-cased as
+// encoding/json matches them case-insensitively
+func structFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for key, typ := range structFields(embedded) {
+					fields[key] = typ
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[strings.ToLower(name)] = field.Type
+	}
+	return fields
+}
+
+// TB is the subset of testing.TB used by Transport
+type TB interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// Transport returns an http.RoundTripper that validates every JSON request
+// and response passing through next (http.DefaultTransport when nil) and
+// reports violations to t. Operations missing from the spec are reported too.
+func (v *Validator) Transport(t TB, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{validator: v, t: t, next: next}
+}
+
+type transport struct {
+	validator *Validator
+	t         TB
+	next      http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		if err := t.validator.ValidateRequest(req.Method, req.URL.Path, body); err != nil {
+			t.t.Helper()
+			t.t.Errorf("%v", err)
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if len(bytes.TrimSpace(body)) > 0 && strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		if err := t.validator.ValidateResponse(req.Method, req.URL.Path, resp.StatusCode, body); err != nil {
+			t.t.Helper()
+			t.t.Errorf("%v", err)
+		}
+	}
+	return resp, nil
+}
+
+func kindOf(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return "null"
+}
+
+func escapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+func contains(values []string, s string) bool {
+	for _, value := range values {
+		if value == s {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,138 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validOrder = `{"id":42,"ticker":"AAPL_US_EQ","type":"LIMIT","status":"NEW","side":"BUY",
+	"strategy":"QUANTITY","quantity":1.5,"limitPrice":150.25,"filledQuantity":0,
+	"createdAt":"2026-01-02T15:04:05.000Z","timeInForce":"DAY","extendedHours":false}`
+
+// recordingTB collects the errors reported by Transport
+type recordingTB struct {
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func loadValidator(t *testing.T, opts *Options) *Validator {
+	t.Helper()
+	v, err := Load("../api.yaml", opts)
+	require.NoError(t, err)
+	return v
+}
+
+func TestValidateSchema(t *testing.T) {
+	v := loadValidator(t, nil)
+
+	assert.NoError(t, v.ValidateSchema("MarketRequest", []byte(`{"ticker":"AAPL_US_EQ","quantity":1,"extendedHours":true}`)))
+	assert.NoError(t, v.ValidateSchema("PieRequest", []byte(`{"name":"Core","instrumentShares":{"AAPL_US_EQ":0.5,"MSFT_US_EQ":0.5},"dividendCashAction":"REINVEST"}`)))
+
+	err := v.ValidateSchema("LimitRequest", []byte(`{"ticker":1,"quantity":"2","limitPrice":10,"timeValidity":"FOREVER"}`))
+	var validation *ValidationError
+	require.True(t, errors.As(err, &validation))
+	require.Len(t, validation.Issues, 3)
+	assert.Equal(t, "/quantity", validation.Issues[0].Pointer)
+	assert.Equal(t, "/ticker", validation.Issues[1].Pointer)
+	assert.Equal(t, "/timeValidity", validation.Issues[2].Pointer)
+	assert.Contains(t, err.Error(), `"FOREVER" is not one of DAY, GOOD_TILL_CANCEL`)
+
+	err = v.ValidateSchema("PieRequest", []byte(`{"instrumentShares":{"AAPL_US_EQ":"half"}}`))
+	require.True(t, errors.As(err, &validation))
+	assert.Equal(t, "/instrumentShares/AAPL_US_EQ", validation.Issues[0].Pointer)
+
+	assert.Error(t, v.ValidateSchema("Missing", []byte(`{}`)))
+	assert.Error(t, v.ValidateSchema("Order", []byte(`{`)))
+}
+
+func TestValidateResponse(t *testing.T) {
+	v := loadValidator(t, nil)
+
+	assert.NoError(t, v.ValidateResponse(http.MethodGet, "/api/v0/equity/orders/42", 200, []byte(validOrder)))
+	assert.NoError(t, v.ValidateResponse(http.MethodGet, "/api/v0/equity/orders", 200, []byte("["+validOrder+"]")))
+	assert.NoError(t, v.ValidateResponse(http.MethodDelete, "/api/v0/equity/orders/42", 200, nil))
+	assert.NoError(t, v.ValidateResponse(http.MethodGet, "/api/v0/equity/orders", 429, []byte(`{"message":"slow down"}`)))
+
+	err := v.ValidateResponse(http.MethodGet, "/api/v0/equity/orders/42", 200, []byte(`{"id":1.5,"status":"DONE","createdAt":"yesterday"}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "GET /api/v0/equity/orders/{id} 200 response")
+	assert.Contains(t, err.Error(), "/createdAt")
+	assert.Contains(t, err.Error(), "1.5 is not an integer")
+	assert.Contains(t, err.Error(), `"DONE" is not one of`)
+
+	assert.Error(t, v.ValidateResponse(http.MethodGet, "/api/v0/equity/orders/42", 500, []byte(`{}`)))
+	assert.Error(t, v.ValidateResponse(http.MethodGet, "/api/v0/equity/unknown", 200, []byte(`{}`)))
+}
+
+func TestValidate_NullAndAdditionalProperties(t *testing.T) {
+	body := []byte(`{"reportId":1,"status":"Queued","downloadLink":null,"unexpected":true}`)
+
+	strict := loadValidator(t, &Options{DisallowAdditionalProperties: true})
+	err := strict.ValidateSchema("ReportResponse", body)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "/downloadLink: null is not allowed")
+	assert.Contains(t, err.Error(), "/unexpected: property is not in the spec")
+
+	lenient := loadValidator(t, &Options{AllowNull: true})
+	assert.NoError(t, lenient.ValidateSchema("ReportResponse", body))
+}
+
+func TestTransport_ValidatesClientTraffic(t *testing.T) {
+	response := validOrder
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	tb := &recordingTB{}
+	client := trading212.NewClient(trading212.Environment(server.URL), "key", "secret")
+	client.SetHTTPClient(&http.Client{Transport: loadValidator(t, nil).Transport(tb, nil)})
+	ctx := context.Background()
+
+	_, err := client.PlaceMarketOrder(ctx, trading212.MarketOrderRequest{Ticker: "AAPL_US_EQ", Quantity: 1})
+	require.NoError(t, err)
+	_, err = client.PlaceLimitOrder(ctx, trading212.LimitOrderRequest{Ticker: "AAPL_US_EQ", Quantity: 1, LimitPrice: 150, TimeValidity: trading212.TimeValidityDay})
+	require.NoError(t, err)
+	_, err = client.PlaceStopOrder(ctx, trading212.StopOrderRequest{Ticker: "AAPL_US_EQ", Quantity: -1, StopPrice: 140, TimeValidity: trading212.TimeValidityDay})
+	require.NoError(t, err)
+	_, err = client.PlaceStopLimitOrder(ctx, trading212.StopLimitOrderRequest{Ticker: "AAPL_US_EQ", Quantity: -1, StopPrice: 140, LimitPrice: 139, TimeValidity: trading212.TimeValidityGoodTillCancel})
+	require.NoError(t, err)
+	_, err = client.GetOrderByID(ctx, 42)
+	require.NoError(t, err)
+	assert.Empty(t, tb.errors)
+
+	response = `{"id":"42"}`
+	_, _ = client.GetOrderByID(ctx, 42)
+	require.Len(t, tb.errors, 1)
+	assert.Contains(t, tb.errors[0], "/id: expected integer, got string")
+}
+
+func TestUndecodedFields(t *testing.T) {
+	missing, err := UndecodedFields([]byte(validOrder), trading212.Order{})
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+
+	missing, err = UndecodedFields([]byte(`[{"ticker":"AAPL_US_EQ","quantity":1,"quantityInPies":0.5,
+		"walletImpact":{"currency":"GBP"}}]`), []trading212.Position{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/0/quantityInPies", "/0/walletImpact"}, missing)
+
+	missing, err = UndecodedFields([]byte(`{"items":[{"fill":{"price":1,"extra":2}}],"nextPagePath":null}`),
+		&trading212.PaginatedResponse[trading212.PreciseHistoricalOrder]{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/items/0/fill/extra"}, missing)
+}
@@ -0,0 +1,122 @@
+package dca
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/internal/jsonl"
+)
+
+// EntryStatus represents the outcome of a single plan item on a run
+type EntryStatus string
+
+const (
+	// EntryStatusPending is recorded just before an order is sent
+	EntryStatusPending EntryStatus = "PENDING"
+	EntryStatusPlaced  EntryStatus = "PLACED"
+	EntryStatusSkipped EntryStatus = "SKIPPED"
+	// EntryStatusFailed means the order was definitely not placed
+	EntryStatusFailed EntryStatus = "FAILED"
+	// EntryStatusUnknown means the order may have been placed; it is looked
+	// up in the order history before the item is sent again
+	EntryStatusUnknown EntryStatus = "UNKNOWN"
+)
+
+// Entry represents one plan item executed (or not) on a scheduled run
+type Entry struct {
+	Plan        string      `json:"plan"`
+	ScheduledAt time.Time   `json:"scheduledAt"`
+	ExecutedAt  time.Time   `json:"executedAt"`
+	Ticker      string      `json:"ticker"`
+	Amount      float64     `json:"amount"`
+	Price       float64     `json:"price,omitempty"`
+	Quantity    float64     `json:"quantity,omitempty"`
+	OrderID     int64       `json:"orderId,omitempty"`
+	Status      EntryStatus `json:"status"`
+	Reason      string      `json:"reason,omitempty"`
+}
+
+// Journal stores run entries so that runs are never executed twice
+type Journal interface {
+	Record(entry Entry) error
+	Entries(plan string) ([]Entry, error)
+}
+
+// MemoryJournal is an in-memory Journal, mainly useful for tests
+type MemoryJournal struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// NewMemoryJournal creates an empty in-memory journal
+func NewMemoryJournal() *MemoryJournal {
+	return &MemoryJournal{}
+}
+
+// Record appends an entry to the journal
+func (j *MemoryJournal) Record(entry Entry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.entries = append(j.entries, entry)
+	return nil
+}
+
+// Entries returns all entries recorded for a plan
+func (j *MemoryJournal) Entries(plan string) ([]Entry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var entries []Entry
+	for _, entry := range j.entries {
+		if entry.Plan == plan {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// FileJournal is a Journal backed by a JSON Lines file
+type FileJournal struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileJournal creates a journal that appends to the file at path
+func NewFileJournal(path string) *FileJournal {
+	return &FileJournal{path: path}
+}
+
+// Record appends an entry to the journal file and syncs it to disk
+func (j *FileJournal) Record(entry Entry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if err := jsonl.Append(j.path, entry); err != nil {
+		return fmt.Errorf("failed to write journal entry: %w", err)
+	}
+	return nil
+}
+
+// Entries returns all entries recorded for a plan
+func (j *FileJournal) Entries(plan string) ([]Entry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var entries []Entry
+	err := jsonl.Read(j.path, func(line int, data []byte) error {
+		var entry Entry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return fmt.Errorf("failed to decode journal entry on line %d: %w", line, err)
+		}
+		if entry.Plan == plan {
+			entries = append(entries, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	return entries, nil
+}
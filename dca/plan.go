@@ -0,0 +1,137 @@
+// Package dca schedules recurring investments (dollar-cost averaging) into a
+// fixed set of instruments.
+package dca
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Cadence represents how often a plan runs
+type Cadence struct {
+	Days   int
+	Months int
+}
+
+var (
+	// Daily runs a plan every day
+	Daily = Cadence{Days: 1}
+	// Weekly runs a plan every seven days
+	Weekly = Cadence{Days: 7}
+	// Fortnightly runs a plan every fourteen days
+	Fortnightly = Cadence{Days: 14}
+	// Monthly runs a plan on the same day every month
+	Monthly = Cadence{Months: 1}
+)
+
+// At returns the n-th run time counting from start. Monthly cadences are
+// clamped to the last day of shorter months rather than overflowing.
+func (c Cadence) At(start time.Time, n int) time.Time {
+	months := start.Month() + time.Month(c.Months*n)
+	day := start.Day()
+	if last := time.Date(start.Year(), months+1, 0, 0, 0, 0, 0, start.Location()).Day(); day > last {
+		day = last
+	}
+	return time.Date(start.Year(), months, day+c.Days*n,
+		start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+}
+
+// PlanItem represents a single instrument in a plan. Either Amount or Weight
+// must be set.
+type PlanItem struct {
+	Ticker string  `json:"ticker"`
+	Amount float64 `json:"amount,omitempty"`
+	Weight float64 `json:"weight,omitempty"`
+}
+
+// Plan represents a recurring investment plan
+type Plan struct {
+	// Name identifies the plan in the journal and must be stable across runs
+	Name  string     `json:"name"`
+	Items []PlanItem `json:"items"`
+	// Budget is the cash split across weighted items on every run
+	Budget  float64 `json:"budget,omitempty"`
+	Cadence Cadence `json:"cadence"`
+	// Start is the first scheduled run; later runs follow Cadence
+	Start time.Time `json:"start"`
+	// ExtendedHours is passed through to every order
+	ExtendedHours bool `json:"extendedHours"`
+	// QuantityPrecision is the number of decimal places quantities are rounded
+	// down to (0 leaves quantities unrounded)
+	QuantityPrecision int `json:"quantityPrecision,omitempty"`
+}
+
+// Validate checks that the plan is well formed
+func (p Plan) Validate() error {
+	if p.Name == "" {
+		return errors.New("plan name is required")
+	}
+	if len(p.Items) == 0 {
+		return errors.New("plan has no items")
+	}
+	if p.Cadence.Days <= 0 && p.Cadence.Months <= 0 {
+		return errors.New("plan cadence must be positive")
+	}
+	if p.Start.IsZero() {
+		return errors.New("plan start time is required")
+	}
+
+	weighted := false
+	for _, item := range p.Items {
+		if item.Ticker == "" {
+			return errors.New("plan item ticker is required")
+		}
+		if (item.Amount > 0) == (item.Weight > 0) {
+			return fmt.Errorf("plan item %s must set exactly one of amount and weight", item.Ticker)
+		}
+		if item.Weight > 0 {
+			weighted = true
+		}
+	}
+	if weighted && p.Budget <= 0 {
+		return errors.New("plan budget is required for weighted items")
+	}
+	return nil
+}
+
+// Amounts returns the cash amount to invest in each ticker on a single run
+func (p Plan) Amounts() map[string]float64 {
+	totalWeight := 0.0
+	for _, item := range p.Items {
+		totalWeight += item.Weight
+	}
+
+	amounts := make(map[string]float64, len(p.Items))
+	for _, item := range p.Items {
+		if item.Amount > 0 {
+			amounts[item.Ticker] += item.Amount
+		} else if totalWeight > 0 {
+			amounts[item.Ticker] += p.Budget * item.Weight / totalWeight
+		}
+	}
+	return amounts
+}
+
+// Schedule returns the run times from Start up to and including until
+func (p Plan) Schedule(until time.Time) []time.Time {
+	var times []time.Time
+	for n := 0; ; n++ {
+		t := p.Cadence.At(p.Start, n)
+		if t.After(until) {
+			return times
+		}
+		times = append(times, t)
+	}
+}
+
+// quantity converts a cash amount into a share quantity at price
+func (p Plan) quantity(amount, price float64) float64 {
+	quantity := amount / price
+	if p.QuantityPrecision > 0 {
+		scale := math.Pow(10, float64(p.QuantityPrecision))
+		quantity = math.Trunc(quantity*scale) / scale
+	}
+	return quantity
+}
@@ -0,0 +1,326 @@
+package dca
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+)
+
+// Client is the subset of the trading212 client used by the scheduler
+type Client interface {
+	GetAccountCash(ctx context.Context) (*trading212.AccountCash, error)
+	PlaceMarketOrderIdempotent(ctx context.Context, key string, req trading212.MarketOrderRequest, opts *trading212.IdempotencyOptions) (*trading212.SubmissionResult, error)
+	FindSubmittedOrder(ctx context.Context, req trading212.MarketOrderRequest, since time.Time, window time.Duration) (*trading212.Order, error)
+}
+
+// matchWindow is the clock skew allowed when looking for an order sent
+// before a crash
+const matchWindow = time.Minute
+
+// PriceSource returns the latest price of an instrument in account currency
+type PriceSource interface {
+	Price(ctx context.Context, ticker string) (float64, error)
+}
+
+// MarketHours reports whether an instrument can currently be traded
+type MarketHours interface {
+	IsOpen(ticker string, t time.Time) (bool, error)
+}
+
+// Scheduler executes a plan's scheduled runs, catching up on missed runs
+// using the journal to avoid placing the same order twice
+type Scheduler struct {
+	client  Client
+	plan    Plan
+	journal Journal
+	prices  PriceSource
+	hours   MarketHours
+	window  time.Duration
+
+	now   func() time.Time
+	after func(time.Duration) <-chan time.Time
+}
+
+// NewScheduler creates a scheduler for plan
+func NewScheduler(client Client, plan Plan, journal Journal, prices PriceSource) (*Scheduler, error) {
+	if err := plan.Validate(); err != nil {
+		return nil, err
+	}
+	if journal == nil {
+		return nil, errors.New("journal is required")
+	}
+	if prices == nil {
+		return nil, errors.New("price source is required")
+	}
+
+	return &Scheduler{
+		client:  client,
+		plan:    plan,
+		journal: journal,
+		prices:  prices,
+		now:     time.Now,
+		after:   time.After,
+	}, nil
+}
+
+// SetMarketHours makes the scheduler skip instruments whose market is closed;
+// skipped items are retried on the next run
+func (s *Scheduler) SetMarketHours(hours MarketHours) {
+	s.hours = hours
+}
+
+// SetCatchUpWindow limits catch-up to runs scheduled within window of now;
+// zero catches up on every missed run
+func (s *Scheduler) SetCatchUpWindow(window time.Duration) {
+	s.window = window
+}
+
+// Pending returns the scheduled runs that still have unplaced items, mapped
+// to the tickers outstanding for each run
+func (s *Scheduler) Pending() (map[time.Time][]string, error) {
+	history, err := s.history()
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	pending := make(map[time.Time][]string)
+	for _, scheduled := range s.plan.Schedule(now) {
+		if s.window > 0 && scheduled.Before(now.Add(-s.window)) {
+			continue
+		}
+		for _, item := range s.plan.Items {
+			if !placed(history[runKey(scheduled, item.Ticker)]) {
+				pending[scheduled] = append(pending[scheduled], item.Ticker)
+			}
+		}
+	}
+	return pending, nil
+}
+
+// RunDue executes every pending run, oldest first, and returns the outcome
+// entry it recorded for each item. A PENDING entry is recorded before each order is
+// sent, so an order whose outcome was never recorded is looked up in the
+// order history before it is sent again.
+func (s *Scheduler) RunDue(ctx context.Context) ([]Entry, error) {
+	pending, err := s.Pending()
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+	history, err := s.history()
+	if err != nil {
+		return nil, err
+	}
+
+	cash, err := s.client.GetAccountCash(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account cash: %w", err)
+	}
+	free := cash.Free
+	amounts := s.plan.Amounts()
+
+	var recorded []Entry
+	record := func(entry Entry) error {
+		if err := s.journal.Record(entry); err != nil {
+			return fmt.Errorf("failed to record journal entry: %w", err)
+		}
+		if entry.Status != EntryStatusPending {
+			recorded = append(recorded, entry)
+		}
+		return nil
+	}
+	for _, scheduled := range s.plan.Schedule(s.now()) {
+		for _, ticker := range pending[scheduled] {
+			previous := history[runKey(scheduled, ticker)]
+			if err := s.execute(ctx, scheduled, ticker, amounts[ticker], &free, previous, record); err != nil {
+				return recorded, err
+			}
+			if err := ctx.Err(); err != nil {
+				return recorded, err
+			}
+		}
+	}
+	return recorded, nil
+}
+
+// Run executes due runs and then sleeps until the next scheduled run,
+// returning when ctx is done
+func (s *Scheduler) Run(ctx context.Context) error {
+	for {
+		if _, err := s.RunDue(ctx); err != nil && ctx.Err() == nil {
+			return err
+		}
+
+		now := s.now()
+		next := s.plan.Cadence.At(s.plan.Start, len(s.plan.Schedule(now)))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.after(next.Sub(s.now())):
+		}
+	}
+}
+
+// execute places the order for a single plan item, deducting from free
+// cash. previous holds the item's earlier entries for the same run.
+func (s *Scheduler) execute(ctx context.Context, scheduled time.Time, ticker string, amount float64, free *float64, previous []Entry, record func(Entry) error) error {
+	entry := Entry{
+		Plan:        s.plan.Name,
+		ScheduledAt: scheduled,
+		ExecutedAt:  s.now(),
+		Ticker:      ticker,
+		Amount:      amount,
+	}
+
+	if sent, ok := unresolved(previous); ok {
+		order, err := s.client.FindSubmittedOrder(ctx, trading212.MarketOrderRequest{Ticker: ticker, Quantity: sent.Quantity}, sent.ExecutedAt, matchWindow)
+		if err != nil {
+			entry.Price, entry.Quantity = sent.Price, sent.Quantity
+			return record(unknown(entry, fmt.Errorf("failed to look up earlier order: %w", err)))
+		}
+		if order != nil {
+			entry.Price, entry.Quantity = sent.Price, sent.Quantity
+			entry.OrderID = order.ID
+			entry.Status = EntryStatusPlaced
+			entry.Reason = "found earlier order"
+			*free -= amount
+			return record(entry)
+		}
+	}
+
+	if s.hours != nil {
+		open, err := s.hours.IsOpen(ticker, entry.ExecutedAt)
+		if err != nil {
+			return record(failed(entry, fmt.Errorf("failed to check market hours: %w", err)))
+		}
+		if !open {
+			entry.Status = EntryStatusSkipped
+			entry.Reason = "market closed"
+			return record(entry)
+		}
+	}
+
+	if amount > *free {
+		entry.Status = EntryStatusSkipped
+		entry.Reason = fmt.Sprintf("insufficient free cash: need %.2f, have %.2f", amount, *free)
+		return record(entry)
+	}
+
+	price, err := s.prices.Price(ctx, ticker)
+	if err != nil {
+		return record(failed(entry, fmt.Errorf("failed to get price: %w", err)))
+	}
+	if price <= 0 {
+		return record(failed(entry, fmt.Errorf("invalid price %v", price)))
+	}
+	entry.Price = price
+	entry.Quantity = s.plan.quantity(amount, price)
+	if entry.Quantity <= 0 {
+		entry.Status = EntryStatusSkipped
+		entry.Reason = "amount too small for a single quantity step"
+		return record(entry)
+	}
+
+	sending := entry
+	sending.Status = EntryStatusPending
+	if err := record(sending); err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("dca|%s|%s|%d", s.plan.Name, runKey(scheduled, ticker), attempts(previous))
+	result, err := s.client.PlaceMarketOrderIdempotent(ctx, key, trading212.MarketOrderRequest{
+		ExtendedHours: s.plan.ExtendedHours,
+		Quantity:      entry.Quantity,
+		Ticker:        ticker,
+	}, nil)
+	entry.ExecutedAt = s.now()
+	switch {
+	case err == nil:
+		entry.OrderID = result.Order.ID
+		entry.Status = EntryStatusPlaced
+		*free -= amount
+		return record(entry)
+	case result != nil && result.Outcome == trading212.SubmissionUnknown:
+		return record(unknown(entry, fmt.Errorf("failed to place order: %w", err)))
+	default:
+		return record(failed(entry, fmt.Errorf("failed to place order: %w", err)))
+	}
+}
+
+func failed(entry Entry, err error) Entry {
+	entry.Status = EntryStatusFailed
+	entry.Reason = err.Error()
+	return entry
+}
+
+func unknown(entry Entry, err error) Entry {
+	entry.Status = EntryStatusUnknown
+	entry.Reason = err.Error()
+	return entry
+}
+
+// history returns the plan's journal entries grouped by run item
+func (s *Scheduler) history() (map[string][]Entry, error) {
+	entries, err := s.journal.Entries(s.plan.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	history := make(map[string][]Entry)
+	for _, entry := range entries {
+		key := runKey(entry.ScheduledAt, entry.Ticker)
+		history[key] = append(history[key], entry)
+	}
+	return history, nil
+}
+
+// placed reports whether any entry placed the order
+func placed(entries []Entry) bool {
+	for _, entry := range entries {
+		if entry.Status == EntryStatusPlaced {
+			return true
+		}
+	}
+	return false
+}
+
+// unresolved returns the last order sent for a run item when it is not known
+// whether the API created it: the process stopped after the PENDING entry,
+// or the submission ended UNKNOWN
+func unresolved(entries []Entry) (Entry, bool) {
+	var sent Entry
+	open := false
+	for _, entry := range entries {
+		switch entry.Status {
+		case EntryStatusPending:
+			sent, open = entry, true
+		case EntryStatusUnknown:
+			open = true
+		default:
+			open = false
+		}
+	}
+	return sent, open
+}
+
+// attempts counts the orders sent for a run item
+func attempts(entries []Entry) int {
+	n := 0
+	for _, entry := range entries {
+		if entry.Status == EntryStatusPending {
+			n++
+		}
+	}
+	return n
+}
+
+// runKey identifies a plan item on a scheduled run
+func runKey(scheduled time.Time, ticker string) string {
+	return scheduled.UTC().Format(time.RFC3339) + "|" + ticker
+}
@@ -0,0 +1,219 @@
+package dca
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+)
+
+var _ Client = (*trading212.Client)(nil)
+
+type fakeClient struct {
+	free   float64
+	orders []trading212.MarketOrderRequest
+	keys   []string
+	// lost makes the next order be created but reported as unknown
+	lost bool
+	// created holds the orders the API has, for FindSubmittedOrder
+	created []trading212.Order
+}
+
+func (f *fakeClient) GetAccountCash(ctx context.Context) (*trading212.AccountCash, error) {
+	return &trading212.AccountCash{Free: f.free}, nil
+}
+
+func (f *fakeClient) PlaceMarketOrderIdempotent(ctx context.Context, key string, req trading212.MarketOrderRequest, opts *trading212.IdempotencyOptions) (*trading212.SubmissionResult, error) {
+	f.orders = append(f.orders, req)
+	f.keys = append(f.keys, key)
+	order := trading212.Order{ID: int64(len(f.orders)), Ticker: req.Ticker, Quantity: req.Quantity}
+	f.created = append(f.created, order)
+	if f.lost {
+		f.lost = false
+		err := errors.New("connection reset")
+		return &trading212.SubmissionResult{Key: key, Outcome: trading212.SubmissionUnknown, Err: err}, err
+	}
+	return &trading212.SubmissionResult{Key: key, Outcome: trading212.SubmissionCreated, Order: &order}, nil
+}
+
+func (f *fakeClient) FindSubmittedOrder(ctx context.Context, req trading212.MarketOrderRequest, since time.Time, window time.Duration) (*trading212.Order, error) {
+	for i := range f.created {
+		if f.created[i].Ticker == req.Ticker && f.created[i].Quantity == req.Quantity {
+			return &f.created[i], nil
+		}
+	}
+	return nil, nil
+}
+
+type prices map[string]float64
+
+func (p prices) Price(ctx context.Context, ticker string) (float64, error) {
+	return p[ticker], nil
+}
+
+type closedMarkets map[string]bool
+
+func (c closedMarkets) IsOpen(ticker string, t time.Time) (bool, error) {
+	return !c[ticker], nil
+}
+
+func weeklyPlan(start time.Time) Plan {
+	return Plan{
+		Name:              "etfs",
+		Items:             []PlanItem{{Ticker: "VUSAl_EQ", Weight: 3}, {Ticker: "VWRLl_EQ", Weight: 1}},
+		Budget:            100,
+		Cadence:           Weekly,
+		Start:             start,
+		QuantityPrecision: 4,
+	}
+}
+
+func TestPlan_Validate(t *testing.T) {
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	assert.NoError(t, weeklyPlan(start).Validate())
+
+	plan := weeklyPlan(start)
+	plan.Budget = 0
+	assert.Error(t, plan.Validate())
+
+	plan = weeklyPlan(start)
+	plan.Items[0].Amount = 10
+	assert.Error(t, plan.Validate())
+
+	plan = weeklyPlan(start)
+	plan.Cadence = Cadence{}
+	assert.Error(t, plan.Validate())
+}
+
+func TestPlan_AmountsAndSchedule(t *testing.T) {
+	start := time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC)
+	plan := weeklyPlan(start)
+
+	amounts := plan.Amounts()
+	assert.InDelta(t, 75.0, amounts["VUSAl_EQ"], 1e-9)
+	assert.InDelta(t, 25.0, amounts["VWRLl_EQ"], 1e-9)
+
+	schedule := plan.Schedule(start.AddDate(0, 0, 14))
+	assert.Len(t, schedule, 3)
+
+	plan.Cadence = Monthly
+	schedule = plan.Schedule(start.AddDate(0, 2, 0))
+	assert.Len(t, schedule, 3)
+}
+
+func TestScheduler_CatchUpIsIdempotent(t *testing.T) {
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	client := &fakeClient{free: 1000}
+	journal := NewFileJournal(filepath.Join(t.TempDir(), "dca.jsonl"))
+
+	scheduler, err := NewScheduler(client, weeklyPlan(start), journal, prices{"VUSAl_EQ": 80, "VWRLl_EQ": 100})
+	require.NoError(t, err)
+	scheduler.now = func() time.Time { return start.AddDate(0, 0, 8) }
+
+	entries, err := scheduler.RunDue(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, entries, 4)
+	require.Len(t, client.orders, 4)
+	assert.Equal(t, 0.9375, client.orders[0].Quantity)
+	assert.Equal(t, 0.25, client.orders[1].Quantity)
+
+	entries, err = scheduler.RunDue(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+	assert.Len(t, client.orders, 4)
+}
+
+func TestScheduler_SkipsAndRetries(t *testing.T) {
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	client := &fakeClient{free: 1000}
+	journal := NewMemoryJournal()
+
+	scheduler, err := NewScheduler(client, weeklyPlan(start), journal, prices{"VUSAl_EQ": 80, "VWRLl_EQ": 100})
+	require.NoError(t, err)
+	scheduler.now = func() time.Time { return start }
+	scheduler.SetMarketHours(closedMarkets{"VWRLl_EQ": true})
+
+	entries, err := scheduler.RunDue(context.Background())
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, EntryStatusPlaced, entries[0].Status)
+	assert.Equal(t, EntryStatusSkipped, entries[1].Status)
+
+	scheduler.SetMarketHours(nil)
+	entries, err = scheduler.RunDue(context.Background())
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "VWRLl_EQ", entries[0].Ticker)
+	assert.Equal(t, EntryStatusPlaced, entries[0].Status)
+}
+
+func TestScheduler_InsufficientCash(t *testing.T) {
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	client := &fakeClient{free: 80}
+
+	scheduler, err := NewScheduler(client, weeklyPlan(start), NewMemoryJournal(), prices{"VUSAl_EQ": 80, "VWRLl_EQ": 100})
+	require.NoError(t, err)
+	scheduler.now = func() time.Time { return start }
+
+	entries, err := scheduler.RunDue(context.Background())
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, EntryStatusPlaced, entries[0].Status)
+	assert.Equal(t, EntryStatusSkipped, entries[1].Status)
+	assert.Contains(t, entries[1].Reason, "insufficient")
+}
+
+func TestScheduler_ReconcilesUnknownOrders(t *testing.T) {
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	client := &fakeClient{free: 1000, lost: true}
+	journal := NewMemoryJournal()
+
+	scheduler, err := NewScheduler(client, weeklyPlan(start), journal, prices{"VUSAl_EQ": 80, "VWRLl_EQ": 100})
+	require.NoError(t, err)
+	scheduler.now = func() time.Time { return start }
+
+	entries, err := scheduler.RunDue(context.Background())
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, EntryStatusUnknown, entries[0].Status)
+	assert.Equal(t, EntryStatusPlaced, entries[1].Status)
+	assert.Equal(t, "dca|etfs|2026-01-05T09:00:00Z|VUSAl_EQ|0", client.keys[0])
+
+	// The order was created after all, so it is found rather than sent again
+	entries, err = scheduler.RunDue(context.Background())
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, EntryStatusPlaced, entries[0].Status)
+	assert.Equal(t, int64(1), entries[0].OrderID)
+	assert.Len(t, client.orders, 2)
+}
+
+func TestScheduler_RecoversFromCrashAfterSending(t *testing.T) {
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	journal := NewFileJournal(filepath.Join(t.TempDir(), "dca.jsonl"))
+	pending := Entry{Plan: "etfs", ScheduledAt: start, ExecutedAt: start, Ticker: "VUSAl_EQ", Amount: 75, Price: 80, Quantity: 0.9375, Status: EntryStatusPending}
+	require.NoError(t, journal.Record(pending))
+	pending.Ticker, pending.Amount, pending.Price, pending.Quantity = "VWRLl_EQ", 25, 100, 0.25
+	require.NoError(t, journal.Record(pending))
+
+	// Only the first order reached the API before the crash
+	client := &fakeClient{free: 1000, created: []trading212.Order{{ID: 7, Ticker: "VUSAl_EQ", Quantity: 0.9375}}}
+	scheduler, err := NewScheduler(client, weeklyPlan(start), journal, prices{"VUSAl_EQ": 80, "VWRLl_EQ": 100})
+	require.NoError(t, err)
+	scheduler.now = func() time.Time { return start.Add(time.Hour) }
+
+	entries, err := scheduler.RunDue(context.Background())
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, int64(7), entries[0].OrderID)
+	assert.Equal(t, EntryStatusPlaced, entries[1].Status)
+	require.Len(t, client.orders, 1)
+	assert.Equal(t, "VWRLl_EQ", client.orders[0].Ticker)
+	assert.Equal(t, "dca|etfs|2026-01-05T09:00:00Z|VWRLl_EQ|1", client.keys[0])
+}
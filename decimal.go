@@ -0,0 +1,289 @@
+package trading212
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal represents an exact base-10 number. Decoding from JSON keeps the
+// digits sent by the server, so sums of quantities and cash amounts do not
+// drift the way float64 sums do. The zero value is 0.
+type Decimal struct {
+	unscaled *big.Int
+	scale    int32
+}
+
+// ErrInvalidDecimal is returned when a string is not a decimal number
+var ErrInvalidDecimal = errors.New("invalid decimal")
+
+// maxExponent bounds the exponent ParseDecimal accepts, so input such as
+// "1e999999999" cannot allocate a huge number
+const maxExponent = 1000
+
+// NewDecimal returns unscaled * 10^-scale
+func NewDecimal(unscaled int64, scale int32) Decimal {
+	d := Decimal{unscaled: big.NewInt(unscaled), scale: scale}
+	if scale < 0 {
+		d = d.rescale(0)
+	}
+	return d
+}
+
+// ParseDecimal parses a decimal number such as "-12.3400" or "1.5E-7"
+func ParseDecimal(s string) (Decimal, error) {
+	mantissa, exponent := s, int64(0)
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		exp, err := strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil || exp < -maxExponent || exp > maxExponent {
+			return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, s)
+		}
+		mantissa, exponent = s[:i], exp
+	}
+
+	digits := mantissa
+	scale := int64(0)
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		digits = mantissa[:i] + mantissa[i+1:]
+		scale = int64(len(mantissa) - i - 1)
+	}
+	if scale-exponent > math.MaxInt32 {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, s)
+	}
+	unsigned := strings.TrimLeft(digits, "+-")
+	if unsigned == "" || len(digits)-len(unsigned) > 1 || strings.Trim(unsigned, "0123456789") != "" {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, s)
+	}
+
+	unscaled, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, s)
+	}
+
+	d := Decimal{unscaled: unscaled, scale: int32(scale - exponent)}
+	if d.scale < 0 {
+		d = d.rescale(0)
+	}
+	return d, nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics on invalid input
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// DecimalFromFloat converts f using its shortest round-tripping
+// representation, so 0.1 becomes exactly 0.1
+func DecimalFromFloat(f float64) Decimal {
+	d, err := ParseDecimal(strconv.FormatFloat(f, 'g', -1, 64))
+	if err != nil {
+		return Decimal{}
+	}
+	return d
+}
+
+// String returns the number in plain notation with its full scale
+func (d Decimal) String() string {
+	s := d.int().String()
+	if d.scale <= 0 {
+		return s
+	}
+
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	if pad := int(d.scale) - len(s) + 1; pad > 0 {
+		s = strings.Repeat("0", pad) + s
+	}
+	s = s[:len(s)-int(d.scale)] + "." + s[len(s)-int(d.scale):]
+	if negative {
+		s = "-" + s
+	}
+	return s
+}
+
+// Float64 returns the nearest float64
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// Scale returns the number of digits after the decimal point
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// IsZero reports whether d is 0
+func (d Decimal) IsZero() bool {
+	return d.int().Sign() == 0
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of d
+func (d Decimal) Sign() int {
+	return d.int().Sign()
+}
+
+// Cmp compares d and e numerically, ignoring scale
+func (d Decimal) Cmp(e Decimal) int {
+	a, b := align(d, e)
+	return a.unscaled.Cmp(b.unscaled)
+}
+
+// Equal reports whether d and e are numerically equal
+func (d Decimal) Equal(e Decimal) bool {
+	return d.Cmp(e) == 0
+}
+
+// Add returns d + e
+func (d Decimal) Add(e Decimal) Decimal {
+	a, b := align(d, e)
+	return Decimal{unscaled: new(big.Int).Add(a.unscaled, b.unscaled), scale: a.scale}
+}
+
+// Sub returns d - e
+func (d Decimal) Sub(e Decimal) Decimal {
+	a, b := align(d, e)
+	return Decimal{unscaled: new(big.Int).Sub(a.unscaled, b.unscaled), scale: a.scale}
+}
+
+// Mul returns d * e
+func (d Decimal) Mul(e Decimal) Decimal {
+	return Decimal{unscaled: new(big.Int).Mul(d.int(), e.int()), scale: d.scale + e.scale}
+}
+
+// Div returns d / e rounded half away from zero to places decimal places
+func (d Decimal) Div(e Decimal, places int32) (Decimal, error) {
+	if e.IsZero() {
+		return Decimal{}, errors.New("decimal division by zero")
+	}
+
+	// d/e = (du * 10^(places+1+es-ds)) / eu * 10^-(places+1)
+	shift := int64(places) + 1 + int64(e.scale) - int64(d.scale)
+	num := new(big.Int).Set(d.int())
+	den := new(big.Int).Set(e.int())
+	if shift >= 0 {
+		num.Mul(num, pow10(shift))
+	} else {
+		den.Mul(den, pow10(-shift))
+	}
+	quotient := Decimal{unscaled: num.Quo(num, den), scale: places + 1}
+	return quotient.Round(places), nil
+}
+
+// Neg returns -d
+func (d Decimal) Neg() Decimal {
+	return Decimal{unscaled: new(big.Int).Neg(d.int()), scale: d.scale}
+}
+
+// Abs returns |d|
+func (d Decimal) Abs() Decimal {
+	return Decimal{unscaled: new(big.Int).Abs(d.int()), scale: d.scale}
+}
+
+// Round returns d rounded half away from zero to places decimal places
+func (d Decimal) Round(places int32) Decimal {
+	if places >= d.scale {
+		return d.rescale(places)
+	}
+
+	divisor := pow10(int64(d.scale - places))
+	quotient, remainder := new(big.Int).QuoRem(d.int(), divisor, new(big.Int))
+	remainder.Abs(remainder).Mul(remainder, big.NewInt(2))
+	if remainder.Cmp(divisor) >= 0 {
+		if d.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+	return Decimal{unscaled: quotient, scale: places}
+}
+
+// Truncate returns d rounded towards zero to places decimal places
+func (d Decimal) Truncate(places int32) Decimal {
+	if places >= d.scale {
+		return d.rescale(places)
+	}
+	divisor := pow10(int64(d.scale - places))
+	return Decimal{unscaled: new(big.Int).Quo(d.int(), divisor), scale: places}
+}
+
+// Normalize returns d without trailing zeros after the decimal point, so
+// 1.500 becomes 1.5 and 2.00 becomes 2
+func (d Decimal) Normalize() Decimal {
+	unscaled, scale := new(big.Int).Set(d.int()), d.scale
+	if unscaled.Sign() == 0 {
+		return Decimal{unscaled: unscaled}
+	}
+	ten := big.NewInt(10)
+	quotient, remainder := new(big.Int), new(big.Int)
+	for scale > 0 {
+		quotient.QuoRem(unscaled, ten, remainder)
+		if remainder.Sign() != 0 {
+			break
+		}
+		unscaled, quotient = quotient, unscaled
+		scale--
+	}
+	return Decimal{unscaled: unscaled, scale: scale}
+}
+
+// MarshalJSON encodes d as a JSON number with its full scale
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON decodes a JSON number or numeric string, keeping its digits
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	s := string(data)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+	}
+
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// int returns the unscaled value, treating the zero Decimal as 0
+func (d Decimal) int() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return d.unscaled
+}
+
+// rescale returns d with a larger scale; scale must not be below d.scale
+// unless d.scale is negative
+func (d Decimal) rescale(scale int32) Decimal {
+	if scale == d.scale {
+		return Decimal{unscaled: d.int(), scale: scale}
+	}
+	return Decimal{unscaled: new(big.Int).Mul(d.int(), pow10(int64(scale-d.scale))), scale: scale}
+}
+
+// align returns d and e rescaled to the larger of their scales
+func align(d, e Decimal) (Decimal, Decimal) {
+	scale := d.scale
+	if e.scale > scale {
+		scale = e.scale
+	}
+	return d.rescale(scale), e.rescale(scale)
+}
+
+func pow10(n int64) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(n), nil)
+}
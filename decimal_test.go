@@ -0,0 +1,157 @@
+package trading212
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"0", "0"},
+		{"12.3400", "12.3400"},
+		{"-0.05", "-0.05"},
+		{"+7", "7"},
+		{".5", "0.5"},
+		{"1.5E-7", "0.00000015"},
+		{"2.5e3", "2500"},
+		{"123456789012345678901234.5", "123456789012345678901234.5"},
+		{"1e1000", "1" + strings.Repeat("0", 1000)},
+	}
+	for _, tt := range tests {
+		d, err := ParseDecimal(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, d.String(), tt.in)
+	}
+
+	for _, bad := range []string{"", "-", "1.2.3", "abc", "1e", "--1", "1-2", "1e1001", "1e-1001", "1e2000000000", "1e-2000000000"} {
+		_, err := ParseDecimal(bad)
+		assert.ErrorIs(t, err, ErrInvalidDecimal, bad)
+	}
+}
+
+func TestDecimal_Arithmetic(t *testing.T) {
+	a := MustParseDecimal("0.1")
+	b := MustParseDecimal("0.2")
+	assert.Equal(t, "0.3", a.Add(b).String())
+	assert.True(t, a.Add(b).Equal(MustParseDecimal("0.30")))
+	assert.Equal(t, "-0.1", a.Sub(b).String())
+	assert.Equal(t, "0.02", a.Mul(b).String())
+	assert.Equal(t, -1, a.Cmp(b))
+	assert.Equal(t, "0", Decimal{}.String())
+	assert.True(t, Decimal{}.Add(a).Equal(a))
+
+	third, err := MustParseDecimal("1").Div(MustParseDecimal("3"), 4)
+	require.NoError(t, err)
+	assert.Equal(t, "0.3333", third.String())
+
+	_, err = a.Div(Decimal{}, 2)
+	assert.Error(t, err)
+
+	assert.Equal(t, "2.35", MustParseDecimal("2.345").Round(2).String())
+	assert.Equal(t, "-2.35", MustParseDecimal("-2.345").Round(2).String())
+	assert.Equal(t, "2.34", MustParseDecimal("2.345").Truncate(2).String())
+	assert.Equal(t, "2.3450", MustParseDecimal("2.345").Round(4).String())
+	assert.Equal(t, "0.1", DecimalFromFloat(0.1).String())
+
+	assert.Equal(t, "1.5", MustParseDecimal("1.500").Normalize().String())
+	assert.Equal(t, "-20", MustParseDecimal("-20.00").Normalize().String())
+	assert.Equal(t, "0", MustParseDecimal("0.000").Normalize().String())
+	assert.Equal(t, "0.001", MustParseDecimal("0.001").Normalize().String())
+	assert.Equal(t, "0", Decimal{}.Normalize().String())
+}
+
+func TestDecimal_SumHasNoDrift(t *testing.T) {
+	var total Decimal
+	var float float64
+	for i := 0; i < 10; i++ {
+		total = total.Add(MustParseDecimal("0.1"))
+		float += 0.1
+	}
+	assert.NotEqual(t, 1.0, float)
+	assert.True(t, total.Equal(MustParseDecimal("1")))
+}
+
+func TestDecimal_JSON(t *testing.T) {
+	var v struct {
+		Number Decimal  `json:"number"`
+		String Decimal  `json:"string"`
+		Null   *Decimal `json:"null"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"number":0.1000000000000000055511,"string":"12.50","null":null}`), &v))
+	assert.Equal(t, "0.1000000000000000055511", v.Number.String())
+	assert.Equal(t, "12.50", v.String.String())
+	assert.Nil(t, v.Null)
+
+	data, err := json.Marshal(v.String)
+	require.NoError(t, err)
+	assert.Equal(t, "12.50", string(data))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"number":"abc"}`), &v))
+}
+
+func TestMoney(t *testing.T) {
+	gbp := MustMoney("10.50", "GBP")
+	sum, err := gbp.Add(MustMoney("0.25", "GBP"))
+	require.NoError(t, err)
+	assert.Equal(t, "10.75 GBP", sum.String())
+
+	_, err = gbp.Add(MustMoney("1", "USD"))
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+	_, err = gbp.Cmp(MustMoney("1", "USD"))
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	_, err = NewMoney(MustParseDecimal("1"), "gbp")
+	assert.ErrorIs(t, err, ErrInvalidCurrency)
+
+	total, err := SumMoney(MustMoney("1.10", "EUR"), MustMoney("2.20", "EUR"))
+	require.NoError(t, err)
+	assert.Equal(t, "3.30 EUR", total.String())
+
+	_, err = SumMoney(MustMoney("1", "EUR"), MustMoney("1", "GBP"))
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	converted, err := MustMoney("100", "USD").Convert(MustParseDecimal("0.79"), "GBP")
+	require.NoError(t, err)
+	assert.Equal(t, "79.00 GBP", converted.String())
+}
+
+func TestGetHistoricalOrdersPrecise(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v0/equity/history/orders", r.URL.Path)
+		w.Write([]byte(`{"items":[{"fill":{"id":1,"price":187.123456,"quantity":0.0534612,
+			"walletImpact":{"currency":"GBP","fxRate":0.7895,"netValue":7.89,"taxes":[
+			{"currency":"GBP","name":"CURRENCY_CONVERSION_FEE","quantity":0.01},
+			{"currency":"GBP","name":"STAMP_DUTY","quantity":0.04}]}},
+			"order":{"id":2,"ticker":"AAPL_US_EQ","filledQuantity":0.0534612,"limitPrice":190.1}}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(Environment(server.URL), "key", "secret")
+	result, err := client.GetHistoricalOrdersPrecise(context.Background(), nil)
+	require.NoError(t, err)
+	require.Len(t, result.Items, 1)
+
+	fill := result.Items[0].Fill
+	assert.Equal(t, "0.0534612", fill.Quantity.String())
+	assert.Equal(t, "10.0038445059072", fill.Value().String())
+	assert.Equal(t, "7.89 GBP", fill.WalletImpact.Net().String())
+
+	taxes, err := fill.WalletImpact.TotalTaxes()
+	require.NoError(t, err)
+	assert.Equal(t, "0.05 GBP", taxes.String())
+
+	order := result.Items[0].Order
+	require.NotNil(t, order.LimitPrice)
+	assert.Equal(t, "190.1", order.LimitPrice.String())
+	assert.True(t, order.FilledQuantity.Equal(fill.Quantity))
+}
@@ -0,0 +1,285 @@
+// Package dividends analyses dividend income: totals by ticker, period and
+// dividend type, yield on cost from the lots of a portfolio, and a forward
+// income estimate from the latest payments. Amounts are in account currency
+// unless noted.
+package dividends
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/internal/decimals"
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/portfolio"
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/tax"
+)
+
+// Period represents how payments are grouped over time
+type Period string
+
+const (
+	PeriodMonth Period = "MONTH"
+	// PeriodYear groups by calendar year
+	PeriodYear Period = "YEAR"
+	// PeriodTaxYear groups by UK tax year, starting on 6 April
+	PeriodTaxYear Period = "TAX_YEAR"
+)
+
+// ParsePeriod returns the period with the given name, ignoring case and
+// accepting tax-year for TAX_YEAR
+func ParsePeriod(s string) (Period, error) {
+	switch p := Period(strings.ToUpper(strings.ReplaceAll(s, "-", "_"))); p {
+	case PeriodMonth, PeriodYear, PeriodTaxYear:
+		return p, nil
+	}
+	return "", fmt.Errorf("unknown period %q, expected month, year or tax-year", s)
+}
+
+// Label returns the name of the period containing t, such as 2024-03, 2024
+// or 2024/25
+func (p Period) Label(t time.Time) string {
+	switch p {
+	case PeriodYear:
+		return t.Format("2006")
+	case PeriodTaxYear:
+		return tax.TaxYearOf(t).String()
+	}
+	return t.Format("2006-01")
+}
+
+// Total represents the payments in a group
+type Total struct {
+	Key      string             `json:"key"`
+	Payments int                `json:"payments"`
+	Amount   trading212.Decimal `json:"amount"`
+	// AmountInEuro is the amount as reported in euro by the API
+	AmountInEuro trading212.Decimal `json:"amountInEuro"`
+	// Manufactured is the part of Amount paid in lieu of dividends while
+	// shares were lent out
+	Manufactured trading212.Decimal `json:"manufactured"`
+}
+
+func (t *Total) add(item trading212.HistoryDividendItem) {
+	amount := trading212.DecimalFromFloat(item.Amount)
+	t.Payments++
+	t.Amount = t.Amount.Add(amount)
+	t.AmountInEuro = t.AmountInEuro.Add(trading212.DecimalFromFloat(item.AmountInEuro))
+	if item.Type.IsManufacturedPayment() {
+		t.Manufactured = t.Manufactured.Add(amount)
+	}
+}
+
+// Analysis represents a set of dividend payments, oldest first
+type Analysis struct {
+	items []trading212.HistoryDividendItem
+}
+
+// New returns an analysis of items, in any order
+func New(items []trading212.HistoryDividendItem) *Analysis {
+	sorted := append([]trading212.HistoryDividendItem(nil), items...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].PaidOn.Before(sorted[j].PaidOn)
+	})
+	return &Analysis{items: sorted}
+}
+
+// Items returns the payments, oldest first
+func (a *Analysis) Items() []trading212.HistoryDividendItem {
+	return append([]trading212.HistoryDividendItem(nil), a.items...)
+}
+
+// Between returns the payments made from (inclusive) to (exclusive); a zero
+// time leaves that side open
+func (a *Analysis) Between(from, to time.Time) *Analysis {
+	return a.filter(func(item trading212.HistoryDividendItem) bool {
+		return (from.IsZero() || !item.PaidOn.Before(from)) && (to.IsZero() || item.PaidOn.Before(to))
+	})
+}
+
+// Regular returns the payments that are not manufactured payments
+func (a *Analysis) Regular() *Analysis {
+	return a.filter(func(item trading212.HistoryDividendItem) bool {
+		return !item.Type.IsManufacturedPayment()
+	})
+}
+
+// Manufactured returns the payments made in lieu of dividends
+func (a *Analysis) Manufactured() *Analysis {
+	return a.filter(func(item trading212.HistoryDividendItem) bool {
+		return item.Type.IsManufacturedPayment()
+	})
+}
+
+// Total returns the total of every payment, with an empty key
+func (a *Analysis) Total() Total {
+	var total Total
+	for _, item := range a.items {
+		total.add(item)
+	}
+	return total
+}
+
+// ByTicker returns the totals per ticker, largest amount first
+func (a *Analysis) ByTicker() []Total {
+	totals := a.group(func(item trading212.HistoryDividendItem) string {
+		return ticker(item)
+	})
+	sort.SliceStable(totals, func(i, j int) bool {
+		return totals[i].Amount.Cmp(totals[j].Amount) > 0
+	})
+	return totals
+}
+
+// ByPeriod returns the totals per period, oldest first
+func (a *Analysis) ByPeriod(p Period) []Total {
+	totals := a.group(func(item trading212.HistoryDividendItem) string {
+		return p.Label(item.PaidOn)
+	})
+	sort.SliceStable(totals, func(i, j int) bool {
+		return totals[i].Key < totals[j].Key
+	})
+	return totals
+}
+
+// ByType returns the totals per dividend type, largest amount first
+func (a *Analysis) ByType() []Total {
+	totals := a.group(func(item trading212.HistoryDividendItem) string {
+		return string(item.Type)
+	})
+	sort.SliceStable(totals, func(i, j int) bool {
+		return totals[i].Amount.Cmp(totals[j].Amount) > 0
+	})
+	return totals
+}
+
+// Yield represents the dividend income of a holding relative to its cost
+type Yield struct {
+	Ticker string `json:"ticker"`
+	// Income is the amount received in the year to the analysis date
+	Income trading212.Decimal `json:"income"`
+	Cost   trading212.Decimal `json:"cost"`
+	// YieldOnCost is Income / Cost, 0.05 for 5%
+	YieldOnCost trading212.Decimal `json:"yieldOnCost"`
+}
+
+// YieldOnCost returns the income of the year to asOf relative to the cost
+// basis of each open holding, highest yield first. Holdings without income
+// are included with a zero yield.
+func (a *Analysis) YieldOnCost(holdings []portfolio.Holding, asOf time.Time) []Yield {
+	income := make(map[string]trading212.Decimal)
+	for _, t := range a.Between(asOf.AddDate(-1, 0, 0), asOf).group(ticker) {
+		income[t.Key] = t.Amount
+	}
+
+	yields := make([]Yield, 0, len(holdings))
+	for _, h := range holdings {
+		y := Yield{Ticker: h.Ticker, Income: income[h.Ticker], Cost: h.Cost}
+		y.YieldOnCost = decimals.Ratio(y.Income, y.Cost)
+		yields = append(yields, y)
+	}
+	sort.SliceStable(yields, func(i, j int) bool {
+		return yields[i].YieldOnCost.Cmp(yields[j].YieldOnCost) > 0
+	})
+	return yields
+}
+
+// Projection represents the estimated income of a position over the next
+// year
+type Projection struct {
+	Ticker   string             `json:"ticker"`
+	Quantity trading212.Decimal `json:"quantity"`
+	// Frequency is the number of payments in the past year
+	Frequency  int       `json:"frequency"`
+	LastPaidOn time.Time `json:"lastPaidOn"`
+	// GrossPerShare is the latest gross payment per share in TickerCurrency
+	GrossPerShare  trading212.Decimal `json:"grossPerShare"`
+	TickerCurrency string             `json:"tickerCurrency"`
+	// NetPerShare is the latest amount received per share, after
+	// withholding tax, in account currency
+	NetPerShare trading212.Decimal `json:"netPerShare"`
+	// AnnualIncome is NetPerShare × Quantity × Frequency
+	AnnualIncome trading212.Decimal `json:"annualIncome"`
+}
+
+// Forward estimates the income of the next year from the positions held:
+// each position that paid in the year to asOf is expected to keep paying
+// its latest amount per share as often as it did in that year. Manufactured
+// payments count as regular ones; one-off corporate actions and returns of
+// capital are ignored. Projections are ordered by income, largest first.
+func (a *Analysis) Forward(positions []trading212.Position, asOf time.Time) []Projection {
+	recent := a.Between(asOf.AddDate(-1, 0, 0), asOf).filter(func(item trading212.HistoryDividendItem) bool {
+		return !item.Type.IsCorporateAction() && !item.Type.IsReturnOfCapital() && item.Quantity > 0
+	})
+
+	latest := make(map[string]trading212.HistoryDividendItem)
+	paid := make(map[string]map[string]bool)
+	for _, item := range recent.items {
+		t := ticker(item)
+		latest[t] = item
+		if paid[t] == nil {
+			paid[t] = make(map[string]bool)
+		}
+		paid[t][item.PaidOn.Format("2006-01-02")] = true
+	}
+
+	var projections []Projection
+	for _, position := range positions {
+		item, ok := latest[position.Ticker]
+		if !ok || position.Quantity <= 0 {
+			continue
+		}
+		p := Projection{
+			Ticker:         position.Ticker,
+			Quantity:       trading212.DecimalFromFloat(position.Quantity),
+			Frequency:      len(paid[position.Ticker]),
+			LastPaidOn:     item.PaidOn,
+			GrossPerShare:  trading212.DecimalFromFloat(item.GrossAmountPerShare),
+			TickerCurrency: item.TickerCurrency,
+			NetPerShare:    decimals.Ratio(trading212.DecimalFromFloat(item.Amount), trading212.DecimalFromFloat(item.Quantity)),
+		}
+		p.AnnualIncome = p.NetPerShare.Mul(p.Quantity).Mul(trading212.NewDecimal(int64(p.Frequency), 0))
+		projections = append(projections, p)
+	}
+	sort.SliceStable(projections, func(i, j int) bool {
+		return projections[i].AnnualIncome.Cmp(projections[j].AnnualIncome) > 0
+	})
+	return projections
+}
+
+func (a *Analysis) filter(keep func(trading212.HistoryDividendItem) bool) *Analysis {
+	var items []trading212.HistoryDividendItem
+	for _, item := range a.items {
+		if keep(item) {
+			items = append(items, item)
+		}
+	}
+	return &Analysis{items: items}
+}
+
+// group totals the payments by key, in order of first appearance
+func (a *Analysis) group(key func(trading212.HistoryDividendItem) string) []Total {
+	var totals []Total
+	index := make(map[string]int)
+	for _, item := range a.items {
+		k := key(item)
+		i, ok := index[k]
+		if !ok {
+			i = len(totals)
+			index[k] = i
+			totals = append(totals, Total{Key: k})
+		}
+		totals[i].add(item)
+	}
+	return totals
+}
+
+// ticker returns the ticker of a payment, which is sometimes only set on
+// the instrument
+func ticker(item trading212.HistoryDividendItem) string {
+	if item.Ticker != "" {
+		return item.Ticker
+	}
+	return item.Instrument.Ticker
+}
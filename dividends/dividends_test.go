@@ -0,0 +1,128 @@
+package dividends
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/internal/fixtures"
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/portfolio"
+)
+
+func payment(ticker, date string, dividendType trading212.DividendType, quantity, grossPerShare, amount float64) trading212.HistoryDividendItem {
+	paidOn, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		panic(err)
+	}
+	return trading212.HistoryDividendItem{
+		Ticker:              ticker,
+		PaidOn:              paidOn.Add(12 * time.Hour),
+		Type:                dividendType,
+		Quantity:            quantity,
+		GrossAmountPerShare: grossPerShare,
+		Amount:              amount,
+		AmountInEuro:        amount * 1.2,
+		Currency:            "GBP",
+		TickerCurrency:      "USD",
+	}
+}
+
+func payments() []trading212.HistoryDividendItem {
+	return []trading212.HistoryDividendItem{
+		payment("AAPL", "2024-05-16", trading212.DividendTypeDividendsPaidByUSCorporations, 10, 0.25, 2),
+		payment("AAPL", "2024-02-15", trading212.DividendTypeDividendsPaidByUSCorporations, 10, 0.24, 1.9),
+		payment("AAPL", "2024-08-15", trading212.DividendTypeDividendsPaidByUSCorporationsManufacturedPayment, 10, 0.25, 2),
+		payment("VOD", "2024-03-30", trading212.DividendTypeOrdinary, 100, 0.05, 5),
+		payment("VOD", "2024-04-10", trading212.DividendTypeDemerger, 100, 0.01, 1),
+	}
+}
+
+var assertDecimal = fixtures.AssertDecimal
+
+func TestTotals(t *testing.T) {
+	a := New(payments())
+
+	total := a.Total()
+	assert.Equal(t, 5, total.Payments)
+	assertDecimal(t, "11.9", total.Amount)
+	assertDecimal(t, "14.28", total.AmountInEuro)
+	assertDecimal(t, "2", total.Manufactured)
+
+	byTicker := a.ByTicker()
+	require.Len(t, byTicker, 2)
+	assert.Equal(t, "VOD", byTicker[0].Key)
+	assertDecimal(t, "6", byTicker[0].Amount)
+	assertDecimal(t, "5.9", byTicker[1].Amount)
+
+	months := a.ByPeriod(PeriodMonth)
+	require.Len(t, months, 5)
+	assert.Equal(t, "2024-02", months[0].Key)
+
+	years := a.ByPeriod(PeriodTaxYear)
+	require.Len(t, years, 2)
+	assert.Equal(t, "2023/24", years[0].Key)
+	assertDecimal(t, "6.9", years[0].Amount)
+	assert.Equal(t, "2024/25", years[1].Key)
+	assertDecimal(t, "5", years[1].Amount)
+
+	assert.Len(t, a.ByPeriod(PeriodYear), 1)
+	assert.Len(t, a.ByType(), 4)
+
+	assertDecimal(t, "2", a.Manufactured().Total().Amount)
+	assertDecimal(t, "9.9", a.Regular().Total().Amount)
+	assert.Equal(t, 2, a.Between(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)).Total().Payments)
+}
+
+func TestYieldOnCost(t *testing.T) {
+	p, err := portfolio.Replay([]trading212.HistoricalOrder{
+		{
+			Order: trading212.Order{Ticker: "AAPL", Side: trading212.OrderSideBuy},
+			Fill:  trading212.Fill{ID: 1, Quantity: 10, Price: 150, WalletImpact: trading212.FillWalletImpact{NetValue: 1200}},
+		},
+		{
+			Order: trading212.Order{Ticker: "MSFT", Side: trading212.OrderSideBuy},
+			Fill:  trading212.Fill{ID: 2, Quantity: 1, Price: 400, WalletImpact: trading212.FillWalletImpact{NetValue: 320}},
+		},
+	}, portfolio.MethodAverage)
+	require.NoError(t, err)
+
+	yields := New(payments()).YieldOnCost(p.Holdings(), time.Date(2025, 2, 20, 0, 0, 0, 0, time.UTC))
+	require.Len(t, yields, 2)
+	assert.Equal(t, "AAPL", yields[0].Ticker)
+	assertDecimal(t, "4", yields[0].Income)
+	assertDecimal(t, "0.0033333333", yields[0].YieldOnCost)
+	assertDecimal(t, "0", yields[1].YieldOnCost)
+}
+
+func TestForward(t *testing.T) {
+	positions := []trading212.Position{
+		{Ticker: "AAPL", Quantity: 20},
+		{Ticker: "VOD", Quantity: 100},
+		{Ticker: "MSFT", Quantity: 1},
+	}
+	projections := New(payments()).Forward(positions, time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC))
+	require.Len(t, projections, 2)
+
+	aapl := projections[0]
+	assert.Equal(t, "AAPL", aapl.Ticker)
+	assert.Equal(t, 3, aapl.Frequency)
+	assertDecimal(t, "0.25", aapl.GrossPerShare)
+	assertDecimal(t, "0.2", aapl.NetPerShare)
+	assertDecimal(t, "12", aapl.AnnualIncome)
+
+	// The demerger is a one-off, so only the ordinary dividend counts
+	vod := projections[1]
+	assert.Equal(t, 1, vod.Frequency)
+	assertDecimal(t, "5", vod.AnnualIncome)
+}
+
+func TestParsePeriod(t *testing.T) {
+	p, err := ParsePeriod("tax-year")
+	require.NoError(t, err)
+	assert.Equal(t, PeriodTaxYear, p)
+	_, err = ParsePeriod("week")
+	assert.Error(t, err)
+}
@@ -0,0 +1,138 @@
+package trading212
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// dryRunOrderID hands out synthetic order IDs; they are negative so they can
+// never collide with IDs assigned by the API
+var dryRunOrderID int64
+
+// SetDryRun enables or disables dry-run mode. In dry-run mode the Place*
+// methods, CancelOrder and the pie create, update, duplicate and delete
+// methods validate the request and record it in the order journal (if one
+// is set) but never send it; Place* methods return a synthetic order with
+// OrderStatusLocal and pie methods a synthetic pie with a negative ID.
+// Each call is also passed to the dry-run logger, so it leaves a trace even
+// without a journal. Read-only methods are unaffected.
+func (c *Client) SetDryRun(enabled bool) {
+	c.dryRun = enabled
+}
+
+// DryRunCall represents a call that was not sent because of dry-run mode
+type DryRunCall struct {
+	Operation string
+	// OrderID and PieID identify the order or pie acted on, if any
+	OrderID int64
+	PieID   int64
+	Request interface{}
+	// Err is set when the request failed validation
+	Err error
+}
+
+// String returns the call as a single log line
+func (d DryRunCall) String() string {
+	parts := []string{"dry run " + d.Operation}
+	if d.OrderID != 0 {
+		parts = append(parts, fmt.Sprintf("order %d", d.OrderID))
+	}
+	if d.PieID != 0 {
+		parts = append(parts, fmt.Sprintf("pie %d", d.PieID))
+	}
+	if d.Request != nil {
+		if raw, err := json.Marshal(d.Request); err == nil {
+			parts = append(parts, string(raw))
+		}
+	}
+	if d.Err != nil {
+		parts = append(parts, "rejected: "+d.Err.Error())
+	}
+	return strings.Join(parts, " ")
+}
+
+// SetDryRunLogger sets the function every dry-run call is passed to. By
+// default calls are written to the standard library logger; nil restores
+// the default.
+func (c *Client) SetDryRunLogger(logger func(DryRunCall)) {
+	c.dryRunLogger = logger
+}
+
+// logDryRun passes a dry-run call to the dry-run logger
+func (c *Client) logDryRun(call DryRunCall) {
+	if c.dryRunLogger != nil {
+		c.dryRunLogger(call)
+		return
+	}
+	log.Print("trading212: ", call)
+}
+
+// DryRun reports whether dry-run mode is enabled
+func (c *Client) DryRun() bool {
+	return c.dryRun
+}
+
+// dryRunOrder validates req and builds the order the API would have created
+func (c *Client) dryRunOrder(req interface{}) (*Order, error) {
+	order := Order{
+		CreatedAt:     time.Now(),
+		ID:            atomic.AddInt64(&dryRunOrderID, -1),
+		InitiatedFrom: "API",
+		Status:        OrderStatusLocal,
+		Strategy:      OrderStrategyQuantity,
+		TimeInForce:   TimeValidityDay,
+	}
+
+	switch r := req.(type) {
+	case MarketOrderRequest:
+		if err := r.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid market order: %w", err)
+		}
+		order.Type = OrderTypeMarket
+		order.Ticker = r.Ticker
+		order.Quantity = r.Quantity
+		order.ExtendedHours = r.ExtendedHours
+	case LimitOrderRequest:
+		if err := r.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid limit order: %w", err)
+		}
+		order.Type = OrderTypeLimit
+		order.Ticker = r.Ticker
+		order.Quantity = r.Quantity
+		order.LimitPrice = &r.LimitPrice
+		order.TimeInForce = r.TimeValidity
+	case StopOrderRequest:
+		if err := r.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid stop order: %w", err)
+		}
+		order.Type = OrderTypeStop
+		order.Ticker = r.Ticker
+		order.Quantity = r.Quantity
+		order.StopPrice = &r.StopPrice
+		order.TimeInForce = r.TimeValidity
+	case StopLimitOrderRequest:
+		if err := r.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid stop-limit order: %w", err)
+		}
+		order.Type = OrderTypeStopLimit
+		order.Ticker = r.Ticker
+		order.Quantity = r.Quantity
+		order.LimitPrice = &r.LimitPrice
+		order.StopPrice = &r.StopPrice
+		order.TimeInForce = r.TimeValidity
+	default:
+		return nil, fmt.Errorf("unsupported order request %T", req)
+	}
+
+	order.Side = OrderSideBuy
+	if order.Quantity < 0 {
+		order.Side = OrderSideSell
+	}
+	order.Instrument.Ticker = order.Ticker
+
+	return &order, nil
+}
@@ -0,0 +1,180 @@
+package trading212
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRun_NeverSendsOrders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected %s %s in dry-run mode", r.Method, r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(AccountCash{Free: 100})
+	}))
+	defer server.Close()
+
+	journal := NewMemoryJournal()
+	client := NewClient(Environment(server.URL), "key", "secret")
+	client.SetOrderJournal(journal)
+	client.SetDryRun(true)
+	ctx := context.Background()
+
+	cash, err := client.GetAccountCash(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 100.0, cash.Free)
+
+	order, err := client.PlaceMarketOrder(ctx, MarketOrderRequest{Ticker: "AAPL_US_EQ", Quantity: -2})
+	require.NoError(t, err)
+	assert.Equal(t, OrderStatusLocal, order.Status)
+	assert.Equal(t, OrderTypeMarket, order.Type)
+	assert.Equal(t, OrderSideSell, order.Side)
+	assert.Less(t, order.ID, int64(0))
+
+	limit, err := client.PlaceLimitOrder(ctx, LimitOrderRequest{Ticker: "AAPL_US_EQ", Quantity: 1, LimitPrice: 150, TimeValidity: TimeValidityGoodTillCancel})
+	require.NoError(t, err)
+	require.NotNil(t, limit.LimitPrice)
+	assert.Equal(t, 150.0, *limit.LimitPrice)
+	assert.Equal(t, TimeValidityGoodTillCancel, limit.TimeInForce)
+	assert.NotEqual(t, order.ID, limit.ID)
+
+	_, err = client.PlaceStopOrder(ctx, StopOrderRequest{Ticker: "AAPL_US_EQ", Quantity: 1, TimeValidity: TimeValidityDay})
+	assert.Error(t, err)
+
+	require.NoError(t, client.CancelOrder(ctx, order.ID))
+
+	entries, err := journal.Query(JournalQuery{})
+	require.NoError(t, err)
+	require.Len(t, entries, 8)
+	for _, entry := range entries {
+		assert.True(t, entry.DryRun)
+	}
+	assert.Contains(t, entries[5].Error, "invalid stop price")
+}
+
+func TestOrderRequest_Validate(t *testing.T) {
+	assert.NoError(t, MarketOrderRequest{Ticker: "AAPL_US_EQ", Quantity: 1}.Validate())
+	assert.Error(t, MarketOrderRequest{Quantity: 1}.Validate())
+	assert.Error(t, MarketOrderRequest{Ticker: "AAPL_US_EQ"}.Validate())
+	assert.Error(t, LimitOrderRequest{Ticker: "AAPL_US_EQ", Quantity: 1, LimitPrice: 1, TimeValidity: "WEEK"}.Validate())
+	assert.NoError(t, StopLimitOrderRequest{Ticker: "AAPL_US_EQ", Quantity: 1, LimitPrice: 1, StopPrice: 2, TimeValidity: TimeValidityDay}.Validate())
+	assert.Error(t, StopLimitOrderRequest{Ticker: "AAPL_US_EQ", Quantity: 1, LimitPrice: 1, TimeValidity: TimeValidityDay}.Validate())
+}
+
+func TestDryRun_NeverChangesPies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected %s %s in dry-run mode", r.Method, r.URL.Path)
+	}))
+	defer server.Close()
+
+	journal := NewMemoryJournal()
+	client := NewClient(Environment(server.URL), "key", "secret")
+	client.SetOrderJournal(journal)
+	client.SetDryRun(true)
+	ctx := context.Background()
+
+	pie, err := client.CreatePie(ctx, PieRequest{Name: "Tech", InstrumentShares: map[string]float64{"MSFT_US_EQ": 0.4, "AAPL_US_EQ": 0.6}})
+	require.NoError(t, err)
+	assert.Less(t, pie.Settings.ID, int64(0))
+	assert.Equal(t, "Tech", pie.Settings.Name)
+	require.Len(t, pie.Instruments, 2)
+	assert.Equal(t, "AAPL_US_EQ", pie.Instruments[0].Ticker)
+
+	updated, err := client.UpdatePie(ctx, 7, PieRequest{Name: "Big Tech"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), updated.Settings.ID)
+
+	_, err = client.DuplicatePie(ctx, 7, DuplicatePieRequest{Name: "Copy"})
+	require.NoError(t, err)
+	require.NoError(t, client.DeletePie(ctx, 7))
+	assert.Error(t, client.DeletePie(ctx, 0))
+
+	entries, err := journal.Query(JournalQuery{})
+	require.NoError(t, err)
+	require.Len(t, entries, 10)
+	for _, entry := range entries {
+		assert.True(t, entry.DryRun)
+	}
+	assert.Equal(t, "CreatePie", entries[0].Operation)
+	assert.Equal(t, pie.Settings.ID, entries[1].PieID)
+	assert.Equal(t, int64(7), entries[6].PieID)
+	assert.Contains(t, entries[9].Error, "pie ID is required")
+}
+
+func TestPies_SentAndJournaled(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodPost {
+			json.NewEncoder(w).Encode(PieDetails{Settings: PieSettings{ID: 42, Name: "Tech"}})
+		}
+	}))
+	defer server.Close()
+
+	journal := NewMemoryJournal()
+	client := NewClient(Environment(server.URL), "key", "secret")
+	client.SetOrderJournal(journal)
+	ctx := context.Background()
+
+	pie, err := client.CreatePie(ctx, PieRequest{Name: "Tech"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), pie.Settings.ID)
+	require.NoError(t, client.DeletePie(ctx, 42))
+	assert.Equal(t, []string{"POST /api/v0/equity/pies", "DELETE /api/v0/equity/pies/42"}, requests)
+
+	entries, err := journal.Query(JournalQuery{Kind: JournalEntryResponse})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, int64(42), entries[0].PieID)
+	assert.Equal(t, "DeletePie", entries[1].Operation)
+	assert.False(t, entries[1].DryRun)
+}
+
+func TestDryRun_LogsWithoutJournal(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected %s %s in dry-run mode", r.Method, r.URL.Path)
+	}))
+	defer server.Close()
+
+	client := NewClient(Environment(server.URL), "key", "secret")
+	client.SetDryRun(true)
+	var calls []DryRunCall
+	client.SetDryRunLogger(func(call DryRunCall) {
+		calls = append(calls, call)
+	})
+	ctx := context.Background()
+
+	_, err := client.PlaceMarketOrder(ctx, MarketOrderRequest{Ticker: "AAPL_US_EQ", Quantity: 1})
+	require.NoError(t, err)
+	_, err = client.PlaceMarketOrder(ctx, MarketOrderRequest{Ticker: "AAPL_US_EQ"})
+	require.Error(t, err)
+	require.NoError(t, client.CancelOrder(ctx, 5))
+	require.NoError(t, client.DeletePie(ctx, 9))
+
+	require.Len(t, calls, 4)
+	assert.Equal(t, "PlaceMarketOrder", calls[0].Operation)
+	assert.Equal(t, MarketOrderRequest{Ticker: "AAPL_US_EQ", Quantity: 1}, calls[0].Request)
+	assert.NoError(t, calls[0].Err)
+	assert.Error(t, calls[1].Err)
+	assert.Equal(t, int64(5), calls[2].OrderID)
+	assert.Equal(t, "DeletePie", calls[3].Operation)
+	assert.Equal(t, int64(9), calls[3].PieID)
+
+	// Without a logger the call goes to the standard logger
+	var out bytes.Buffer
+	log.SetOutput(&out)
+	defer log.SetOutput(os.Stderr)
+	client.SetDryRunLogger(nil)
+	_, err = client.PlaceMarketOrder(ctx, MarketOrderRequest{Ticker: "AAPL_US_EQ", Quantity: 1})
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), `trading212: dry run PlaceMarketOrder {"extendedHours":false,"quantity":1,"ticker":"AAPL_US_EQ"}`)
+}
@@ -0,0 +1,324 @@
+package trading212
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownEnumValue is returned by Validate for values the SDK does not
+// know about. The API may add values at any time, so decoding never fails on
+// them; callers decide whether an unknown value is an error.
+var ErrUnknownEnumValue = errors.New("unknown enum value")
+
+// FillType represents the kind of event that produced a fill
+type FillType string
+
+const (
+	FillTypeTrade                   FillType = "TRADE"
+	FillTypeStockSplit              FillType = "STOCK_SPLIT"
+	FillTypeStockDistribution       FillType = "STOCK_DISTRIBUTION"
+	FillTypeFOP                     FillType = "FOP"
+	FillTypeFOPCorrection           FillType = "FOP_CORRECTION"
+	FillTypeCustomStockDistribution FillType = "CUSTOM_STOCK_DISTRIBUTION"
+	FillTypeEquityRights            FillType = "EQUITY_RIGHTS"
+)
+
+var fillTypes = map[FillType]bool{
+	FillTypeTrade:                   true,
+	FillTypeStockSplit:              true,
+	FillTypeStockDistribution:       true,
+	FillTypeFOP:                     true,
+	FillTypeFOPCorrection:           true,
+	FillTypeCustomStockDistribution: true,
+	FillTypeEquityRights:            true,
+}
+
+// String returns the API value
+func (t FillType) String() string {
+	return string(t)
+}
+
+// IsKnown reports whether t is one of the values defined by the API
+func (t FillType) IsKnown() bool {
+	return fillTypes[t]
+}
+
+// Validate returns an error wrapping ErrUnknownEnumValue for unknown values
+func (t FillType) Validate() error {
+	return validateEnum("fill type", string(t), t.IsKnown())
+}
+
+// IsTrade reports whether the fill is an ordinary buy or sell
+func (t FillType) IsTrade() bool {
+	return t == FillTypeTrade
+}
+
+// IsCorporateAction reports whether the fill was created by a corporate
+// action such as a split or a stock distribution rather than by trading
+func (t FillType) IsCorporateAction() bool {
+	switch t {
+	case FillTypeStockSplit, FillTypeStockDistribution, FillTypeCustomStockDistribution, FillTypeEquityRights:
+		return true
+	}
+	return false
+}
+
+// IsTransfer reports whether the fill is a free of payment transfer of
+// shares in or out of the account, or a correction of one
+func (t FillType) IsTransfer() bool {
+	return t == FillTypeFOP || t == FillTypeFOPCorrection
+}
+
+// FillTradingMethod represents the venue type a fill was executed on
+type FillTradingMethod string
+
+const (
+	// FillTradingMethodTOTV is a fill on a trading venue
+	FillTradingMethodTOTV FillTradingMethod = "TOTV"
+	// FillTradingMethodOTC is an over the counter fill
+	FillTradingMethodOTC FillTradingMethod = "OTC"
+)
+
+// String returns the API value
+func (m FillTradingMethod) String() string {
+	return string(m)
+}
+
+// IsKnown reports whether m is one of the values defined by the API
+func (m FillTradingMethod) IsKnown() bool {
+	return m == FillTradingMethodTOTV || m == FillTradingMethodOTC
+}
+
+// Validate returns an error wrapping ErrUnknownEnumValue for unknown values
+func (m FillTradingMethod) Validate() error {
+	return validateEnum("fill trading method", string(m), m.IsKnown())
+}
+
+// DividendType represents the kind of a dividend payment. Values ending in
+// _MANUFACTURED_PAYMENT are paid in lieu of a dividend while shares were lent
+// out, and the US values follow the 1042-S income codes.
+type DividendType string
+
+const (
+	DividendTypeOrdinary                                                          DividendType = "ORDINARY"
+	DividendTypeBonus                                                             DividendType = "BONUS"
+	DividendTypePropertyIncome                                                    DividendType = "PROPERTY_INCOME"
+	DividendTypeReturnOfCapitalNonUS                                              DividendType = "RETURN_OF_CAPITAL_NON_US"
+	DividendTypeDemerger                                                          DividendType = "DEMERGER"
+	DividendTypeInterest                                                          DividendType = "INTEREST"
+	DividendTypeCapitalGainsDistributionNonUS                                     DividendType = "CAPITAL_GAINS_DISTRIBUTION_NON_US"
+	DividendTypeInterimLiquidation                                                DividendType = "INTERIM_LIQUIDATION"
+	DividendTypeOrdinaryManufacturedPayment                                       DividendType = "ORDINARY_MANUFACTURED_PAYMENT"
+	DividendTypeBonusManufacturedPayment                                          DividendType = "BONUS_MANUFACTURED_PAYMENT"
+	DividendTypePropertyIncomeManufacturedPayment                                 DividendType = "PROPERTY_INCOME_MANUFACTURED_PAYMENT"
+	DividendTypeReturnOfCapitalNonUSManufacturedPayment                           DividendType = "RETURN_OF_CAPITAL_NON_US_MANUFACTURED_PAYMENT"
+	DividendTypeDemergerManufacturedPayment                                       DividendType = "DEMERGER_MANUFACTURED_PAYMENT"
+	DividendTypeInterestManufacturedPayment                                       DividendType = "INTEREST_MANUFACTURED_PAYMENT"
+	DividendTypeCapitalGainsDistributionNonUSManufacturedPayment                  DividendType = "CAPITAL_GAINS_DISTRIBUTION_NON_US_MANUFACTURED_PAYMENT"
+	DividendTypeInterimLiquidationManufacturedPayment                             DividendType = "INTERIM_LIQUIDATION_MANUFACTURED_PAYMENT"
+	DividendTypeInterestPaidByUSObligors                                          DividendType = "INTEREST_PAID_BY_US_OBLIGORS"
+	DividendTypeInterestPaidByForeignCorporations                                 DividendType = "INTEREST_PAID_BY_FOREIGN_CORPORATIONS"
+	DividendTypeDividendsPaidByUSCorporations                                     DividendType = "DIVIDENDS_PAID_BY_US_CORPORATIONS"
+	DividendTypeDividendsPaidByForeignCorporations                                DividendType = "DIVIDENDS_PAID_BY_FOREIGN_CORPORATIONS"
+	DividendTypeCapitalGains                                                      DividendType = "CAPITAL_GAINS"
+	DividendTypeRealPropertyIncomeAndNaturalResourcesRoyalties                    DividendType = "REAL_PROPERTY_INCOME_AND_NATURAL_RESOURCES_ROYALTIES"
+	DividendTypeOtherIncome                                                       DividendType = "OTHER_INCOME"
+	DividendTypeQualifiedInvestmentEntity                                         DividendType = "QUALIFIED_INVESTMENT_ENTITY"
+	DividendTypeTrustDistribution                                                 DividendType = "TRUST_DISTRIBUTION"
+	DividendTypePubliclyTradedPartnershipDistribution                             DividendType = "PUBLICLY_TRADED_PARTNERSHIP_DISTRIBUTION"
+	DividendTypeCapitalGainsDistribution                                          DividendType = "CAPITAL_GAINS_DISTRIBUTION"
+	DividendTypeReturnOfCapital                                                   DividendType = "RETURN_OF_CAPITAL"
+	DividendTypeOtherDividendEquivalent                                           DividendType = "OTHER_DIVIDEND_EQUIVALENT"
+	DividendTypeTaxEvent1446FForPubliclyTradedSecurities                          DividendType = "TAX_EVENT_1446F_FOR_PUBLICLY_TRADED_SECURITIES"
+	DividendTypePTPUncharacterisedIncome                                          DividendType = "PTP_UNCHARACTERISED_INCOME"
+	DividendTypeMultiple1042STaxComponents                                        DividendType = "MULTIPLE_1042S_TAX_COMPONENTS"
+	DividendTypeDividend                                                          DividendType = "DIVIDEND"
+	DividendTypeShortTermCapitalGains                                             DividendType = "SHORT_TERM_CAPITAL_GAINS"
+	DividendTypeLongTermCapitalGains                                              DividendType = "LONG_TERM_CAPITAL_GAINS"
+	DividendTypePropertyIncomeDistribution                                        DividendType = "PROPERTY_INCOME_DISTRIBUTION"
+	DividendTypeTaxExempted                                                       DividendType = "TAX_EXEMPTED"
+	DividendTypeInterestPaidByUSObligorsManufacturedPayment                       DividendType = "INTEREST_PAID_BY_US_OBLIGORS_MANUFACTURED_PAYMENT"
+	DividendTypeInterestPaidByForeignCorporationsManufacturedPayment              DividendType = "INTEREST_PAID_BY_FOREIGN_CORPORATIONS_MANUFACTURED_PAYMENT"
+	DividendTypeDividendsPaidByUSCorporationsManufacturedPayment                  DividendType = "DIVIDENDS_PAID_BY_US_CORPORATIONS_MANUFACTURED_PAYMENT"
+	DividendTypeDividendsPaidByForeignCorporationsManufacturedPayment             DividendType = "DIVIDENDS_PAID_BY_FOREIGN_CORPORATIONS_MANUFACTURED_PAYMENT"
+	DividendTypeCapitalGainsManufacturedPayment                                   DividendType = "CAPITAL_GAINS_MANUFACTURED_PAYMENT"
+	DividendTypeRealPropertyIncomeAndNaturalResourcesRoyaltiesManufacturedPayment DividendType = "REAL_PROPERTY_INCOME_AND_NATURAL_RESOURCES_ROYALTIES_MANUFACTURED_PAYMENT"
+	DividendTypeOtherIncomeManufacturedPayment                                    DividendType = "OTHER_INCOME_MANUFACTURED_PAYMENT"
+	DividendTypeQualifiedInvestmentEntityManufacturedPayment                      DividendType = "QUALIFIED_INVESTMENT_ENTITY_MANUFACTURED_PAYMENT"
+	DividendTypeTrustDistributionManufacturedPayment                              DividendType = "TRUST_DISTRIBUTION_MANUFACTURED_PAYMENT"
+	DividendTypePubliclyTradedPartnershipDistributionManufacturedPayment          DividendType = "PUBLICLY_TRADED_PARTNERSHIP_DISTRIBUTION_MANUFACTURED_PAYMENT"
+	DividendTypeCapitalGainsDistributionManufacturedPayment                       DividendType = "CAPITAL_GAINS_DISTRIBUTION_MANUFACTURED_PAYMENT"
+	DividendTypeReturnOfCapitalManufacturedPayment                                DividendType = "RETURN_OF_CAPITAL_MANUFACTURED_PAYMENT"
+	DividendTypeOtherDividendEquivalentManufacturedPayment                        DividendType = "OTHER_DIVIDEND_EQUIVALENT_MANUFACTURED_PAYMENT"
+	DividendTypeTaxEvent1446FForPubliclyTradedSecuritiesManufacturedPayment       DividendType = "TAX_EVENT_1446F_FOR_PUBLICLY_TRADED_SECURITIES_MANUFACTURED_PAYMENT"
+	DividendTypePTPUncharacterisedIncomeManufacturedPayment                       DividendType = "PTP_UNCHARACTERISED_INCOME_MANUFACTURED_PAYMENT"
+	DividendTypeMultiple1042STaxComponentsManufacturedPayment                     DividendType = "MULTIPLE_1042S_TAX_COMPONENTS_MANUFACTURED_PAYMENT"
+	DividendTypeDividendManufacturedPayment                                       DividendType = "DIVIDEND_MANUFACTURED_PAYMENT"
+	DividendTypeShortTermCapitalGainsManufacturedPayment                          DividendType = "SHORT_TERM_CAPITAL_GAINS_MANUFACTURED_PAYMENT"
+	DividendTypeLongTermCapitalGainsManufacturedPayment                           DividendType = "LONG_TERM_CAPITAL_GAINS_MANUFACTURED_PAYMENT"
+	DividendTypePropertyIncomeDistributionManufacturedPayment                     DividendType = "PROPERTY_INCOME_DISTRIBUTION_MANUFACTURED_PAYMENT"
+	DividendTypeTaxExemptedManufacturedPayment                                    DividendType = "TAX_EXEMPTED_MANUFACTURED_PAYMENT"
+)
+
+// manufacturedPaymentSuffix marks payments made in lieu of a dividend
+const manufacturedPaymentSuffix = "_MANUFACTURED_PAYMENT"
+
+var dividendTypes = map[DividendType]bool{
+	DividendTypeOrdinary:                                                          true,
+	DividendTypeBonus:                                                             true,
+	DividendTypePropertyIncome:                                                    true,
+	DividendTypeReturnOfCapitalNonUS:                                              true,
+	DividendTypeDemerger:                                                          true,
+	DividendTypeInterest:                                                          true,
+	DividendTypeCapitalGainsDistributionNonUS:                                     true,
+	DividendTypeInterimLiquidation:                                                true,
+	DividendTypeOrdinaryManufacturedPayment:                                       true,
+	DividendTypeBonusManufacturedPayment:                                          true,
+	DividendTypePropertyIncomeManufacturedPayment:                                 true,
+	DividendTypeReturnOfCapitalNonUSManufacturedPayment:                           true,
+	DividendTypeDemergerManufacturedPayment:                                       true,
+	DividendTypeInterestManufacturedPayment:                                       true,
+	DividendTypeCapitalGainsDistributionNonUSManufacturedPayment:                  true,
+	DividendTypeInterimLiquidationManufacturedPayment:                             true,
+	DividendTypeInterestPaidByUSObligors:                                          true,
+	DividendTypeInterestPaidByForeignCorporations:                                 true,
+	DividendTypeDividendsPaidByUSCorporations:                                     true,
+	DividendTypeDividendsPaidByForeignCorporations:                                true,
+	DividendTypeCapitalGains:                                                      true,
+	DividendTypeRealPropertyIncomeAndNaturalResourcesRoyalties:                    true,
+	DividendTypeOtherIncome:                                                       true,
+	DividendTypeQualifiedInvestmentEntity:                                         true,
+	DividendTypeTrustDistribution:                                                 true,
+	DividendTypePubliclyTradedPartnershipDistribution:                             true,
+	DividendTypeCapitalGainsDistribution:                                          true,
+	DividendTypeReturnOfCapital:                                                   true,
+	DividendTypeOtherDividendEquivalent:                                           true,
+	DividendTypeTaxEvent1446FForPubliclyTradedSecurities:                          true,
+	DividendTypePTPUncharacterisedIncome:                                          true,
+	DividendTypeMultiple1042STaxComponents:                                        true,
+	DividendTypeDividend:                                                          true,
+	DividendTypeShortTermCapitalGains:                                             true,
+	DividendTypeLongTermCapitalGains:                                              true,
+	DividendTypePropertyIncomeDistribution:                                        true,
+	DividendTypeTaxExempted:                                                       true,
+	DividendTypeInterestPaidByUSObligorsManufacturedPayment:                       true,
+	DividendTypeInterestPaidByForeignCorporationsManufacturedPayment:              true,
+	DividendTypeDividendsPaidByUSCorporationsManufacturedPayment:                  true,
+	DividendTypeDividendsPaidByForeignCorporationsManufacturedPayment:             true,
+	DividendTypeCapitalGainsManufacturedPayment:                                   true,
+	DividendTypeRealPropertyIncomeAndNaturalResourcesRoyaltiesManufacturedPayment: true,
+	DividendTypeOtherIncomeManufacturedPayment:                                    true,
+	DividendTypeQualifiedInvestmentEntityManufacturedPayment:                      true,
+	DividendTypeTrustDistributionManufacturedPayment:                              true,
+	DividendTypePubliclyTradedPartnershipDistributionManufacturedPayment:          true,
+	DividendTypeCapitalGainsDistributionManufacturedPayment:                       true,
+	DividendTypeReturnOfCapitalManufacturedPayment:                                true,
+	DividendTypeOtherDividendEquivalentManufacturedPayment:                        true,
+	DividendTypeTaxEvent1446FForPubliclyTradedSecuritiesManufacturedPayment:       true,
+	DividendTypePTPUncharacterisedIncomeManufacturedPayment:                       true,
+	DividendTypeMultiple1042STaxComponentsManufacturedPayment:                     true,
+	DividendTypeDividendManufacturedPayment:                                       true,
+	DividendTypeShortTermCapitalGainsManufacturedPayment:                          true,
+	DividendTypeLongTermCapitalGainsManufacturedPayment:                           true,
+	DividendTypePropertyIncomeDistributionManufacturedPayment:                     true,
+	DividendTypeTaxExemptedManufacturedPayment:                                    true,
+}
+
+// String returns the API value
+func (t DividendType) String() string {
+	return string(t)
+}
+
+// IsKnown reports whether t is one of the values defined by the API
+func (t DividendType) IsKnown() bool {
+	return dividendTypes[t]
+}
+
+// Validate returns an error wrapping ErrUnknownEnumValue for unknown values
+func (t DividendType) Validate() error {
+	return validateEnum("dividend type", string(t), t.IsKnown())
+}
+
+// IsManufacturedPayment reports whether the payment was made in lieu of a
+// dividend, which is often taxed differently from the dividend itself
+func (t DividendType) IsManufacturedPayment() bool {
+	return strings.HasSuffix(string(t), manufacturedPaymentSuffix)
+}
+
+// Underlying returns the dividend type a manufactured payment replaces, or t
+// itself for other types
+func (t DividendType) Underlying() DividendType {
+	return DividendType(strings.TrimSuffix(string(t), manufacturedPaymentSuffix))
+}
+
+// IsReturnOfCapital reports whether the payment returns capital rather than
+// distributing income
+func (t DividendType) IsReturnOfCapital() bool {
+	switch t.Underlying() {
+	case DividendTypeReturnOfCapital, DividendTypeReturnOfCapitalNonUS:
+		return true
+	}
+	return false
+}
+
+// IsInterest reports whether the payment is interest rather than a dividend
+func (t DividendType) IsInterest() bool {
+	switch t.Underlying() {
+	case DividendTypeInterest, DividendTypeInterestPaidByUSObligors, DividendTypeInterestPaidByForeignCorporations:
+		return true
+	}
+	return false
+}
+
+// IsCorporateAction reports whether the payment stems from a corporate
+// action such as a demerger or a liquidation rather than regular income
+func (t DividendType) IsCorporateAction() bool {
+	switch t.Underlying() {
+	case DividendTypeDemerger, DividendTypeInterimLiquidation, DividendTypeBonus:
+		return true
+	}
+	return false
+}
+
+// TransactionType represents the kind of a cash transaction
+type TransactionType string
+
+const (
+	TransactionTypeWithdraw TransactionType = "WITHDRAW"
+	TransactionTypeDeposit  TransactionType = "DEPOSIT"
+	TransactionTypeFee      TransactionType = "FEE"
+	TransactionTypeTransfer TransactionType = "TRANSFER"
+)
+
+// String returns the API value
+func (t TransactionType) String() string {
+	return string(t)
+}
+
+// IsKnown reports whether t is one of the values defined by the API
+func (t TransactionType) IsKnown() bool {
+	switch t {
+	case TransactionTypeWithdraw, TransactionTypeDeposit, TransactionTypeFee, TransactionTypeTransfer:
+		return true
+	}
+	return false
+}
+
+// Validate returns an error wrapping ErrUnknownEnumValue for unknown values
+func (t TransactionType) Validate() error {
+	return validateEnum("transaction type", string(t), t.IsKnown())
+}
+
+// IsExternalFlow reports whether the transaction moves money into or out of
+// the account, as opposed to a fee charged by the broker
+func (t TransactionType) IsExternalFlow() bool {
+	return t == TransactionTypeDeposit || t == TransactionTypeWithdraw || t == TransactionTypeTransfer
+}
+
+func validateEnum(kind, value string, known bool) error {
+	if known {
+		return nil
+	}
+	return fmt.Errorf("%w: %s %q", ErrUnknownEnumValue, kind, value)
+}
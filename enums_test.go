@@ -0,0 +1,60 @@
+package trading212
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnums_DecodeUnknownValues(t *testing.T) {
+	var order HistoricalOrder
+	require.NoError(t, json.Unmarshal([]byte(`{"fill":{"type":"SOMETHING_NEW","tradingMethod":"OTC"}}`), &order))
+	assert.Equal(t, FillType("SOMETHING_NEW"), order.Fill.Type)
+	assert.False(t, order.Fill.Type.IsKnown())
+	assert.ErrorIs(t, order.Fill.Type.Validate(), ErrUnknownEnumValue)
+	assert.NoError(t, order.Fill.TradingMethod.Validate())
+	assert.Equal(t, "OTC", order.Fill.TradingMethod.String())
+
+	var dividend HistoryDividendItem
+	require.NoError(t, json.Unmarshal([]byte(`{"type":"ORDINARY_MANUFACTURED_PAYMENT"}`), &dividend))
+	assert.Equal(t, DividendTypeOrdinaryManufacturedPayment, dividend.Type)
+	assert.NoError(t, dividend.Type.Validate())
+
+	var transaction HistoryTransactionItem
+	require.NoError(t, json.Unmarshal([]byte(`{"type":"DEPOSIT"}`), &transaction))
+	assert.Equal(t, TransactionTypeDeposit, transaction.Type)
+}
+
+func TestFillType_Classification(t *testing.T) {
+	assert.True(t, FillTypeTrade.IsTrade())
+	assert.False(t, FillTypeTrade.IsCorporateAction())
+	assert.True(t, FillTypeStockSplit.IsCorporateAction())
+	assert.True(t, FillTypeCustomStockDistribution.IsCorporateAction())
+	assert.True(t, FillTypeFOPCorrection.IsTransfer())
+	assert.False(t, FillType("NEW").IsCorporateAction())
+}
+
+func TestDividendType_Classification(t *testing.T) {
+	for dividendType := range dividendTypes {
+		assert.True(t, dividendType.Underlying().IsKnown(), dividendType)
+	}
+
+	assert.True(t, DividendTypeDividendsPaidByUSCorporationsManufacturedPayment.IsManufacturedPayment())
+	assert.Equal(t, DividendTypeDividendsPaidByUSCorporations, DividendTypeDividendsPaidByUSCorporationsManufacturedPayment.Underlying())
+	assert.False(t, DividendTypeOrdinary.IsManufacturedPayment())
+	assert.Equal(t, DividendTypeOrdinary, DividendTypeOrdinary.Underlying())
+
+	assert.True(t, DividendTypeReturnOfCapitalNonUSManufacturedPayment.IsReturnOfCapital())
+	assert.True(t, DividendTypeInterestPaidByUSObligors.IsInterest())
+	assert.True(t, DividendTypeDemerger.IsCorporateAction())
+	assert.False(t, DividendTypeOrdinary.IsCorporateAction())
+}
+
+func TestTransactionType(t *testing.T) {
+	assert.True(t, TransactionTypeWithdraw.IsExternalFlow())
+	assert.False(t, TransactionTypeFee.IsExternalFlow())
+	assert.NoError(t, TransactionTypeTransfer.Validate())
+	assert.ErrorIs(t, TransactionType("REFUND").Validate(), ErrUnknownEnumValue)
+}
@@ -0,0 +1,544 @@
+// Package execution provides order slicing algorithms that split a large
+// parent order into smaller child market orders over time.
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+)
+
+// OrderPlacer is the subset of the client used to submit child orders
+type OrderPlacer interface {
+	PlaceMarketOrder(ctx context.Context, req trading212.MarketOrderRequest) (*trading212.Order, error)
+}
+
+// OrderTracker looks up child orders after they are placed, so fills are
+// tracked; *trading212.Client implements it. Orders that have left the
+// pending list are looked up in the order history.
+type OrderTracker interface {
+	GetOrderByID(ctx context.Context, orderID int64) (*trading212.Order, error)
+	GetHistoricalOrders(ctx context.Context, opts *trading212.HistoryOrdersOptions) (*trading212.PaginatedResponse[trading212.HistoricalOrder], error)
+}
+
+// MarketHours reports whether an instrument can currently be traded
+type MarketHours interface {
+	IsOpen(ticker string, t time.Time) (bool, error)
+}
+
+// PriceSource returns the latest price of an instrument, used to convert
+// value-based slices into quantities
+type PriceSource interface {
+	Price(ctx context.Context, ticker string) (float64, error)
+}
+
+// State represents the lifecycle state of a parent order
+type State string
+
+const (
+	StatePending   State = "PENDING"
+	StateRunning   State = "RUNNING"
+	StatePaused    State = "PAUSED"
+	StateCancelled State = "CANCELLED"
+	StateCompleted State = "COMPLETED"
+	StateFailed    State = "FAILED"
+)
+
+// ErrCancelled is returned by Run when the parent order was cancelled
+var ErrCancelled = errors.New("parent order cancelled")
+
+// Config describes a TWAP parent order. Exactly one of Quantity and Value
+// must be set; negative values sell.
+type Config struct {
+	Ticker string
+	// Quantity is the total number of shares to trade
+	Quantity float64
+	// Value is the total cash amount to trade, converted to quantity per slice
+	// using Prices
+	Value float64
+	// Duration is the time over which the slices are spread
+	Duration time.Duration
+	// Slices is the minimum number of child orders
+	Slices int
+	// MaxChildQuantity caps the size of a single child order (0 for no cap)
+	MaxChildQuantity float64
+	// MaxChildValue caps the value of a single child order for value-based
+	// slicing (0 for no cap)
+	MaxChildValue float64
+	// Jitter randomises each interval by up to this fraction (0 to 1)
+	Jitter float64
+	// QuantityPrecision is the number of decimal places child quantities are
+	// rounded down to (0 leaves quantities unrounded)
+	QuantityPrecision int
+	// ExtendedHours is passed through to every child order
+	ExtendedHours bool
+	// Hours, when set, restricts child orders to times the market is open
+	Hours MarketHours
+	// Prices is required for value-based slicing
+	Prices PriceSource
+	// ClosedPollInterval is how often market hours are re-checked while the
+	// market is closed (defaults to 1 minute)
+	ClosedPollInterval time.Duration
+	// FillPollInterval is how often unfinished child orders are looked up
+	// when the placer is an OrderTracker (defaults to 5 seconds)
+	FillPollInterval time.Duration
+	// Rand is the source used for jitter (defaults to a time-seeded source)
+	Rand *rand.Rand
+}
+
+// ChildOrder represents a single slice sent to the API
+type ChildOrder struct {
+	// Index is the slice number; slices that round to nothing are skipped
+	Index    int
+	Quantity float64
+	Value    float64
+	PlacedAt time.Time
+	Order    *trading212.Order
+	Err      error
+}
+
+// Progress represents a point-in-time view of a parent order
+type Progress struct {
+	State             State
+	TargetQuantity    float64
+	TargetValue       float64
+	SubmittedQuantity float64
+	SubmittedValue    float64
+	FilledQuantity    float64
+	FilledValue       float64
+	Children          []ChildOrder
+}
+
+// ParentOrder executes a Config by slicing it into child market orders
+type ParentOrder struct {
+	placer OrderPlacer
+	cfg    Config
+	slices int
+
+	mu       sync.Mutex
+	state    State
+	children []ChildOrder
+	wake     chan struct{}
+
+	now   func() time.Time
+	after func(time.Duration) <-chan time.Time
+}
+
+// NewTWAP validates cfg and returns a parent order ready to Run
+func NewTWAP(placer OrderPlacer, cfg Config) (*ParentOrder, error) {
+	if cfg.Ticker == "" {
+		return nil, errors.New("ticker is required")
+	}
+	if (cfg.Quantity == 0) == (cfg.Value == 0) {
+		return nil, errors.New("exactly one of quantity and value must be set")
+	}
+	if cfg.Value != 0 && cfg.Prices == nil {
+		return nil, errors.New("a price source is required for value-based slicing")
+	}
+	if cfg.Duration < 0 {
+		return nil, errors.New("duration must not be negative")
+	}
+	if cfg.Jitter < 0 || cfg.Jitter > 1 {
+		return nil, errors.New("jitter must be between 0 and 1")
+	}
+	if cfg.ClosedPollInterval <= 0 {
+		cfg.ClosedPollInterval = time.Minute
+	}
+	if cfg.FillPollInterval <= 0 {
+		cfg.FillPollInterval = 5 * time.Second
+	}
+	if cfg.Rand == nil {
+		cfg.Rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	slices := cfg.Slices
+	if slices < 1 {
+		slices = 1
+	}
+	if cfg.Quantity != 0 && cfg.MaxChildQuantity > 0 {
+		if n := int(math.Ceil(math.Abs(cfg.Quantity) / cfg.MaxChildQuantity)); n > slices {
+			slices = n
+		}
+	}
+	if cfg.Value != 0 && cfg.MaxChildValue > 0 {
+		if n := int(math.Ceil(math.Abs(cfg.Value) / cfg.MaxChildValue)); n > slices {
+			slices = n
+		}
+	}
+
+	return &ParentOrder{
+		placer: placer,
+		cfg:    cfg,
+		slices: slices,
+		state:  StatePending,
+		wake:   make(chan struct{}, 1),
+		now:    time.Now,
+		after:  time.After,
+	}, nil
+}
+
+// Run executes the schedule, blocking until every slice has been submitted
+// and, when the placer is an OrderTracker, every child order has finished;
+// or until the parent is cancelled, a child order fails or ctx is done
+func (p *ParentOrder) Run(ctx context.Context) error {
+	p.mu.Lock()
+	if p.state != StatePending {
+		p.mu.Unlock()
+		return fmt.Errorf("parent order already %s", p.state)
+	}
+	p.state = StateRunning
+	p.mu.Unlock()
+
+	interval := time.Duration(0)
+	if p.slices > 1 {
+		interval = p.cfg.Duration / time.Duration(p.slices-1)
+	}
+
+	// Slices past the planned count carry forward what capped children
+	// could not take, and stop once the rest rounds to nothing
+	for i := 0; i < p.slices || p.outstanding(); i++ {
+		if i > 0 {
+			if err := p.wait(ctx, p.jittered(interval)); err != nil {
+				return err
+			}
+		}
+		if err := p.waitForOpen(ctx); err != nil {
+			return err
+		}
+		if err := p.waitWhilePaused(ctx); err != nil {
+			return err
+		}
+		p.refresh(ctx)
+		placed, err := p.placeChild(ctx, i)
+		if err != nil {
+			p.setState(StateFailed)
+			return err
+		}
+		if !placed && i >= p.slices-1 {
+			break
+		}
+	}
+
+	for p.refresh(ctx) {
+		if err := p.wait(ctx, p.cfg.FillPollInterval); err != nil {
+			return err
+		}
+	}
+
+	p.setState(StateCompleted)
+	return nil
+}
+
+// Pause stops new child orders from being placed until Resume is called
+func (p *ParentOrder) Pause() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.state == StateRunning {
+		p.state = StatePaused
+		p.signal()
+	}
+}
+
+// Resume continues a paused parent order
+func (p *ParentOrder) Resume() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.state == StatePaused {
+		p.state = StateRunning
+		p.signal()
+	}
+}
+
+// Cancel stops the parent order; child orders already placed are not cancelled
+func (p *ParentOrder) Cancel() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch p.state {
+	case StatePending, StateRunning, StatePaused:
+		p.state = StateCancelled
+		p.signal()
+	}
+}
+
+// Progress returns the current state and fill totals of the parent order
+func (p *ParentOrder) Progress() Progress {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	progress := Progress{
+		State:          p.state,
+		TargetQuantity: p.cfg.Quantity,
+		TargetValue:    p.cfg.Value,
+		Children:       append([]ChildOrder(nil), p.children...),
+	}
+	for _, child := range p.children {
+		if child.Err != nil {
+			continue
+		}
+		progress.SubmittedQuantity += child.Quantity
+		progress.SubmittedValue += child.Value
+		if child.Order != nil {
+			progress.FilledQuantity += child.Order.FilledQuantity
+			progress.FilledValue += child.Order.FilledValue
+		}
+	}
+	return progress
+}
+
+// placeChild sizes and submits the i-th child order, reporting whether
+// there was anything to place
+func (p *ParentOrder) placeChild(ctx context.Context, i int) (bool, error) {
+	remaining := p.slices - i
+	if remaining < 1 {
+		remaining = 1
+	}
+	child := ChildOrder{Index: i}
+
+	if p.cfg.Quantity != 0 {
+		child.Quantity = p.sliceSize(p.cfg.Quantity-p.submittedQuantity(), remaining, p.cfg.MaxChildQuantity)
+	} else {
+		child.Value = p.sliceSize(p.cfg.Value-p.submittedValue(), remaining, p.cfg.MaxChildValue)
+		price, err := p.cfg.Prices.Price(ctx, p.cfg.Ticker)
+		if err != nil {
+			return false, fmt.Errorf("failed to get price for %s: %w", p.cfg.Ticker, err)
+		}
+		if price <= 0 {
+			return false, fmt.Errorf("invalid price %v for %s", price, p.cfg.Ticker)
+		}
+		child.Quantity = p.round(child.Value / price)
+		// Count only what is ordered, so truncation is made up later
+		child.Value = child.Quantity * price
+	}
+
+	if child.Quantity == 0 {
+		return false, nil
+	}
+
+	child.PlacedAt = p.now()
+	order, err := p.placer.PlaceMarketOrder(ctx, trading212.MarketOrderRequest{
+		ExtendedHours: p.cfg.ExtendedHours,
+		Quantity:      child.Quantity,
+		Ticker:        p.cfg.Ticker,
+	})
+	child.Order = order
+	child.Err = err
+
+	p.mu.Lock()
+	p.children = append(p.children, child)
+	p.mu.Unlock()
+
+	if err != nil {
+		return true, fmt.Errorf("failed to place child order %d: %w", i, err)
+	}
+	return true, nil
+}
+
+// sliceSize splits what is left evenly over the remaining slices, applying
+// the child cap and rounding; the last slice takes everything left, up to
+// the cap
+func (p *ParentOrder) sliceSize(left float64, remaining int, max float64) float64 {
+	size := left / float64(remaining)
+	if remaining == 1 {
+		size = left
+	}
+	if max > 0 && math.Abs(size) > max {
+		size = math.Copysign(max, size)
+	}
+	if p.cfg.Value == 0 {
+		return p.round(size)
+	}
+	return size
+}
+
+// round truncates a quantity towards zero to the configured precision
+func (p *ParentOrder) round(quantity float64) float64 {
+	if p.cfg.QuantityPrecision <= 0 {
+		return quantity
+	}
+	scale := math.Pow(10, float64(p.cfg.QuantityPrecision))
+	// The nudge keeps float noise such as 0.0999…98 from losing a whole step
+	return math.Trunc(quantity*scale+math.Copysign(1e-6, quantity)) / scale
+}
+
+// refresh looks up the child orders that have not finished and reports
+// whether any are still open. Lookup failures leave the child as it was to
+// be retried on the next refresh.
+func (p *ParentOrder) refresh(ctx context.Context) bool {
+	tracker, ok := p.placer.(OrderTracker)
+	if !ok {
+		return false
+	}
+
+	// Children are only ever appended, so positions stay valid; Index is
+	// the slice number, which skips slices that rounded to nothing
+	p.mu.Lock()
+	open := make(map[int]int64)
+	for i, child := range p.children {
+		if child.Order != nil && !finished(child.Order.Status) {
+			open[i] = child.Order.ID
+		}
+	}
+	p.mu.Unlock()
+
+	pending := false
+	for i, orderID := range open {
+		order, err := p.lookup(ctx, tracker, orderID)
+		if err != nil || order == nil {
+			pending = true
+			continue
+		}
+		if !finished(order.Status) {
+			pending = true
+		}
+		p.mu.Lock()
+		p.children[i].Order = order
+		p.mu.Unlock()
+	}
+	return pending
+}
+
+// lookup returns an order from the pending list or, once it has left it,
+// from recent order history; nil means it is in neither yet
+func (p *ParentOrder) lookup(ctx context.Context, tracker OrderTracker, orderID int64) (*trading212.Order, error) {
+	order, err := tracker.GetOrderByID(ctx, orderID)
+	var apiErr *trading212.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		return order, err
+	}
+
+	history, err := tracker.GetHistoricalOrders(ctx, &trading212.HistoryOrdersOptions{Ticker: p.cfg.Ticker, Limit: 50})
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range history.Items {
+		if item.Order.ID == orderID {
+			order := item.Order
+			return &order, nil
+		}
+	}
+	return nil, nil
+}
+
+// finished reports whether an order can no longer fill
+func finished(status trading212.OrderStatus) bool {
+	switch status {
+	case trading212.OrderStatusFilled, trading212.OrderStatusCancelled, trading212.OrderStatusRejected,
+		trading212.OrderStatusReplaced, trading212.OrderStatusLocal:
+		return true
+	}
+	return false
+}
+
+// outstanding reports whether part of the target has not been submitted
+func (p *ParentOrder) outstanding() bool {
+	if p.cfg.Quantity != 0 {
+		return math.Abs(p.round(p.cfg.Quantity-p.submittedQuantity())) > 1e-9*math.Abs(p.cfg.Quantity)
+	}
+	return math.Abs(p.cfg.Value-p.submittedValue()) > 1e-9*math.Abs(p.cfg.Value)
+}
+
+func (p *ParentOrder) submittedQuantity() float64 {
+	return p.Progress().SubmittedQuantity
+}
+
+func (p *ParentOrder) submittedValue() float64 {
+	return p.Progress().SubmittedValue
+}
+
+// jittered randomises an interval by up to the configured jitter fraction
+func (p *ParentOrder) jittered(interval time.Duration) time.Duration {
+	if p.cfg.Jitter == 0 || interval == 0 {
+		return interval
+	}
+	factor := 1 + p.cfg.Jitter*(2*p.cfg.Rand.Float64()-1)
+	return time.Duration(float64(interval) * factor)
+}
+
+// wait sleeps for d, returning early on cancellation
+func (p *ParentOrder) wait(ctx context.Context, d time.Duration) error {
+	timer := p.after(d)
+	for {
+		if err := p.checkCancelled(ctx); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer:
+			return p.checkCancelled(ctx)
+		case <-p.wake:
+		}
+	}
+}
+
+// waitWhilePaused blocks until the parent order is resumed or cancelled
+func (p *ParentOrder) waitWhilePaused(ctx context.Context) error {
+	for {
+		if err := p.checkCancelled(ctx); err != nil {
+			return err
+		}
+		p.mu.Lock()
+		paused := p.state == StatePaused
+		p.mu.Unlock()
+		if !paused {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-p.wake:
+		}
+	}
+}
+
+// waitForOpen blocks until the market for the ticker is open
+func (p *ParentOrder) waitForOpen(ctx context.Context) error {
+	if p.cfg.Hours == nil {
+		return nil
+	}
+	for {
+		open, err := p.cfg.Hours.IsOpen(p.cfg.Ticker, p.now())
+		if err != nil {
+			p.setState(StateFailed)
+			return fmt.Errorf("failed to check market hours for %s: %w", p.cfg.Ticker, err)
+		}
+		if open {
+			return nil
+		}
+		if err := p.wait(ctx, p.cfg.ClosedPollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *ParentOrder) checkCancelled(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.state == StateCancelled {
+		return ErrCancelled
+	}
+	return ctx.Err()
+}
+
+func (p *ParentOrder) setState(state State) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.state != StateCancelled {
+		p.state = state
+	}
+}
+
+// signal wakes the run loop; callers must hold p.mu
+func (p *ParentOrder) signal() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,310 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+)
+
+type fakePlacer struct {
+	mu       sync.Mutex
+	requests []trading212.MarketOrderRequest
+	err      error
+}
+
+func (f *fakePlacer) PlaceMarketOrder(ctx context.Context, req trading212.MarketOrderRequest) (*trading212.Order, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.requests = append(f.requests, req)
+	return &trading212.Order{
+		ID:             int64(len(f.requests)),
+		Ticker:         req.Ticker,
+		Quantity:       req.Quantity,
+		FilledQuantity: req.Quantity,
+		Status:         trading212.OrderStatusFilled,
+	}, nil
+}
+
+type fixedPrice float64
+
+func (p fixedPrice) Price(ctx context.Context, ticker string) (float64, error) {
+	return float64(p), nil
+}
+
+type scriptedHours struct {
+	open []bool
+}
+
+func (h *scriptedHours) IsOpen(ticker string, t time.Time) (bool, error) {
+	if len(h.open) == 0 {
+		return true, nil
+	}
+	open := h.open[0]
+	h.open = h.open[1:]
+	return open, nil
+}
+
+func immediate(time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	ch <- time.Time{}
+	return ch
+}
+
+func TestNewTWAP_Validation(t *testing.T) {
+	_, err := NewTWAP(&fakePlacer{}, Config{Quantity: 1})
+	assert.Error(t, err)
+
+	_, err = NewTWAP(&fakePlacer{}, Config{Ticker: "AAPL_US_EQ", Quantity: 1, Value: 10})
+	assert.Error(t, err)
+
+	_, err = NewTWAP(&fakePlacer{}, Config{Ticker: "AAPL_US_EQ", Value: 10})
+	assert.Error(t, err)
+
+	_, err = NewTWAP(&fakePlacer{}, Config{Ticker: "AAPL_US_EQ", Quantity: 1, Jitter: 2})
+	assert.Error(t, err)
+}
+
+func TestParentOrder_QuantitySlicing(t *testing.T) {
+	placer := &fakePlacer{}
+	parent, err := NewTWAP(placer, Config{
+		Ticker:           "AAPL_US_EQ",
+		Quantity:         10,
+		Slices:           2,
+		MaxChildQuantity: 3,
+		Duration:         time.Hour,
+	})
+	require.NoError(t, err)
+	parent.after = immediate
+
+	require.NoError(t, parent.Run(context.Background()))
+
+	require.Len(t, placer.requests, 4)
+	total := 0.0
+	for _, req := range placer.requests {
+		assert.LessOrEqual(t, req.Quantity, 3.0)
+		total += req.Quantity
+	}
+	assert.InDelta(t, 10.0, total, 1e-9)
+
+	progress := parent.Progress()
+	assert.Equal(t, StateCompleted, progress.State)
+	assert.InDelta(t, 10.0, progress.SubmittedQuantity, 1e-9)
+	assert.InDelta(t, 10.0, progress.FilledQuantity, 1e-9)
+}
+
+func TestParentOrder_ValueSlicing(t *testing.T) {
+	placer := &fakePlacer{}
+	parent, err := NewTWAP(placer, Config{
+		Ticker:            "VUSAl_EQ",
+		Value:             300,
+		Slices:            3,
+		QuantityPrecision: 2,
+		Prices:            fixedPrice(70),
+	})
+	require.NoError(t, err)
+	parent.after = immediate
+
+	require.NoError(t, parent.Run(context.Background()))
+
+	// Truncating to 1.42 leaves value over for the later slices
+	require.Len(t, placer.requests, 3)
+	assert.Equal(t, 1.42, placer.requests[0].Quantity)
+	assert.Equal(t, 1.43, placer.requests[1].Quantity)
+	assert.Equal(t, 1.43, placer.requests[2].Quantity)
+	assert.InDelta(t, 4.28*70, parent.Progress().SubmittedValue, 1e-9)
+}
+
+func TestParentOrder_WaitsForMarketOpen(t *testing.T) {
+	placer := &fakePlacer{}
+	hours := &scriptedHours{open: []bool{false, false, true}}
+	parent, err := NewTWAP(placer, Config{
+		Ticker:   "AAPL_US_EQ",
+		Quantity: 1,
+		Hours:    hours,
+	})
+	require.NoError(t, err)
+	parent.after = immediate
+
+	require.NoError(t, parent.Run(context.Background()))
+	assert.Len(t, placer.requests, 1)
+	assert.Empty(t, hours.open)
+}
+
+func TestParentOrder_Cancel(t *testing.T) {
+	placer := &fakePlacer{}
+	parent, err := NewTWAP(placer, Config{
+		Ticker:   "AAPL_US_EQ",
+		Quantity: 4,
+		Slices:   4,
+		Duration: time.Hour,
+	})
+	require.NoError(t, err)
+
+	blocked := make(chan time.Time)
+	parent.after = func(time.Duration) <-chan time.Time { return blocked }
+
+	done := make(chan error, 1)
+	go func() { done <- parent.Run(context.Background()) }()
+
+	require.Eventually(t, func() bool { return len(parent.Progress().Children) == 1 }, time.Second, time.Millisecond)
+	parent.Cancel()
+
+	assert.ErrorIs(t, <-done, ErrCancelled)
+	assert.Equal(t, StateCancelled, parent.Progress().State)
+	assert.Len(t, placer.requests, 1)
+}
+
+func TestParentOrder_PauseResume(t *testing.T) {
+	placer := &fakePlacer{}
+	parent, err := NewTWAP(placer, Config{
+		Ticker:   "AAPL_US_EQ",
+		Quantity: 2,
+		Slices:   2,
+		Duration: time.Hour,
+	})
+	require.NoError(t, err)
+
+	tick := make(chan time.Time, 1)
+	parent.after = func(time.Duration) <-chan time.Time { return tick }
+
+	done := make(chan error, 1)
+	go func() { done <- parent.Run(context.Background()) }()
+
+	require.Eventually(t, func() bool { return len(parent.Progress().Children) == 1 }, time.Second, time.Millisecond)
+	parent.Pause()
+	tick <- time.Time{}
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, StatePaused, parent.Progress().State)
+	assert.Len(t, parent.Progress().Children, 1)
+
+	parent.Resume()
+	require.NoError(t, <-done)
+	assert.Equal(t, StateCompleted, parent.Progress().State)
+	assert.Len(t, placer.requests, 2)
+}
+
+func TestParentOrder_ChildFailure(t *testing.T) {
+	placer := &fakePlacer{err: errors.New("boom")}
+	parent, err := NewTWAP(placer, Config{Ticker: "AAPL_US_EQ", Quantity: 1})
+	require.NoError(t, err)
+
+	err = parent.Run(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, StateFailed, parent.Progress().State)
+	assert.Zero(t, parent.Progress().SubmittedQuantity)
+}
+
+func TestParentOrder_LastSliceRespectsCap(t *testing.T) {
+	// Early slices round down to nothing, leaving more than the cap for the
+	// last planned slice
+	placer := &fakePlacer{}
+	parent, err := NewTWAP(placer, Config{
+		Ticker:            "AAPL_US_EQ",
+		Quantity:          0.5,
+		Slices:            10,
+		MaxChildQuantity:  0.1,
+		QuantityPrecision: 1,
+		Duration:          time.Hour,
+	})
+	require.NoError(t, err)
+	parent.after = immediate
+
+	require.NoError(t, parent.Run(context.Background()))
+
+	total := 0.0
+	for _, req := range placer.requests {
+		assert.LessOrEqual(t, req.Quantity, 0.1)
+		total += req.Quantity
+	}
+	assert.InDelta(t, 0.5, total, 1e-9)
+	assert.Len(t, placer.requests, 5)
+	assert.Equal(t, StateCompleted, parent.Progress().State)
+}
+
+// trackingPlacer accepts orders as NEW; each is filled after being looked
+// up twice, and order 2 has moved to the history by then
+type trackingPlacer struct {
+	fakePlacer
+	lookups map[int64]int
+}
+
+func (f *trackingPlacer) PlaceMarketOrder(ctx context.Context, req trading212.MarketOrderRequest) (*trading212.Order, error) {
+	order, err := f.fakePlacer.PlaceMarketOrder(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	order.FilledQuantity = 0
+	order.Status = trading212.OrderStatusNew
+	return order, nil
+}
+
+func (f *trackingPlacer) filled(orderID int64) trading212.Order {
+	req := f.requests[orderID-1]
+	return trading212.Order{ID: orderID, Ticker: req.Ticker, Quantity: req.Quantity, FilledQuantity: req.Quantity, FilledValue: req.Quantity * 100, Status: trading212.OrderStatusFilled}
+}
+
+func (f *trackingPlacer) GetOrderByID(ctx context.Context, orderID int64) (*trading212.Order, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.lookups[orderID]++
+	if f.lookups[orderID] < 2 {
+		return &trading212.Order{ID: orderID, Status: trading212.OrderStatusNew}, nil
+	}
+	if orderID == 2 {
+		return nil, &trading212.APIError{StatusCode: 404}
+	}
+	order := f.filled(orderID)
+	return &order, nil
+}
+
+func (f *trackingPlacer) GetHistoricalOrders(ctx context.Context, opts *trading212.HistoryOrdersOptions) (*trading212.PaginatedResponse[trading212.HistoricalOrder], error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &trading212.PaginatedResponse[trading212.HistoricalOrder]{
+		Items: []trading212.HistoricalOrder{{Order: f.filled(2)}},
+	}, nil
+}
+
+func TestParentOrder_TracksFills(t *testing.T) {
+	placer := &trackingPlacer{lookups: make(map[int64]int)}
+	parent, err := NewTWAP(placer, Config{Ticker: "AAPL_US_EQ", Quantity: 2, Slices: 2, Duration: time.Hour})
+	require.NoError(t, err)
+	parent.after = immediate
+
+	require.NoError(t, parent.Run(context.Background()))
+
+	progress := parent.Progress()
+	assert.Equal(t, StateCompleted, progress.State)
+	assert.InDelta(t, 2, progress.FilledQuantity, 1e-9)
+	assert.InDelta(t, 200, progress.FilledValue, 1e-9)
+	for _, child := range progress.Children {
+		assert.Equal(t, trading212.OrderStatusFilled, child.Order.Status)
+	}
+}
+
+func TestParentOrder_TracksFillsAfterEmptySlices(t *testing.T) {
+	// The first two slices round down to nothing, so child positions and
+	// slice numbers differ
+	placer := &trackingPlacer{lookups: make(map[int64]int)}
+	parent, err := NewTWAP(placer, Config{Ticker: "AAPL_US_EQ", Quantity: 0.3, Slices: 5, QuantityPrecision: 1, Duration: time.Hour})
+	require.NoError(t, err)
+	parent.after = immediate
+
+	require.NoError(t, parent.Run(context.Background()))
+
+	progress := parent.Progress()
+	require.Len(t, progress.Children, 3)
+	assert.Equal(t, 2, progress.Children[0].Index)
+	assert.InDelta(t, 0.3, progress.FilledQuantity, 1e-9)
+}
@@ -0,0 +1,486 @@
+// Package format renders SDK values as aligned tables, JSON, JSON Lines or
+// CSV. Columns are named by JSON path, such as "ticker" or
+// "fill.walletImpact.netValue", so the names match the API documentation and
+// the JSON output. Each SDK type has a default column set in a fixed order;
+// any leaf field can be selected instead.
+package format
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+)
+
+// Format represents an output format
+type Format string
+
+const (
+	// Table writes aligned, space separated columns with a header row
+	Table Format = "table"
+	// JSON writes an indented JSON array, or object for a single value
+	JSON Format = "json"
+	// JSONL writes one compact JSON object per line
+	JSONL Format = "jsonl"
+	// CSV writes comma separated values with a header row
+	CSV Format = "csv"
+)
+
+// Formats lists the supported formats
+var Formats = []Format{Table, JSON, JSONL, CSV}
+
+var (
+	// ErrUnknownFormat is returned for a format name that is not supported
+	ErrUnknownFormat = errors.New("unknown format")
+	// ErrUnknownColumn is returned when a column does not name a field
+	ErrUnknownColumn = errors.New("unknown column")
+)
+
+// Options represents options for Write
+type Options struct {
+	// Columns selects and orders the columns. When empty, table and CSV
+	// output use the default columns of the type and JSON output keeps the
+	// full values.
+	Columns []string
+}
+
+// ParseFormat returns the format with the given name, ignoring case
+func ParseFormat(s string) (Format, error) {
+	f := Format(strings.ToLower(strings.TrimSpace(s)))
+	for _, known := range Formats {
+		if f == known {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("%w %q, expected one of table, json, jsonl, csv", ErrUnknownFormat, s)
+}
+
+var (
+	mu       sync.RWMutex
+	defaults = map[reflect.Type][]string{
+		reflect.TypeOf(trading212.Position{}): {
+			"ticker", "quantity", "averagePrice", "currentPrice", "ppl", "fxPpl", "initialFillDate",
+		},
+		reflect.TypeOf(trading212.Order{}): {
+			"id", "ticker", "type", "status", "quantity", "filledQuantity", "limitPrice", "stopPrice", "timeInForce", "createdAt",
+		},
+		reflect.TypeOf(trading212.HistoricalOrder{}): {
+			"order.id", "order.ticker", "order.type", "order.status", "fill.filledAt", "fill.quantity", "fill.price",
+			"fill.walletImpact.netValue", "fill.walletImpact.currency",
+		},
+		reflect.TypeOf(trading212.HistoryDividendItem{}): {
+			"paidOn", "ticker", "type", "quantity", "grossAmountPerShare", "amount", "currency", "reference",
+		},
+		reflect.TypeOf(trading212.HistoryTransactionItem{}): {
+			"dateTime", "type", "amount", "currency", "reference",
+		},
+		reflect.TypeOf(trading212.TradableInstrument{}): {
+			"ticker", "shortName", "name", "type", "currencyCode", "isin", "extendedHours",
+		},
+		reflect.TypeOf(trading212.ReportResponse{}): {
+			"reportId", "status", "timeFrom", "timeTo", "downloadLink",
+		},
+		reflect.TypeOf(trading212.Exchange{}): {
+			"id", "name",
+		},
+		reflect.TypeOf(trading212.Pie{}): {
+			"id", "status", "cash", "progress", "result.priceAvgValue", "result.priceAvgResult",
+		},
+	}
+)
+
+// SetDefaultColumns sets the default columns of the type of sample, which
+// may be a value or a slice. It panics if a column does not exist.
+func SetDefaultColumns(sample interface{}, columns ...string) {
+	t := elemType(reflect.TypeOf(sample))
+	if _, err := resolve(t, columns); err != nil {
+		panic(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	defaults[t] = append([]string(nil), columns...)
+}
+
+// DefaultColumns returns the columns used for v when none are selected.
+// Types without registered defaults use every scalar field in declaration
+// order.
+func DefaultColumns(v interface{}) []string {
+	t := elemType(reflect.TypeOf(v))
+	mu.RLock()
+	columns, ok := defaults[t]
+	mu.RUnlock()
+	if ok {
+		return append([]string(nil), columns...)
+	}
+
+	var scalars []string
+	for _, f := range leaves(t, "", nil) {
+		if kind := indirect(f.typ).Kind(); kind != reflect.Slice && kind != reflect.Map {
+			scalars = append(scalars, f.name)
+		}
+	}
+	return scalars
+}
+
+// AvailableColumns returns every column that can be selected for v in
+// declaration order. Slices and maps are single columns holding JSON.
+func AvailableColumns(v interface{}) []string {
+	fields := leaves(elemType(reflect.TypeOf(v)), "", nil)
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.name
+	}
+	return names
+}
+
+// Write renders v, a struct, a slice of structs or a pointer to either, to w
+func Write(w io.Writer, f Format, v interface{}, opts *Options) error {
+	var selected []string
+	if opts != nil {
+		selected = opts.Columns
+	}
+
+	rows, single := rowsOf(v)
+	t := elemType(reflect.TypeOf(v))
+
+	if (f == JSON || f == JSONL) && len(selected) == 0 {
+		return writeValues(w, f, rows, single)
+	}
+
+	names := selected
+	if len(names) == 0 {
+		names = DefaultColumns(v)
+	}
+	columns, err := resolve(t, names)
+	if err != nil {
+		return err
+	}
+
+	switch f {
+	case Table:
+		return writeTable(w, columns, rows)
+	case CSV:
+		return writeCSV(w, columns, rows)
+	case JSON, JSONL:
+		return writeProjected(w, f, columns, rows, single)
+	}
+	return fmt.Errorf("%w %q", ErrUnknownFormat, f)
+}
+
+// column is a selectable leaf field: its name and the path of field indexes
+// to its value, one step per nested struct
+type column struct {
+	name  string
+	index [][]int
+	typ   reflect.Type
+}
+
+// value returns the field of row addressed by the column, or an invalid
+// Value when a pointer on the way is nil
+func (c column) value(row reflect.Value) reflect.Value {
+	for _, index := range c.index {
+		for row.Kind() == reflect.Ptr {
+			if row.IsNil() {
+				return reflect.Value{}
+			}
+			row = row.Elem()
+		}
+		row = row.FieldByIndex(index)
+	}
+	return row
+}
+
+func writeValues(w io.Writer, f Format, rows []reflect.Value, single bool) error {
+	if f == JSONL {
+		encoder := json.NewEncoder(w)
+		for _, row := range rows {
+			if err := encoder.Encode(row.Interface()); err != nil {
+				return fmt.Errorf("failed to encode JSON: %w", err)
+			}
+		}
+		return nil
+	}
+
+	var value interface{}
+	if single {
+		value = rows[0].Interface()
+	} else {
+		items := make([]interface{}, len(rows))
+		for i, row := range rows {
+			items[i] = row.Interface()
+		}
+		value = items
+	}
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+func writeProjected(w io.Writer, f Format, columns []column, rows []reflect.Value, single bool) error {
+	objects := make([][]byte, len(rows))
+	for i, row := range rows {
+		var buf bytes.Buffer
+		buf.WriteByte('{')
+		for j, c := range columns {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+			key, _ := json.Marshal(c.name)
+			buf.Write(key)
+			buf.WriteByte(':')
+
+			value := c.value(row)
+			if !value.IsValid() {
+				buf.WriteString("null")
+				continue
+			}
+			data, err := json.Marshal(value.Interface())
+			if err != nil {
+				return fmt.Errorf("failed to encode column %s: %w", c.name, err)
+			}
+			buf.Write(data)
+		}
+		buf.WriteByte('}')
+		objects[i] = buf.Bytes()
+	}
+
+	if f == JSONL {
+		for _, object := range objects {
+			if _, err := w.Write(append(object, '\n')); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var compact []byte
+	if single && len(objects) == 1 {
+		compact = objects[0]
+	} else {
+		compact = append([]byte{'['}, bytes.Join(objects, []byte{','})...)
+		compact = append(compact, ']')
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, compact, "", "  "); err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	out.WriteByte('\n')
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+func writeTable(w io.Writer, columns []column, rows []reflect.Value) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	headers := make([]string, len(columns))
+	for i, c := range columns {
+		headers[i] = strings.ToUpper(c.name)
+	}
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+		for i, c := range columns {
+			cells[i] = strings.NewReplacer("\t", " ", "\n", " ").Replace(cell(c.value(row)))
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+func writeCSV(w io.Writer, columns []column, rows []reflect.Value) error {
+	cw := csv.NewWriter(w)
+	headers := make([]string, len(columns))
+	for i, c := range columns {
+		headers[i] = c.name
+	}
+	if err := cw.Write(headers); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+		for i, c := range columns {
+			cells[i] = cell(c.value(row))
+		}
+		if err := cw.Write(cells); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	stringerType  = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// cell formats a value for table and CSV output
+func cell(v reflect.Value) string {
+	for v.IsValid() && v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return ""
+	}
+
+	if v.Type() == timeType {
+		t := v.Interface().(time.Time)
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(time.RFC3339)
+	}
+
+	if v.Type().Implements(stringerType) {
+		return v.Interface().(fmt.Stringer).String()
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	}
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return fmt.Sprint(v.Interface())
+	}
+	return string(data)
+}
+
+// leaves lists the leaf fields of t, flattening nested and embedded structs
+// the way encoding/json names them
+func leaves(t reflect.Type, prefix string, index [][]int) []column {
+	t = indirect(t)
+	if t == nil || t.Kind() != reflect.Struct || isLeaf(t) {
+		return nil
+	}
+
+	var fields []column
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, ok := jsonName(sf)
+		if !ok {
+			continue
+		}
+		path := append(append([][]int(nil), index...), []int{i})
+
+		if sf.Anonymous && sf.Tag.Get("json") == "" && indirect(sf.Type).Kind() == reflect.Struct {
+			fields = append(fields, leaves(sf.Type, prefix, path)...)
+			continue
+		}
+		if ft := indirect(sf.Type); ft.Kind() == reflect.Struct && !isLeaf(ft) {
+			fields = append(fields, leaves(ft, prefix+name+".", path)...)
+			continue
+		}
+		fields = append(fields, column{name: prefix + name, index: path, typ: sf.Type})
+	}
+	return fields
+}
+
+// resolve maps column names to fields of t
+func resolve(t reflect.Type, names []string) ([]column, error) {
+	byName := make(map[string]column)
+	for _, f := range leaves(t, "", nil) {
+		byName[f.name] = f
+	}
+
+	columns := make([]column, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		f, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("%w %q for %s", ErrUnknownColumn, name, t)
+		}
+		columns = append(columns, f)
+	}
+	return columns, nil
+}
+
+// rowsOf returns the rows of v and whether v was a single value
+func rowsOf(v interface{}) ([]reflect.Value, bool) {
+	rv := reflect.ValueOf(v)
+	for rv.IsValid() && rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, false
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil, false
+	}
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return []reflect.Value{rv}, true
+	}
+
+	rows := make([]reflect.Value, rv.Len())
+	for i := range rows {
+		rows[i] = rv.Index(i)
+	}
+	return rows, false
+}
+
+// elemType returns the struct type of the rows of a value of type t
+func elemType(t reflect.Type) reflect.Type {
+	if t == nil {
+		return nil
+	}
+	t = indirect(t)
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = indirect(t.Elem())
+	}
+	return t
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// isLeaf reports whether a struct type is rendered as a single value
+func isLeaf(t reflect.Type) bool {
+	return t == timeType || t.Implements(marshalerType) || t.Implements(stringerType)
+}
+
+// jsonName returns the JSON name of a struct field and false for fields
+// encoding/json skips
+func jsonName(sf reflect.StructField) (string, bool) {
+	if !sf.IsExported() && !sf.Anonymous {
+		return "", false
+	}
+	tag := sf.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = sf.Name
+	}
+	return name, true
+}
@@ -0,0 +1,122 @@
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+)
+
+func testPositions() []trading212.Position {
+	return []trading212.Position{
+		{Ticker: "AAPL_US_EQ", Quantity: 2, AveragePrice: 150.5, CurrentPrice: 170, Ppl: 39, InitialFillDate: time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{Ticker: "VUSAl_EQ", Quantity: 10.25, AveragePrice: 80, CurrentPrice: 82.1, Ppl: 21.52},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	f, err := ParseFormat("CSV")
+	require.NoError(t, err)
+	assert.Equal(t, CSV, f)
+
+	_, err = ParseFormat("xml")
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestWriteTable(t *testing.T) {
+	var buf bytes.Buffer
+	err := Write(&buf, Table, testPositions(), &Options{Columns: []string{"ticker", "quantity", "initialFillDate"}})
+	require.NoError(t, err)
+
+	expected := "" +
+		"TICKER      QUANTITY  INITIALFILLDATE\n" +
+		"AAPL_US_EQ  2         2024-01-02T15:04:05Z\n" +
+		"VUSAl_EQ    10.25     \n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestWriteCSVDefaultColumns(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, CSV, testPositions(), nil))
+
+	expected := "" +
+		"ticker,quantity,averagePrice,currentPrice,ppl,fxPpl,initialFillDate\n" +
+		"AAPL_US_EQ,2,150.5,170,39,0,2024-01-02T15:04:05Z\n" +
+		"VUSAl_EQ,10.25,80,82.1,21.52,0,\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestWriteNestedColumns(t *testing.T) {
+	limit := 99.5
+	orders := []trading212.HistoricalOrder{{
+		Order: trading212.Order{ID: 7, Ticker: "AAPL_US_EQ", LimitPrice: &limit},
+		Fill:  trading212.Fill{WalletImpact: trading212.FillWalletImpact{NetValue: -199, Currency: "GBP"}},
+	}}
+
+	var buf bytes.Buffer
+	err := Write(&buf, CSV, orders, &Options{Columns: []string{"order.id", "order.limitPrice", "order.stopPrice", "fill.walletImpact.netValue"}})
+	require.NoError(t, err)
+	assert.Equal(t, "order.id,order.limitPrice,order.stopPrice,fill.walletImpact.netValue\n7,99.5,,-199\n", buf.String())
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, JSON, testPositions(), nil))
+
+	var decoded []trading212.Position
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, testPositions(), decoded)
+
+	buf.Reset()
+	require.NoError(t, Write(&buf, JSON, []trading212.Position{}, nil))
+	assert.Equal(t, "[]\n", buf.String())
+}
+
+func TestWriteJSONLProjected(t *testing.T) {
+	var buf bytes.Buffer
+	err := Write(&buf, JSONL, testPositions(), &Options{Columns: []string{"quantity", "ticker"}})
+	require.NoError(t, err)
+	assert.Equal(t, "{\"quantity\":2,\"ticker\":\"AAPL_US_EQ\"}\n{\"quantity\":10.25,\"ticker\":\"VUSAl_EQ\"}\n", buf.String())
+}
+
+func TestWriteSingleValue(t *testing.T) {
+	summary := &trading212.AccountSummary{
+		AccountInfo: trading212.AccountInfo{ID: 42, Currency: "GBP"},
+		Cash:        trading212.AccountCash{Free: 10.5, Total: 100},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, JSON, summary, &Options{Columns: []string{"id", "cash.free"}}))
+	assert.JSONEq(t, `{"id": 42, "cash.free": 10.5}`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, Write(&buf, CSV, summary, nil))
+	assert.Equal(t, "currencyCode,id,cash.free,cash.invested,cash.result,cash.total\nGBP,42,10.5,0,0,100\n", buf.String())
+}
+
+func TestUnknownColumn(t *testing.T) {
+	err := Write(&bytes.Buffer{}, Table, testPositions(), &Options{Columns: []string{"ticker", "nope"}})
+	assert.ErrorIs(t, err, ErrUnknownColumn)
+}
+
+func TestDefaultColumnsExist(t *testing.T) {
+	for typ, columns := range defaults {
+		_, err := resolve(typ, columns)
+		assert.NoError(t, err, typ.String())
+	}
+}
+
+func TestAvailableColumns(t *testing.T) {
+	columns := AvailableColumns([]trading212.HistoryDividendItem{})
+	assert.Contains(t, columns, "instrument.isin")
+	assert.Contains(t, columns, "type")
+	assert.Equal(t, "amount", columns[0])
+
+	assert.Contains(t, AvailableColumns(trading212.Exchange{}), "workingSchedules")
+	assert.NotContains(t, DefaultColumns(trading212.PieDetails{}), "instruments")
+}
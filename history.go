@@ -14,13 +14,13 @@ type HistoricalOrder struct {
 
 // Fill represents order fill information
 type Fill struct {
-	FilledAt      time.Time        `json:"filledAt"`
-	ID            int64            `json:"id"`
-	Price         float64          `json:"price"`
-	Quantity      float64          `json:"quantity"`
-	TradingMethod string           `json:"tradingMethod"`
-	Type          string           `json:"type"`
-	WalletImpact  FillWalletImpact `json:"walletImpact"`
+	FilledAt      time.Time         `json:"filledAt"`
+	ID            int64             `json:"id"`
+	Price         float64           `json:"price"`
+	Quantity      float64           `json:"quantity"`
+	TradingMethod FillTradingMethod `json:"tradingMethod"`
+	Type          FillType          `json:"type"`
+	WalletImpact  FillWalletImpact  `json:"walletImpact"`
 }
 
 // FillWalletImpact represents fill wallet impact
@@ -42,26 +42,26 @@ type Tax struct {
 
 // HistoryDividendItem represents a dividend item
 type HistoryDividendItem struct {
-	Amount               float64    `json:"amount"`
-	AmountInEuro         float64    `json:"amountInEuro"`
-	Currency             string     `json:"currency"`
-	GrossAmountPerShare  float64    `json:"grossAmountPerShare"`
-	Instrument           Instrument `json:"instrument"`
-	PaidOn               time.Time  `json:"paidOn"`
-	Quantity             float64    `json:"quantity"`
-	Reference            string     `json:"reference"`
-	Ticker               string     `json:"ticker"`
-	TickerCurrency       string     `json:"tickerCurrency"`
-	Type                 string     `json:"type"`
+	Amount               float64      `json:"amount"`
+	AmountInEuro         float64      `json:"amountInEuro"`
+	Currency             string       `json:"currency"`
+	GrossAmountPerShare  float64      `json:"grossAmountPerShare"`
+	Instrument           Instrument   `json:"instrument"`
+	PaidOn               time.Time    `json:"paidOn"`
+	Quantity             float64      `json:"quantity"`
+	Reference            string       `json:"reference"`
+	Ticker               string       `json:"ticker"`
+	TickerCurrency       string       `json:"tickerCurrency"`
+	Type                 DividendType `json:"type"`
 }
 
 // HistoryTransactionItem represents a transaction item
 type HistoryTransactionItem struct {
-	Amount    float64   `json:"amount"`
-	Currency  string    `json:"currency"`
-	DateTime  time.Time `json:"dateTime"`
-	Reference string    `json:"reference"`
-	Type      string    `json:"type"`
+	Amount    float64         `json:"amount"`
+	Currency  string          `json:"currency"`
+	DateTime  time.Time       `json:"dateTime"`
+	Reference string          `json:"reference"`
+	Type      TransactionType `json:"type"`
 }
 
 // PaginatedResponse represents a paginated response
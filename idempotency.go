@@ -0,0 +1,281 @@
+package trading212
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+)
+
+// SubmissionOutcome represents how an idempotent order submission was resolved
+type SubmissionOutcome string
+
+const (
+	// SubmissionCreated means the API confirmed the order on the first attempt
+	SubmissionCreated SubmissionOutcome = "CREATED"
+	// SubmissionReconciled means the request failed ambiguously but a matching
+	// order was found, so it was not resubmitted
+	SubmissionReconciled SubmissionOutcome = "RECONCILED"
+	// SubmissionResubmitted means the request failed ambiguously, no matching
+	// order was found and a later attempt created the order
+	SubmissionResubmitted SubmissionOutcome = "RESUBMITTED"
+	// SubmissionRejected means the API definitely did not create the order
+	SubmissionRejected SubmissionOutcome = "REJECTED"
+	// SubmissionUnknown means it could not be determined whether the order
+	// was created; calling again with the same key looks for the order
+	// before resending it
+	SubmissionUnknown SubmissionOutcome = "UNKNOWN"
+)
+
+// SubmissionResult represents the outcome of an idempotent order submission
+type SubmissionResult struct {
+	Key         string
+	Outcome     SubmissionOutcome
+	Order       *Order
+	Attempts    int
+	SubmittedAt time.Time
+	Err         error
+}
+
+// IdempotencyOptions represents options for idempotent order submission
+type IdempotencyOptions struct {
+	// MaxAttempts is the maximum number of times the order is sent (default 2)
+	MaxAttempts int
+	// ReconcileDelay is how long to wait after an ambiguous failure before
+	// looking for the order (default 2s)
+	ReconcileDelay time.Duration
+	// MatchWindow is the allowed clock skew when matching an order's
+	// createdAt against the submission time (default 1m)
+	MatchWindow time.Duration
+}
+
+// NewIdempotencyKey returns a random key suitable for PlaceMarketOrderIdempotent
+func NewIdempotencyKey() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("failed to generate idempotency key: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
+
+// PlaceMarketOrderIdempotent places a market order at most once per key.
+// When a request fails without a definite answer (network error, timeout or
+// 5xx) pending and recent historical orders are inspected for a matching
+// order before deciding whether to resubmit. Calling it again with the same
+// key returns the earlier result without contacting the API, unless that
+// result was UNKNOWN: then the order is looked for again and only sent if
+// it is not found. A concurrent call with the same key waits for the first
+// one to finish.
+func (c *Client) PlaceMarketOrderIdempotent(ctx context.Context, key string, req MarketOrderRequest, opts *IdempotencyOptions) (*SubmissionResult, error) {
+	if key == "" {
+		return nil, errors.New("idempotency key is required")
+	}
+
+	previous, done, err := c.claimSubmission(ctx, key)
+	if done {
+		return previous, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	o := IdempotencyOptions{MaxAttempts: 2, ReconcileDelay: 2 * time.Second, MatchWindow: time.Minute}
+	if opts != nil {
+		if opts.MaxAttempts > 0 {
+			o.MaxAttempts = opts.MaxAttempts
+		}
+		if opts.ReconcileDelay > 0 {
+			o.ReconcileDelay = opts.ReconcileDelay
+		}
+		if opts.MatchWindow > 0 {
+			o.MatchWindow = opts.MatchWindow
+		}
+	}
+
+	result := &SubmissionResult{Key: key, SubmittedAt: time.Now()}
+	defer c.finishSubmission(key, result)
+	if previous != nil {
+		// The earlier call may have created the order after all
+		match, err := c.FindSubmittedOrder(ctx, req, previous.SubmittedAt, o.MatchWindow)
+		if err != nil {
+			result.Outcome = SubmissionUnknown
+			result.Err = fmt.Errorf("failed to reconcile order: %w", err)
+			return result, result.Err
+		}
+		if match != nil {
+			result.Order = match
+			result.Outcome = SubmissionReconciled
+			return result, nil
+		}
+	}
+	for result.Attempts < o.MaxAttempts {
+		result.Attempts++
+		attemptAt := time.Now()
+
+		order, err := c.PlaceMarketOrder(ctx, req)
+		if err == nil {
+			result.Order = order
+			result.Outcome = SubmissionCreated
+			if result.Attempts > 1 || previous != nil {
+				result.Outcome = SubmissionResubmitted
+			}
+			result.Err = nil
+			break
+		}
+		result.Err = err
+
+		if !isAmbiguous(err) {
+			result.Outcome = SubmissionRejected
+			break
+		}
+
+		result.Outcome = SubmissionUnknown
+		if ctx.Err() != nil {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(o.ReconcileDelay):
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		match, err := c.FindSubmittedOrder(ctx, req, attemptAt, o.MatchWindow)
+		if err != nil {
+			result.Err = fmt.Errorf("failed to reconcile order: %w", err)
+			break
+		}
+		if match != nil {
+			result.Order = match
+			result.Outcome = SubmissionReconciled
+			result.Err = nil
+			break
+		}
+	}
+
+	return result, result.Err
+}
+
+// claimSubmission reserves key for the caller. It returns done with the
+// earlier result if key has already been resolved, and waits while another
+// call with the same key is in flight. Otherwise it returns the earlier
+// UNKNOWN result, if any, for the caller to reconcile before resending.
+func (c *Client) claimSubmission(ctx context.Context, key string) (*SubmissionResult, bool, error) {
+	c.submissionsMu.Lock()
+	var previous *SubmissionResult
+	for {
+		if result, ok := c.submissions[key]; ok {
+			if result.Outcome != SubmissionUnknown {
+				c.submissionsMu.Unlock()
+				return result, true, result.Err
+			}
+			previous = result
+		}
+		wait, busy := c.inFlight[key]
+		if !busy {
+			break
+		}
+		c.submissionsMu.Unlock()
+		select {
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		case <-wait:
+		}
+		c.submissionsMu.Lock()
+	}
+
+	if c.submissions == nil {
+		c.submissions = make(map[string]*SubmissionResult)
+		c.inFlight = make(map[string]chan struct{})
+	}
+	c.inFlight[key] = make(chan struct{})
+	c.submissionsMu.Unlock()
+	return previous, false, nil
+}
+
+// finishSubmission stores the result of key and releases waiting callers
+func (c *Client) finishSubmission(key string, result *SubmissionResult) {
+	c.submissionsMu.Lock()
+	defer c.submissionsMu.Unlock()
+	c.submissions[key] = result
+	if result.Order != nil {
+		c.submissions[claimKey(result.Order.ID)] = result
+	}
+	close(c.inFlight[key])
+	delete(c.inFlight, key)
+}
+
+// FindSubmittedOrder looks for a market order matching req created after
+// since, allowing window of clock skew, first among pending orders and then
+// in recent order history. Orders already attributed to an idempotency key
+// are ignored. It returns nil when no order matches.
+func (c *Client) FindSubmittedOrder(ctx context.Context, req MarketOrderRequest, since time.Time, window time.Duration) (*Order, error) {
+	from, to := since.Add(-window), time.Now().Add(window)
+
+	pending, err := c.GetOrders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range pending {
+		if c.matchesSubmission(&pending[i], req, from, to) {
+			return &pending[i], nil
+		}
+	}
+
+	history, err := c.GetHistoricalOrders(ctx, &HistoryOrdersOptions{Ticker: req.Ticker, Limit: 50})
+	if err != nil {
+		return nil, err
+	}
+	for i := range history.Items {
+		if c.matchesSubmission(&history.Items[i].Order, req, from, to) {
+			return &history.Items[i].Order, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// matchesSubmission reports whether order could have been created by req and
+// has not already been claimed by another idempotency key
+func (c *Client) matchesSubmission(order *Order, req MarketOrderRequest, from, to time.Time) bool {
+	if order.Ticker != req.Ticker || order.Type != OrderTypeMarket {
+		return false
+	}
+	if order.CreatedAt.Before(from) || order.CreatedAt.After(to) {
+		return false
+	}
+	if math.Abs(math.Abs(order.Quantity)-math.Abs(req.Quantity)) > 1e-9 {
+		return false
+	}
+	if order.Side != "" && (order.Side == OrderSideSell) != (req.Quantity < 0) {
+		return false
+	}
+
+	c.submissionsMu.Lock()
+	defer c.submissionsMu.Unlock()
+	_, claimed := c.submissions[claimKey(order.ID)]
+	return !claimed
+}
+
+// isAmbiguous reports whether err leaves it unknown if the order was created
+func isAmbiguous(err error) bool {
+	if errors.Is(err, errJournalWrite) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500 || apiErr.StatusCode == http.StatusRequestTimeout
+	}
+	return true
+}
+
+// claimKey records that an order ID has been attributed to a submission
+func claimKey(orderID int64) string {
+	return fmt.Sprintf("order:%d", orderID)
+}
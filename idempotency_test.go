@@ -0,0 +1,166 @@
+package trading212
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newIdempotencyServer(t *testing.T, marketStatus []int, pending []Order) (*httptest.Server, *int32) {
+	var posts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v0/equity/orders/market":
+			n := atomic.AddInt32(&posts, 1)
+			status := http.StatusOK
+			if int(n) <= len(marketStatus) {
+				status = marketStatus[n-1]
+			}
+			w.WriteHeader(status)
+			if status == http.StatusOK {
+				json.NewEncoder(w).Encode(Order{ID: 100 + int64(n), Ticker: "AAPL_US_EQ", Quantity: 1, Type: OrderTypeMarket})
+			}
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v0/equity/orders":
+			json.NewEncoder(w).Encode(pending)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v0/equity/history/orders":
+			json.NewEncoder(w).Encode(PaginatedResponse[HistoricalOrder]{})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &posts
+}
+
+func TestPlaceMarketOrderIdempotent_Created(t *testing.T) {
+	server, posts := newIdempotencyServer(t, nil, nil)
+	client := NewClient(Environment(server.URL), "key", "secret")
+	req := MarketOrderRequest{Ticker: "AAPL_US_EQ", Quantity: 1}
+
+	result, err := client.PlaceMarketOrderIdempotent(context.Background(), "k1", req, nil)
+	require.NoError(t, err)
+	assert.Equal(t, SubmissionCreated, result.Outcome)
+
+	again, err := client.PlaceMarketOrderIdempotent(context.Background(), "k1", req, nil)
+	require.NoError(t, err)
+	assert.Same(t, result, again)
+	assert.EqualValues(t, 1, atomic.LoadInt32(posts))
+}
+
+func TestPlaceMarketOrderIdempotent_Reconciled(t *testing.T) {
+	pending := []Order{{ID: 7, Ticker: "AAPL_US_EQ", Quantity: 1, Type: OrderTypeMarket, Side: OrderSideBuy, CreatedAt: time.Now()}}
+	server, posts := newIdempotencyServer(t, []int{http.StatusBadGateway}, pending)
+	client := NewClient(Environment(server.URL), "key", "secret")
+
+	result, err := client.PlaceMarketOrderIdempotent(context.Background(), "k1",
+		MarketOrderRequest{Ticker: "AAPL_US_EQ", Quantity: 1}, &IdempotencyOptions{ReconcileDelay: time.Millisecond})
+	require.NoError(t, err)
+	assert.Equal(t, SubmissionReconciled, result.Outcome)
+	assert.Equal(t, int64(7), result.Order.ID)
+	assert.EqualValues(t, 1, atomic.LoadInt32(posts))
+}
+
+func TestPlaceMarketOrderIdempotent_Resubmitted(t *testing.T) {
+	server, posts := newIdempotencyServer(t, []int{http.StatusServiceUnavailable}, nil)
+	client := NewClient(Environment(server.URL), "key", "secret")
+
+	result, err := client.PlaceMarketOrderIdempotent(context.Background(), "k1",
+		MarketOrderRequest{Ticker: "AAPL_US_EQ", Quantity: 1}, &IdempotencyOptions{ReconcileDelay: time.Millisecond})
+	require.NoError(t, err)
+	assert.Equal(t, SubmissionResubmitted, result.Outcome)
+	assert.Equal(t, 2, result.Attempts)
+	assert.EqualValues(t, 2, atomic.LoadInt32(posts))
+}
+
+func TestPlaceMarketOrderIdempotent_Rejected(t *testing.T) {
+	server, posts := newIdempotencyServer(t, []int{http.StatusBadRequest}, nil)
+	client := NewClient(Environment(server.URL), "key", "secret")
+
+	result, err := client.PlaceMarketOrderIdempotent(context.Background(), "k1",
+		MarketOrderRequest{Ticker: "AAPL_US_EQ", Quantity: 1}, nil)
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, SubmissionRejected, result.Outcome)
+	assert.EqualValues(t, 1, atomic.LoadInt32(posts))
+}
+
+func TestPlaceMarketOrderIdempotent_RetryAfterUnknownReconciles(t *testing.T) {
+	var mu sync.Mutex
+	var pending []Order
+	var posts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/api/v0/equity/orders/market":
+			atomic.AddInt32(&posts, 1)
+			w.WriteHeader(http.StatusGatewayTimeout)
+		case "/api/v0/equity/orders":
+			json.NewEncoder(w).Encode(pending)
+		case "/api/v0/equity/history/orders":
+			json.NewEncoder(w).Encode(PaginatedResponse[HistoricalOrder]{})
+		}
+	}))
+	defer server.Close()
+	client := NewClient(Environment(server.URL), "key", "secret")
+	req := MarketOrderRequest{Ticker: "AAPL_US_EQ", Quantity: 1}
+	opts := &IdempotencyOptions{MaxAttempts: 1, ReconcileDelay: time.Millisecond}
+
+	result, err := client.PlaceMarketOrderIdempotent(context.Background(), "k1", req, opts)
+	require.Error(t, err)
+	assert.Equal(t, SubmissionUnknown, result.Outcome)
+
+	// The timed out order shows up late
+	mu.Lock()
+	pending = []Order{{ID: 7, Ticker: "AAPL_US_EQ", Quantity: 1, Type: OrderTypeMarket, Side: OrderSideBuy, CreatedAt: time.Now()}}
+	mu.Unlock()
+
+	result, err = client.PlaceMarketOrderIdempotent(context.Background(), "k1", req, opts)
+	require.NoError(t, err)
+	assert.Equal(t, SubmissionReconciled, result.Outcome)
+	assert.Equal(t, int64(7), result.Order.ID)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&posts))
+}
+
+func TestPlaceMarketOrderIdempotent_ConcurrentSameKey(t *testing.T) {
+	var posts int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&posts, 1)
+		<-release
+		json.NewEncoder(w).Encode(Order{ID: 100 + int64(n), Ticker: "AAPL_US_EQ", Quantity: 1, Type: OrderTypeMarket})
+	}))
+	defer server.Close()
+	client := NewClient(Environment(server.URL), "key", "secret")
+	req := MarketOrderRequest{Ticker: "AAPL_US_EQ", Quantity: 1}
+
+	results := make([]*SubmissionResult, 5)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			result, err := client.PlaceMarketOrderIdempotent(context.Background(), "k1", req, nil)
+			assert.NoError(t, err)
+			results[i] = result
+		}(i)
+	}
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&posts) == 1 }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.EqualValues(t, 1, atomic.LoadInt32(&posts))
+	for _, result := range results {
+		assert.Same(t, results[0], result)
+	}
+}
@@ -0,0 +1,137 @@
+package trading212
+
+import "sort"
+
+// InstrumentChangeType represents the kind of change between two instrument
+// snapshots
+type InstrumentChangeType string
+
+const (
+	InstrumentAdded   InstrumentChangeType = "ADDED"
+	InstrumentRemoved InstrumentChangeType = "REMOVED"
+	InstrumentChanged InstrumentChangeType = "CHANGED"
+)
+
+// InstrumentChange represents a single difference between two instrument
+// snapshots. Old is nil for added instruments and New is nil for removed ones.
+type InstrumentChange struct {
+	Type   InstrumentChangeType
+	Ticker string
+	Old    *TradableInstrument
+	New    *TradableInstrument
+	// Fields lists the JSON names of the fields that changed
+	Fields []string
+}
+
+// DiffInstruments compares two instrument snapshots keyed by ticker and
+// returns the changes from previous to current sorted by ticker
+func DiffInstruments(previous, current []TradableInstrument) []InstrumentChange {
+	oldByTicker := make(map[string]*TradableInstrument, len(previous))
+	for i := range previous {
+		oldByTicker[previous[i].Ticker] = &previous[i]
+	}
+	newByTicker := make(map[string]*TradableInstrument, len(current))
+	for i := range current {
+		newByTicker[current[i].Ticker] = &current[i]
+	}
+
+	var changes []InstrumentChange
+	for ticker, before := range oldByTicker {
+		after, ok := newByTicker[ticker]
+		if !ok {
+			changes = append(changes, InstrumentChange{Type: InstrumentRemoved, Ticker: ticker, Old: before})
+			continue
+		}
+		if fields := changedInstrumentFields(before, after); len(fields) > 0 {
+			changes = append(changes, InstrumentChange{Type: InstrumentChanged, Ticker: ticker, Old: before, New: after, Fields: fields})
+		}
+	}
+	for ticker, after := range newByTicker {
+		if _, ok := oldByTicker[ticker]; !ok {
+			changes = append(changes, InstrumentChange{Type: InstrumentAdded, Ticker: ticker, New: after})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Ticker < changes[j].Ticker
+	})
+	return changes
+}
+
+// AffectedHoldings returns the changes that concern tickers held in positions
+func AffectedHoldings(changes []InstrumentChange, positions []Position) []InstrumentChange {
+	held := make(map[string]bool, len(positions))
+	for _, position := range positions {
+		held[position.Ticker] = true
+	}
+
+	var affected []InstrumentChange
+	for _, change := range changes {
+		if held[change.Ticker] {
+			affected = append(affected, change)
+		}
+	}
+	return affected
+}
+
+// OnChange registers fn to be called with the differences between the
+// previous and the new instrument list after every successful Refresh. It is
+// not called for the initial load or when nothing changed.
+func (c *InstrumentCatalog) OnChange(fn func([]InstrumentChange)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.listeners = append(c.listeners, fn)
+}
+
+// notify diffs two snapshots and calls the registered listeners
+func (c *InstrumentCatalog) notify(previous, current []TradableInstrument) {
+	var listeners []func([]InstrumentChange)
+	c.mu.RLock()
+	listeners = append(listeners, c.listeners...)
+	c.mu.RUnlock()
+
+	if len(listeners) == 0 || len(previous) == 0 {
+		return
+	}
+
+	changes := DiffInstruments(previous, current)
+	if len(changes) == 0 {
+		return
+	}
+	for _, listener := range listeners {
+		listener(changes)
+	}
+}
+
+// changedInstrumentFields returns the JSON names of the fields that differ
+func changedInstrumentFields(a, b *TradableInstrument) []string {
+	var fields []string
+	if !a.AddedOn.Equal(b.AddedOn) {
+		fields = append(fields, "addedOn")
+	}
+	if a.CurrencyCode != b.CurrencyCode {
+		fields = append(fields, "currencyCode")
+	}
+	if a.ExtendedHours != b.ExtendedHours {
+		fields = append(fields, "extendedHours")
+	}
+	if a.ISIN != b.ISIN {
+		fields = append(fields, "isin")
+	}
+	if a.MaxOpenQuantity != b.MaxOpenQuantity {
+		fields = append(fields, "maxOpenQuantity")
+	}
+	if a.Name != b.Name {
+		fields = append(fields, "name")
+	}
+	if a.ShortName != b.ShortName {
+		fields = append(fields, "shortName")
+	}
+	if a.Type != b.Type {
+		fields = append(fields, "type")
+	}
+	if a.WorkingScheduleID != b.WorkingScheduleID {
+		fields = append(fields, "workingScheduleId")
+	}
+	return fields
+}
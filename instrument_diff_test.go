@@ -0,0 +1,73 @@
+package trading212
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffInstruments(t *testing.T) {
+	previous := []TradableInstrument{
+		{Ticker: "AAPL_US_EQ", MaxOpenQuantity: 1000},
+		{Ticker: "TWTR_US_EQ"},
+		{Ticker: "VUSAl_EQ", ExtendedHours: false},
+	}
+	current := []TradableInstrument{
+		{Ticker: "AAPL_US_EQ", MaxOpenQuantity: 500},
+		{Ticker: "ARM_US_EQ"},
+		{Ticker: "VUSAl_EQ", ExtendedHours: false},
+	}
+
+	changes := DiffInstruments(previous, current)
+	require.Len(t, changes, 3)
+
+	assert.Equal(t, InstrumentChanged, changes[0].Type)
+	assert.Equal(t, "AAPL_US_EQ", changes[0].Ticker)
+	assert.Equal(t, []string{"maxOpenQuantity"}, changes[0].Fields)
+
+	assert.Equal(t, InstrumentAdded, changes[1].Type)
+	assert.Equal(t, "ARM_US_EQ", changes[1].Ticker)
+	assert.Nil(t, changes[1].Old)
+
+	assert.Equal(t, InstrumentRemoved, changes[2].Type)
+	assert.Equal(t, "TWTR_US_EQ", changes[2].Ticker)
+	assert.Nil(t, changes[2].New)
+
+	affected := AffectedHoldings(changes, []Position{{Ticker: "TWTR_US_EQ"}, {Ticker: "MSFT_US_EQ"}})
+	require.Len(t, affected, 1)
+	assert.Equal(t, InstrumentRemoved, affected[0].Type)
+}
+
+func TestInstrumentCatalog_OnChange(t *testing.T) {
+	snapshots := [][]TradableInstrument{
+		{{Ticker: "AAPL_US_EQ"}, {Ticker: "TWTR_US_EQ"}},
+		{{Ticker: "AAPL_US_EQ"}, {Ticker: "TWTR_US_EQ"}},
+		{{Ticker: "AAPL_US_EQ"}},
+	}
+	call := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(snapshots[call])
+		call++
+	}))
+	defer server.Close()
+
+	catalog := NewInstrumentCatalog(NewClient(Environment(server.URL), "key", "secret"), nil)
+	var events [][]InstrumentChange
+	catalog.OnChange(func(changes []InstrumentChange) {
+		events = append(events, changes)
+	})
+
+	for range snapshots {
+		require.NoError(t, catalog.Refresh(context.Background()))
+	}
+
+	require.Len(t, events, 1)
+	require.Len(t, events[0], 1)
+	assert.Equal(t, InstrumentRemoved, events[0][0].Type)
+	assert.Equal(t, "TWTR_US_EQ", events[0][0].Ticker)
+}
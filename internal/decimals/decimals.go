@@ -0,0 +1,23 @@
+// Package decimals holds the division helpers shared by the analytics
+// packages, which all split costs and amounts pro rata.
+package decimals
+
+import trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+
+// Places is the precision of divisions
+const Places = 10
+
+// Ratio returns a / b to Places decimal places without trailing zeros, or 0
+// when b is 0
+func Ratio(a, b trading212.Decimal) trading212.Decimal {
+	result, err := a.Div(b, Places)
+	if err != nil {
+		return trading212.Decimal{}
+	}
+	return result.Normalize()
+}
+
+// Prorate returns the share of amount that part is of whole
+func Prorate(amount, part, whole trading212.Decimal) trading212.Decimal {
+	return Ratio(amount.Mul(part), whole)
+}
@@ -0,0 +1,44 @@
+// Package fixtures builds the test data shared by the analytics packages'
+// tests. It does not import testing or testify, so it adds no test-only
+// dependencies to the packages that import it.
+package fixtures
+
+import (
+	"time"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+)
+
+// Fill returns a filled order in a GBP account; a negative quantity is a
+// sell. The order and fill share id.
+func Fill(id int64, ticker string, filledAt time.Time, fillType trading212.FillType, quantity, price float64) trading212.HistoricalOrder {
+	side := trading212.OrderSideBuy
+	if quantity < 0 {
+		side = trading212.OrderSideSell
+	}
+	return trading212.HistoricalOrder{
+		Order: trading212.Order{ID: id, Ticker: ticker, Side: side},
+		Fill: trading212.Fill{
+			ID:           id,
+			FilledAt:     filledAt,
+			Type:         fillType,
+			Quantity:     quantity,
+			Price:        price,
+			WalletImpact: trading212.FillWalletImpact{Currency: "GBP"},
+		},
+	}
+}
+
+// T is the part of *testing.T used by AssertDecimal
+type T interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// AssertDecimal checks that got is numerically equal to want, ignoring scale
+func AssertDecimal(t T, want string, got trading212.Decimal) {
+	t.Helper()
+	if !trading212.MustParseDecimal(want).Equal(got) {
+		t.Errorf("want %s, got %s", want, got)
+	}
+}
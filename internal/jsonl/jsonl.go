@@ -0,0 +1,105 @@
+// Package jsonl reads and appends the JSON Lines files used by the journals
+// and stores. Appends are synced to disk, and a last line left incomplete by
+// a crash is ignored on read and removed by the next append.
+package jsonl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Append encodes each value as a line of the file at path, creating it if
+// needed, and syncs it to disk
+func Append(path string, values ...interface{}) error {
+	var buf bytes.Buffer
+	for _, v := range values {
+		line, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("failed to encode line: %w", err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_RDWR, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := TrimPartialLine(f); err != nil {
+		return fmt.Errorf("failed to repair file: %w", err)
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// Read calls fn with each complete, non-blank line of the file at path and
+// its 1-based line number. A missing file has no lines.
+func Read(path string, fn func(line int, data []byte) error) error {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// Without a newline the last write did not finish
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(bytes.TrimSpace(data)) == 0 {
+			continue
+		}
+		if err := fn(line, data); err != nil {
+			return err
+		}
+	}
+}
+
+// TrimPartialLine truncates f after its last newline, dropping a line left
+// incomplete by a crash during Append
+func TrimPartialLine(f *os.File) error {
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	end := info.Size()
+	chunk := make([]byte, 4096)
+	for offset := end; offset > 0; {
+		n := int64(len(chunk))
+		if offset < n {
+			n = offset
+		}
+		offset -= n
+		if _, err := f.ReadAt(chunk[:n], offset); err != nil {
+			return err
+		}
+		if i := bytes.LastIndexByte(chunk[:n], '\n'); i >= 0 {
+			if size := offset + int64(i) + 1; size != end {
+				return f.Truncate(size)
+			}
+			return nil
+		}
+	}
+	if end > 0 {
+		return f.Truncate(0)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package jsonl
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type item struct {
+	N int `json:"n"`
+}
+
+func readAll(t *testing.T, path string) []int {
+	var values []int
+	require.NoError(t, Read(path, func(line int, data []byte) error {
+		var v item
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		values = append(values, v.N)
+		return nil
+	}))
+	return values
+}
+
+func TestAppendAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "items.jsonl")
+	assert.Empty(t, readAll(t, path))
+
+	require.NoError(t, Append(path, item{1}, item{2}))
+	require.NoError(t, Append(path, item{3}))
+	assert.Equal(t, []int{1, 2, 3}, readAll(t, path))
+}
+
+func TestPartialLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "items.jsonl")
+	require.NoError(t, Append(path, item{1}))
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"n": 2`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	assert.Equal(t, []int{1}, readAll(t, path))
+
+	require.NoError(t, Append(path, item{3}))
+	assert.Equal(t, []int{1, 3}, readAll(t, path))
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"n": 4`), 0o600))
+	require.NoError(t, Append(path, item{5}))
+	assert.Equal(t, []int{5}, readAll(t, path))
+}
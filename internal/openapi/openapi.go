@@ -0,0 +1,269 @@
+// Package openapi loads the subset of an OpenAPI 3 document used by the
+// Trading 212 spec: paths, operations and component schemas.
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document represents an OpenAPI document
+type Document struct {
+	Paths      map[string]PathItem `yaml:"paths"`
+	Components Components          `yaml:"components"`
+}
+
+// Components represents the reusable parts of a document
+type Components struct {
+	Schemas map[string]*Schema `yaml:"schemas"`
+}
+
+// PathItem represents the operations available on a path
+type PathItem struct {
+	Get    *Operation `yaml:"get"`
+	Put    *Operation `yaml:"put"`
+	Post   *Operation `yaml:"post"`
+	Delete *Operation `yaml:"delete"`
+	Patch  *Operation `yaml:"patch"`
+}
+
+// Operation represents a single API operation
+type Operation struct {
+	OperationID string              `yaml:"operationId"`
+	Summary     string              `yaml:"summary"`
+	Description string              `yaml:"description"`
+	Tags        []string            `yaml:"tags"`
+	Parameters  []Parameter         `yaml:"parameters"`
+	RequestBody *RequestBody        `yaml:"requestBody"`
+	Responses   map[string]Response `yaml:"responses"`
+
+	// Method and Path are filled in by Document.Operations
+	Method string `yaml:"-"`
+	Path   string `yaml:"-"`
+}
+
+// Parameter represents a path, query or header parameter
+type Parameter struct {
+	Name        string  `yaml:"name"`
+	In          string  `yaml:"in"`
+	Description string  `yaml:"description"`
+	Required    bool    `yaml:"required"`
+	Schema      *Schema `yaml:"schema"`
+}
+
+// RequestBody represents an operation request body
+type RequestBody struct {
+	Required bool                 `yaml:"required"`
+	Content  map[string]MediaType `yaml:"content"`
+}
+
+// Response represents an operation response
+type Response struct {
+	Description string               `yaml:"description"`
+	Content     map[string]MediaType `yaml:"content"`
+}
+
+// MediaType represents the schema of a body in one content type
+type MediaType struct {
+	Schema *Schema `yaml:"schema"`
+}
+
+// Schema represents a JSON schema
+type Schema struct {
+	Ref                  string             `yaml:"$ref"`
+	Type                 string             `yaml:"type"`
+	Format               string             `yaml:"format"`
+	Description          string             `yaml:"description"`
+	Enum                 []string           `yaml:"enum"`
+	Properties           map[string]*Schema `yaml:"properties"`
+	Required             []string           `yaml:"required"`
+	Items                *Schema            `yaml:"items"`
+	AdditionalProperties *Schema            `yaml:"additionalProperties"`
+	Nullable             bool               `yaml:"nullable"`
+	MinLength            *int               `yaml:"minLength"`
+	MaxLength            *int               `yaml:"maxLength"`
+	Minimum              *float64           `yaml:"minimum"`
+	Maximum              *float64           `yaml:"maximum"`
+}
+
+// UnmarshalYAML accepts the boolean form of additionalProperties, where true
+// means any value and false means none
+func (s *Schema) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!bool" {
+		*s = Schema{}
+		return nil
+	}
+	type plain Schema
+	return node.Decode((*plain)(s))
+}
+
+// Load reads and parses the document at path
+func Load(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse parses a YAML or JSON document
+func Parse(data []byte) (*Document, error) {
+	var doc Document
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse spec: %w", err)
+	}
+	if len(doc.Paths) == 0 {
+		return nil, errors.New("spec has no paths")
+	}
+	return &doc, nil
+}
+
+// Operations returns every operation sorted by path and method
+func (d *Document) Operations() []*Operation {
+	var operations []*Operation
+	for path, item := range d.Paths {
+		for method, operation := range map[string]*Operation{
+			"GET":    item.Get,
+			"PUT":    item.Put,
+			"POST":   item.Post,
+			"DELETE": item.Delete,
+			"PATCH":  item.Patch,
+		} {
+			if operation == nil {
+				continue
+			}
+			operation.Method = method
+			operation.Path = path
+			operations = append(operations, operation)
+		}
+	}
+
+	sort.Slice(operations, func(i, j int) bool {
+		if operations[i].Path != operations[j].Path {
+			return operations[i].Path < operations[j].Path
+		}
+		return operations[i].Method < operations[j].Method
+	})
+	return operations
+}
+
+// Operation returns the operation for method and path, or nil
+func (d *Document) Operation(method, path string) *Operation {
+	for _, operation := range d.Operations() {
+		if operation.Method == strings.ToUpper(method) && operation.Path == path {
+			return operation
+		}
+	}
+	return nil
+}
+
+// Schema returns the named component schema, or nil
+func (d *Document) Schema(name string) *Schema {
+	return d.Components.Schemas[name]
+}
+
+// SchemaNames returns the component schema names in sorted order
+func (d *Document) SchemaNames() []string {
+	names := make([]string, 0, len(d.Components.Schemas))
+	for name := range d.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolve follows $ref until it reaches a concrete schema
+func (d *Document) Resolve(s *Schema) (*Schema, error) {
+	for seen := 0; s != nil && s.Ref != ""; seen++ {
+		if seen > 32 {
+			return nil, fmt.Errorf("reference cycle at %s", s.Ref)
+		}
+		target := d.Schema(RefName(s.Ref))
+		if target == nil {
+			return nil, fmt.Errorf("unresolved reference %s", s.Ref)
+		}
+		s = target
+	}
+	return s, nil
+}
+
+// RefName returns the schema name a local reference points to, e.g. Order
+// for #/components/schemas/Order
+func RefName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// RequestSchema returns the JSON request body schema, or nil
+func (o *Operation) RequestSchema() *Schema {
+	if o.RequestBody == nil {
+		return nil
+	}
+	return jsonSchema(o.RequestBody.Content)
+}
+
+// ResponseSchema returns the JSON body schema of the response with the given
+// status code, falling back to the "default" response. ok is false when the
+// operation does not document the status.
+func (o *Operation) ResponseSchema(status int) (schema *Schema, ok bool) {
+	response, ok := o.Responses[fmt.Sprint(status)]
+	if !ok {
+		response, ok = o.Responses["default"]
+	}
+	if !ok {
+		return nil, false
+	}
+	return jsonSchema(response.Content), true
+}
+
+// SuccessSchema returns the lowest documented 2xx status and its body schema
+func (o *Operation) SuccessSchema() (status string, schema *Schema) {
+	var codes []string
+	for code := range o.Responses {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		return "", nil
+	}
+	sort.Strings(codes)
+	return codes[0], jsonSchema(o.Responses[codes[0]].Content)
+}
+
+// PathParameters returns the parameters that are part of the path
+func (o *Operation) PathParameters() []Parameter {
+	return o.parametersIn("path")
+}
+
+// QueryParameters returns the parameters passed in the query string
+func (o *Operation) QueryParameters() []Parameter {
+	return o.parametersIn("query")
+}
+
+func (o *Operation) parametersIn(in string) []Parameter {
+	var parameters []Parameter
+	for _, parameter := range o.Parameters {
+		if parameter.In == in {
+			parameters = append(parameters, parameter)
+		}
+	}
+	return parameters
+}
+
+// jsonSchema picks the JSON content schema, or any schema if there is no JSON
+func jsonSchema(content map[string]MediaType) *Schema {
+	if media, ok := content["application/json"]; ok {
+		return media.Schema
+	}
+	for _, media := range content {
+		if media.Schema != nil {
+			return media.Schema
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSpec = `
+openapi: 3.0.1
+paths:
+  /things/{id}:
+    get:
+      operationId: getThing
+      parameters:
+        - {in: path, name: id, required: true, schema: {type: integer, format: int64}}
+        - {in: query, name: expand, schema: {type: boolean}}
+      responses:
+        "200":
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Alias'}
+        "404":
+          description: not found
+    delete:
+      operationId: deleteThing
+      responses:
+        "200": {description: ok}
+  /things:
+    post:
+      operationId: createThing
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Thing'}
+      responses:
+        "200": {description: ok}
+components:
+  schemas:
+    Alias:
+      $ref: '#/components/schemas/Thing'
+    Thing:
+      type: object
+      properties:
+        name: {type: string}
+        extra:
+          type: object
+          additionalProperties: true
+`
+
+func TestParse(t *testing.T) {
+	doc, err := Parse([]byte(testSpec))
+	require.NoError(t, err)
+
+	operations := doc.Operations()
+	require.Len(t, operations, 3)
+	assert.Equal(t, "POST /things", operations[0].Method+" "+operations[0].Path)
+	assert.Equal(t, "DELETE /things/{id}", operations[1].Method+" "+operations[1].Path)
+	assert.Equal(t, "GET /things/{id}", operations[2].Method+" "+operations[2].Path)
+
+	get := doc.Operation("get", "/things/{id}")
+	require.NotNil(t, get)
+	assert.Len(t, get.PathParameters(), 1)
+	assert.Len(t, get.QueryParameters(), 1)
+
+	status, schema := get.SuccessSchema()
+	assert.Equal(t, "200", status)
+	resolved, err := doc.Resolve(schema)
+	require.NoError(t, err)
+	assert.Same(t, doc.Schema("Thing"), resolved)
+	assert.NotNil(t, resolved.Properties["extra"].AdditionalProperties)
+
+	schema, ok := get.ResponseSchema(404)
+	assert.True(t, ok)
+	assert.Nil(t, schema)
+	_, ok = get.ResponseSchema(500)
+	assert.False(t, ok)
+
+	assert.Equal(t, "Thing", RefName(doc.Operation("POST", "/things").RequestSchema().Ref))
+	assert.Equal(t, []string{"Alias", "Thing"}, doc.SchemaNames())
+}
+
+func TestResolve_Errors(t *testing.T) {
+	doc, err := Parse([]byte(testSpec))
+	require.NoError(t, err)
+
+	_, err = doc.Resolve(&Schema{Ref: "#/components/schemas/Missing"})
+	assert.Error(t, err)
+
+	doc.Components.Schemas["Loop"] = &Schema{Ref: "#/components/schemas/Loop"}
+	_, err = doc.Resolve(doc.Schema("Loop"))
+	assert.Error(t, err)
+}
+
+func TestLoad_RepositorySpec(t *testing.T) {
+	doc, err := Load("../../api.yaml")
+	require.NoError(t, err)
+	assert.NotNil(t, doc.Schema("Order"))
+	assert.NotNil(t, doc.Operation("POST", "/api/v0/equity/orders/market"))
+}
@@ -0,0 +1,285 @@
+package trading212
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/internal/jsonl"
+)
+
+// JournalEntryKind represents the kind of a journal entry
+type JournalEntryKind string
+
+const (
+	// JournalEntryRequest is written before an order request is sent
+	JournalEntryRequest JournalEntryKind = "REQUEST"
+	// JournalEntryResponse is written once the API has answered (or failed)
+	JournalEntryResponse JournalEntryKind = "RESPONSE"
+	// JournalEntryStatus records a later status update of an order
+	JournalEntryStatus JournalEntryKind = "STATUS"
+)
+
+// JournalEntry represents a single record in an order journal
+type JournalEntry struct {
+	Kind        JournalEntryKind `json:"kind"`
+	RequestID   string           `json:"requestId,omitempty"`
+	Operation   string           `json:"operation"`
+	Environment string           `json:"environment"`
+	DryRun      bool             `json:"dryRun,omitempty"`
+	Timestamp   time.Time        `json:"timestamp"`
+	Latency     time.Duration    `json:"latency,omitempty"`
+	Ticker      string           `json:"ticker,omitempty"`
+	OrderID     int64            `json:"orderId,omitempty"`
+	PieID       int64            `json:"pieId,omitempty"`
+	Status      OrderStatus      `json:"status,omitempty"`
+	Request     json.RawMessage  `json:"request,omitempty"`
+	Order       *Order           `json:"order,omitempty"`
+	Error       string           `json:"error,omitempty"`
+}
+
+// errJournalWrite is returned when a request entry cannot be recorded, in
+// which case the request is never sent
+var errJournalWrite = errors.New("failed to write order journal")
+
+// JournalQuery represents filters for reading a journal; zero fields match
+// everything
+type JournalQuery struct {
+	Kind      JournalEntryKind
+	RequestID string
+	Operation string
+	Ticker    string
+	OrderID   int64
+	From      time.Time
+	To        time.Time
+}
+
+// Matches reports whether entry satisfies the query
+func (q JournalQuery) Matches(entry JournalEntry) bool {
+	switch {
+	case q.Kind != "" && entry.Kind != q.Kind:
+		return false
+	case q.RequestID != "" && entry.RequestID != q.RequestID:
+		return false
+	case q.Operation != "" && entry.Operation != q.Operation:
+		return false
+	case q.Ticker != "" && entry.Ticker != q.Ticker:
+		return false
+	case q.OrderID != 0 && entry.OrderID != q.OrderID:
+		return false
+	case !q.From.IsZero() && entry.Timestamp.Before(q.From):
+		return false
+	case !q.To.IsZero() && !entry.Timestamp.Before(q.To):
+		return false
+	}
+	return true
+}
+
+// OrderJournal records order requests, responses and status updates
+type OrderJournal interface {
+	Append(entry JournalEntry) error
+	Query(q JournalQuery) ([]JournalEntry, error)
+}
+
+// SetOrderJournal records every Place*, CancelOrder and pie create, update,
+// duplicate and delete call to journal.
+// The request entry is written before the request is sent and the call fails
+// if it cannot be written; a failure to write the response entry is not
+// returned because the order has already been sent.
+func (c *Client) SetOrderJournal(journal OrderJournal) {
+	c.journal = journal
+}
+
+// RecordOrderStatus appends a status update for order to the journal. It is
+// a no-op when no journal is set. GetOrderByID and GetOrders record status
+// changes themselves, so polling either keeps the journal up to date.
+func (c *Client) RecordOrderStatus(order *Order) error {
+	if c.journal == nil {
+		return nil
+	}
+	c.seenStatus(order)
+	return c.journal.Append(c.statusEntry(order))
+}
+
+// journalStatus records the status of a fetched order when it differs from
+// the last one seen. Like responses, status entries are best effort.
+func (c *Client) journalStatus(order *Order) {
+	if c.journal == nil || !c.seenStatus(order) {
+		return
+	}
+	_ = c.journal.Append(c.statusEntry(order))
+}
+
+// seenStatus remembers the status of order and reports whether it changed
+func (c *Client) seenStatus(order *Order) bool {
+	c.statusesMu.Lock()
+	defer c.statusesMu.Unlock()
+	if c.statuses == nil {
+		c.statuses = make(map[int64]OrderStatus)
+	}
+	if status, ok := c.statuses[order.ID]; ok && status == order.Status {
+		return false
+	}
+	c.statuses[order.ID] = order.Status
+	return true
+}
+
+func (c *Client) statusEntry(order *Order) JournalEntry {
+	return JournalEntry{
+		Kind:        JournalEntryStatus,
+		Operation:   "OrderStatus",
+		Environment: c.baseURL,
+		DryRun:      c.dryRun,
+		Timestamp:   time.Now(),
+		Ticker:      order.Ticker,
+		OrderID:     order.ID,
+		Status:      order.Status,
+		Order:       order,
+	}
+}
+
+// newRequestID returns a random identifier linking journal entries
+func newRequestID() string {
+	return NewIdempotencyKey()
+}
+
+// journalRequest writes the request entry of an order or pie operation
+func (c *Client) journalRequest(requestID, operation, ticker string, orderID, pieID int64, body interface{}) error {
+	if c.journal == nil {
+		return nil
+	}
+
+	entry := JournalEntry{
+		Kind:        JournalEntryRequest,
+		RequestID:   requestID,
+		Operation:   operation,
+		Environment: c.baseURL,
+		DryRun:      c.dryRun,
+		Timestamp:   time.Now(),
+		Ticker:      ticker,
+		OrderID:     orderID,
+		PieID:       pieID,
+	}
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal journal request: %w", err)
+		}
+		entry.Request = raw
+	}
+
+	if err := c.journal.Append(entry); err != nil {
+		return fmt.Errorf("%w: %v", errJournalWrite, err)
+	}
+	return nil
+}
+
+// journalResponse writes the response entry of an order or pie operation
+func (c *Client) journalResponse(requestID, operation, ticker string, orderID, pieID int64, started time.Time, order *Order, err error) {
+	if c.journal == nil {
+		return
+	}
+
+	entry := JournalEntry{
+		Kind:        JournalEntryResponse,
+		RequestID:   requestID,
+		Operation:   operation,
+		Environment: c.baseURL,
+		DryRun:      c.dryRun,
+		Timestamp:   time.Now(),
+		Latency:     time.Since(started),
+		Ticker:      ticker,
+		OrderID:     orderID,
+		PieID:       pieID,
+		Order:       order,
+	}
+	if order != nil {
+		entry.OrderID = order.ID
+		entry.Status = order.Status
+		c.seenStatus(order)
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	_ = c.journal.Append(entry)
+}
+
+// MemoryJournal is an in-memory OrderJournal
+type MemoryJournal struct {
+	mu      sync.Mutex
+	entries []JournalEntry
+}
+
+// NewMemoryJournal creates an empty in-memory journal
+func NewMemoryJournal() *MemoryJournal {
+	return &MemoryJournal{}
+}
+
+// Append adds an entry to the journal
+func (j *MemoryJournal) Append(entry JournalEntry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.entries = append(j.entries, entry)
+	return nil
+}
+
+// Query returns the entries matching q in the order they were appended
+func (j *MemoryJournal) Query(q JournalQuery) ([]JournalEntry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var entries []JournalEntry
+	for _, entry := range j.entries {
+		if q.Matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// FileJournal is an OrderJournal backed by an append-only JSON Lines file
+type FileJournal struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileJournal creates a journal that appends to the file at path
+func NewFileJournal(path string) *FileJournal {
+	return &FileJournal{path: path}
+}
+
+// Append writes an entry to the journal file and syncs it to disk
+func (j *FileJournal) Append(entry JournalEntry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if err := jsonl.Append(j.path, entry); err != nil {
+		return fmt.Errorf("failed to write journal entry: %w", err)
+	}
+	return nil
+}
+
+// Query reads the journal file and returns the entries matching q
+func (j *FileJournal) Query(q JournalQuery) ([]JournalEntry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var entries []JournalEntry
+	err := jsonl.Read(j.path, func(line int, data []byte) error {
+		var entry JournalEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return fmt.Errorf("failed to decode journal entry on line %d: %w", line, err)
+		}
+		if q.Matches(entry) {
+			entries = append(entries, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,108 @@
+package trading212
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileJournal_RecordsOrderCalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v0/equity/orders/market":
+			json.NewEncoder(w).Encode(Order{ID: 42, Ticker: "AAPL_US_EQ", Status: OrderStatusNew})
+		case "/api/v0/equity/orders/42":
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	journal := NewFileJournal(filepath.Join(t.TempDir(), "orders.jsonl"))
+	client := NewClient(Environment(server.URL), "key", "secret")
+	client.SetOrderJournal(journal)
+
+	order, err := client.PlaceMarketOrder(context.Background(), MarketOrderRequest{Ticker: "AAPL_US_EQ", Quantity: 1})
+	require.NoError(t, err)
+	assert.Error(t, client.CancelOrder(context.Background(), order.ID))
+
+	order.Status = OrderStatusFilled
+	require.NoError(t, client.RecordOrderStatus(order))
+
+	entries, err := journal.Query(JournalQuery{})
+	require.NoError(t, err)
+	require.Len(t, entries, 5)
+
+	assert.Equal(t, JournalEntryRequest, entries[0].Kind)
+	assert.Equal(t, "PlaceMarketOrder", entries[0].Operation)
+	assert.Equal(t, server.URL, entries[0].Environment)
+	assert.JSONEq(t, `{"extendedHours":false,"quantity":1,"ticker":"AAPL_US_EQ"}`, string(entries[0].Request))
+
+	assert.Equal(t, JournalEntryResponse, entries[1].Kind)
+	assert.Equal(t, entries[0].RequestID, entries[1].RequestID)
+	assert.Equal(t, int64(42), entries[1].OrderID)
+	assert.Equal(t, OrderStatusNew, entries[1].Status)
+
+	assert.Equal(t, "CancelOrder", entries[3].Operation)
+	assert.Contains(t, entries[3].Error, "404")
+
+	byOrder, err := journal.Query(JournalQuery{OrderID: 42, Kind: JournalEntryStatus})
+	require.NoError(t, err)
+	require.Len(t, byOrder, 1)
+	assert.Equal(t, OrderStatusFilled, byOrder[0].Status)
+}
+
+type failingJournal struct{ MemoryJournal }
+
+func (*failingJournal) Append(JournalEntry) error { return assert.AnError }
+
+func TestJournal_WriteFailurePreventsRequest(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	client := NewClient(Environment(server.URL), "key", "secret")
+	client.SetOrderJournal(&failingJournal{})
+
+	_, err := client.PlaceMarketOrder(context.Background(), MarketOrderRequest{Ticker: "AAPL_US_EQ", Quantity: 1})
+	assert.ErrorIs(t, err, errJournalWrite)
+	assert.False(t, called)
+}
+
+func TestJournal_RecordsPolledStatusChanges(t *testing.T) {
+	statuses := []OrderStatus{OrderStatusNew, OrderStatusNew, OrderStatusFilled}
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v0/equity/orders/market":
+			json.NewEncoder(w).Encode(Order{ID: 42, Ticker: "AAPL_US_EQ", Status: OrderStatusNew})
+		case "/api/v0/equity/orders/42":
+			json.NewEncoder(w).Encode(Order{ID: 42, Ticker: "AAPL_US_EQ", Status: statuses[polls]})
+			polls++
+		}
+	}))
+	defer server.Close()
+
+	journal := NewMemoryJournal()
+	client := NewClient(Environment(server.URL), "key", "secret")
+	client.SetOrderJournal(journal)
+
+	_, err := client.PlaceMarketOrder(context.Background(), MarketOrderRequest{Ticker: "AAPL_US_EQ", Quantity: 1})
+	require.NoError(t, err)
+	for range statuses {
+		_, err := client.GetOrderByID(context.Background(), 42)
+		require.NoError(t, err)
+	}
+
+	entries, err := journal.Query(JournalQuery{OrderID: 42, Kind: JournalEntryStatus})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, OrderStatusFilled, entries[0].Status)
+}
@@ -0,0 +1,541 @@
+// Package ledger mirrors the account history (historical orders, dividends
+// and transactions) into a local store. The first sync pages through the
+// whole history, remembering the next page so an interrupted run resumes where
+// it stopped; later syncs only fetch the pages newer than what is stored.
+// Items are deduplicated by fill ID or reference (their contents when there
+// is no reference), so re-fetching a page is harmless.
+package ledger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+)
+
+// Stream represents one of the mirrored history endpoints
+type Stream string
+
+const (
+	StreamOrders       Stream = "orders"
+	StreamDividends    Stream = "dividends"
+	StreamTransactions Stream = "transactions"
+)
+
+// Streams lists every stream in sync order
+var Streams = []Stream{StreamOrders, StreamDividends, StreamTransactions}
+
+// Source represents the history endpoints; *trading212.Client implements it
+type Source interface {
+	GetHistoricalOrders(ctx context.Context, opts *trading212.HistoryOrdersOptions) (*trading212.PaginatedResponse[trading212.HistoricalOrder], error)
+	GetDividends(ctx context.Context, opts *trading212.HistoryDividendsOptions) (*trading212.PaginatedResponse[trading212.HistoryDividendItem], error)
+	GetTransactions(ctx context.Context, opts *trading212.HistoryTransactionsOptions) (*trading212.PaginatedResponse[trading212.HistoryTransactionItem], error)
+	GetHistoricalOrdersPage(ctx context.Context, path string) (*trading212.PaginatedResponse[trading212.HistoricalOrder], error)
+	GetDividendsPage(ctx context.Context, path string) (*trading212.PaginatedResponse[trading212.HistoryDividendItem], error)
+	GetTransactionsPage(ctx context.Context, path string) (*trading212.PaginatedResponse[trading212.HistoryTransactionItem], error)
+}
+
+// Options represents options for a Ledger
+type Options struct {
+	// PageSize is the number of items requested per page (default 50, the
+	// API maximum)
+	PageSize int
+	// RetryDelay is how long to wait after a 429 response (default 10s; the
+	// history endpoints allow 6 requests per minute)
+	RetryDelay time.Duration
+}
+
+// Query represents filters for reading the ledger; zero fields match
+// everything. From is inclusive and To exclusive.
+type Query struct {
+	// Ticker is ignored for transactions, which have no ticker
+	Ticker string
+	From   time.Time
+	To     time.Time
+}
+
+// SyncResult counts the items added by a sync
+type SyncResult struct {
+	Orders       int
+	Dividends    int
+	Transactions int
+}
+
+// Ledger is a local, queryable copy of the account history
+type Ledger struct {
+	store Store
+	opts  Options
+
+	mu           sync.RWMutex
+	state        State
+	orders       []trading212.HistoricalOrder
+	dividends    []trading212.HistoryDividendItem
+	transactions []trading212.HistoryTransactionItem
+	keys         map[Stream]map[string]bool
+}
+
+// Open loads the ledger from store
+func Open(store Store, opts *Options) (*Ledger, error) {
+	l := &Ledger{store: store, opts: Options{PageSize: 50, RetryDelay: 10 * time.Second}}
+	if opts != nil {
+		if opts.PageSize > 0 {
+			l.opts.PageSize = opts.PageSize
+		}
+		if opts.RetryDelay > 0 {
+			l.opts.RetryDelay = opts.RetryDelay
+		}
+	}
+
+	snapshot, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+	l.state = snapshot.State
+	if l.state.Streams == nil {
+		l.state.Streams = make(map[Stream]StreamState)
+	}
+	l.keys = make(map[Stream]map[string]bool)
+	for _, stream := range Streams {
+		l.keys[stream] = make(map[string]bool)
+		if err := l.add(stream, snapshot.Records[stream]); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// State returns the sync state of every stream
+func (l *Ledger) State() State {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	state := State{Streams: make(map[Stream]StreamState, len(l.state.Streams))}
+	for stream, s := range l.state.Streams {
+		state.Streams[stream] = s
+	}
+	return state
+}
+
+// Sync fetches new items of every stream. Items added before an error are
+// kept and counted.
+func (l *Ledger) Sync(ctx context.Context, source Source) (SyncResult, error) {
+	var result SyncResult
+	counts := map[Stream]*int{
+		StreamOrders:       &result.Orders,
+		StreamDividends:    &result.Dividends,
+		StreamTransactions: &result.Transactions,
+	}
+	for _, stream := range Streams {
+		added, err := l.SyncStream(ctx, source, stream)
+		*counts[stream] = added
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// SyncStream fetches new items of one stream and returns how many were
+// added. The first sync, and any sync after an interrupted one, pages back
+// to the oldest item, saving the next page path after every page. Otherwise only
+// pages down to the first already stored item are fetched.
+func (l *Ledger) SyncStream(ctx context.Context, source Source, stream Stream) (int, error) {
+	fetch, err := l.fetcher(source, stream)
+	if err != nil {
+		return 0, err
+	}
+
+	l.mu.RLock()
+	state := l.state.Streams[stream]
+	stored := len(l.keys[stream])
+	l.mu.RUnlock()
+
+	added := 0
+	if stored > 0 || state.Complete {
+		n, complete, err := l.syncHead(ctx, stream, fetch)
+		added += n
+		if err != nil {
+			return added, err
+		}
+		if complete {
+			state = StreamState{Complete: true}
+			err := l.saveState(stream, func(s *StreamState) {
+				s.Complete, s.NextPage = true, ""
+			})
+			if err != nil {
+				return added, err
+			}
+		}
+	}
+
+	if !state.Complete {
+		n, err := l.backfill(ctx, stream, fetch, state.NextPage)
+		added += n
+		if err != nil {
+			return added, err
+		}
+	}
+
+	return added, l.saveState(stream, func(s *StreamState) {
+		s.LastSync = time.Now().UTC()
+	})
+}
+
+// syncHead fetches the newest pages until one contains a stored item, and
+// stores the new items together so an interruption cannot leave a gap. It
+// reports whether it reached the end of the history instead.
+func (l *Ledger) syncHead(ctx context.Context, stream Stream, fetch fetchFunc) (int, bool, error) {
+	var records []Record
+	next := ""
+	complete := false
+	for {
+		page, err := l.fetchPage(ctx, fetch, next)
+		if err != nil {
+			return 0, false, err
+		}
+
+		overlap := false
+		l.mu.RLock()
+		for _, record := range page.records {
+			if l.keys[stream][record.Key] {
+				overlap = true
+			} else {
+				records = append(records, record)
+			}
+		}
+		l.mu.RUnlock()
+
+		if overlap {
+			break
+		}
+		if page.next == "" || page.next == next {
+			complete = true
+			break
+		}
+		next = page.next
+	}
+
+	added, err := l.append(stream, records)
+	return added, complete, err
+}
+
+// backfill pages from next, or the newest page when empty, to the oldest
+// item, storing each page and the path of the next one as it goes
+func (l *Ledger) backfill(ctx context.Context, stream Stream, fetch fetchFunc, next string) (int, error) {
+	added := 0
+	for {
+		page, err := l.fetchPage(ctx, fetch, next)
+		if err != nil {
+			return added, err
+		}
+		n, err := l.append(stream, page.records)
+		added += n
+		if err != nil {
+			return added, err
+		}
+
+		done := page.next == "" || page.next == next
+		next = page.next
+		err = l.saveState(stream, func(s *StreamState) {
+			s.Complete = done
+			s.NextPage = ""
+			if !done {
+				s.NextPage = next
+			}
+		})
+		if err != nil || done {
+			return added, err
+		}
+	}
+}
+
+// page is a fetched page converted to records; next is its NextPagePath
+type page struct {
+	records []Record
+	next    string
+}
+
+// fetchFunc fetches the page at a NextPagePath, or the newest page when
+// next is empty
+type fetchFunc func(ctx context.Context, next string) (*page, error)
+
+// fetchPage fetches a page, waiting and retrying after 429 responses
+func (l *Ledger) fetchPage(ctx context.Context, fetch fetchFunc, next string) (*page, error) {
+	for {
+		p, err := fetch(ctx, next)
+		var apiErr *trading212.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(l.opts.RetryDelay):
+			}
+			continue
+		}
+		return p, err
+	}
+}
+
+// fetcher returns the page fetch function of a stream. Later pages are
+// requested by their NextPagePath exactly as returned, which carries every
+// parameter of the page.
+func (l *Ledger) fetcher(source Source, stream Stream) (fetchFunc, error) {
+	limit := l.opts.PageSize
+	switch stream {
+	case StreamOrders:
+		return func(ctx context.Context, next string) (*page, error) {
+			var result *trading212.PaginatedResponse[trading212.HistoricalOrder]
+			var err error
+			if next == "" {
+				result, err = source.GetHistoricalOrders(ctx, &trading212.HistoryOrdersOptions{Limit: limit})
+			} else {
+				result, err = source.GetHistoricalOrdersPage(ctx, next)
+			}
+			if err != nil {
+				return nil, err
+			}
+			return toPage(result, OrderKey)
+		}, nil
+	case StreamDividends:
+		return func(ctx context.Context, next string) (*page, error) {
+			var result *trading212.PaginatedResponse[trading212.HistoryDividendItem]
+			var err error
+			if next == "" {
+				result, err = source.GetDividends(ctx, &trading212.HistoryDividendsOptions{Limit: limit})
+			} else {
+				result, err = source.GetDividendsPage(ctx, next)
+			}
+			if err != nil {
+				return nil, err
+			}
+			return toPage(result, DividendKey)
+		}, nil
+	case StreamTransactions:
+		return func(ctx context.Context, next string) (*page, error) {
+			var result *trading212.PaginatedResponse[trading212.HistoryTransactionItem]
+			var err error
+			if next == "" {
+				result, err = source.GetTransactions(ctx, &trading212.HistoryTransactionsOptions{Limit: limit})
+			} else {
+				result, err = source.GetTransactionsPage(ctx, next)
+			}
+			if err != nil {
+				return nil, err
+			}
+			return toPage(result, TransactionKey)
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown stream %q", stream)
+}
+
+func toPage[T any](result *trading212.PaginatedResponse[T], key func(T) string) (*page, error) {
+	p := &page{records: make([]Record, 0, len(result.Items))}
+	for _, item := range result.Items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode history item: %w", err)
+		}
+		p.records = append(p.records, Record{Key: key(item), Data: data})
+	}
+	p.next, _ = result.NextPage()
+	return p, nil
+}
+
+// append stores the records whose keys are new and returns how many
+func (l *Ledger) append(stream Stream, records []Record) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	seen := make(map[string]bool, len(records))
+	var fresh []Record
+	for _, record := range records {
+		if l.keys[stream][record.Key] || seen[record.Key] {
+			continue
+		}
+		seen[record.Key] = true
+		fresh = append(fresh, record)
+	}
+	if len(fresh) == 0 {
+		return 0, nil
+	}
+
+	if err := l.store.Append(stream, fresh); err != nil {
+		return 0, err
+	}
+	if err := l.addLocked(stream, fresh); err != nil {
+		return 0, err
+	}
+	return len(fresh), nil
+}
+
+// saveState updates the state of a stream and persists it
+func (l *Ledger) saveState(stream Stream, update func(*StreamState)) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s := l.state.Streams[stream]
+	update(&s)
+	l.state.Streams[stream] = s
+	return l.store.SaveState(l.state)
+}
+
+func (l *Ledger) add(stream Stream, records []Record) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.addLocked(stream, records)
+}
+
+// addLocked decodes records into the in-memory copy, skipping keys that are
+// already present; callers must hold l.mu
+func (l *Ledger) addLocked(stream Stream, records []Record) error {
+	for _, record := range records {
+		if l.keys[stream][record.Key] {
+			continue
+		}
+
+		var err error
+		switch stream {
+		case StreamOrders:
+			var item trading212.HistoricalOrder
+			if err = json.Unmarshal(record.Data, &item); err == nil {
+				l.orders = append(l.orders, item)
+			}
+		case StreamDividends:
+			var item trading212.HistoryDividendItem
+			if err = json.Unmarshal(record.Data, &item); err == nil {
+				l.dividends = append(l.dividends, item)
+			}
+		case StreamTransactions:
+			var item trading212.HistoryTransactionItem
+			if err = json.Unmarshal(record.Data, &item); err == nil {
+				l.transactions = append(l.transactions, item)
+			}
+		default:
+			err = fmt.Errorf("unknown stream %q", stream)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to decode %s record %s: %w", stream, record.Key, err)
+		}
+		l.keys[stream][record.Key] = true
+	}
+	return nil
+}
+
+// OrderKey identifies a historical order: its fill ID, or the order ID for
+// orders that were never filled
+func OrderKey(item trading212.HistoricalOrder) string {
+	if item.Fill.ID != 0 {
+		return "fill:" + strconv.FormatInt(item.Fill.ID, 10)
+	}
+	return "order:" + strconv.FormatInt(item.Order.ID, 10)
+}
+
+// DividendKey identifies a dividend by its reference, or by ticker, payment
+// time, amount and type when it has none
+func DividendKey(item trading212.HistoryDividendItem) string {
+	if item.Reference != "" {
+		return item.Reference
+	}
+	return compositeKey("dividend", item.Ticker, item.PaidOn, item.Amount, string(item.Type))
+}
+
+// TransactionKey identifies a transaction by its reference, or by time, type
+// and amount when it has none
+func TransactionKey(item trading212.HistoryTransactionItem) string {
+	if item.Reference != "" {
+		return item.Reference
+	}
+	return compositeKey("transaction", "", item.DateTime, item.Amount, string(item.Type))
+}
+
+// compositeKey builds the key of an item without a reference
+func compositeKey(kind, ticker string, at time.Time, amount float64, itemType string) string {
+	return strings.Join([]string{kind, ticker, at.UTC().Format(time.RFC3339Nano), strconv.FormatFloat(amount, 'f', -1, 64), itemType}, "|")
+}
+
+// OrderTime returns when an order was filled, or created if it was not
+func OrderTime(item trading212.HistoricalOrder) time.Time {
+	if !item.Fill.FilledAt.IsZero() {
+		return item.Fill.FilledAt
+	}
+	return item.Order.CreatedAt
+}
+
+// Orders returns the historical orders matching q, oldest first
+func (l *Ledger) Orders(q Query) []trading212.HistoricalOrder {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return filter(l.orders, q, func(item trading212.HistoricalOrder) (string, time.Time) {
+		return item.Order.Ticker, OrderTime(item)
+	})
+}
+
+// Dividends returns the dividends matching q, oldest first
+func (l *Ledger) Dividends(q Query) []trading212.HistoryDividendItem {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return filter(l.dividends, q, func(item trading212.HistoryDividendItem) (string, time.Time) {
+		return item.Ticker, item.PaidOn
+	})
+}
+
+// Transactions returns the transactions matching q, oldest first
+func (l *Ledger) Transactions(q Query) []trading212.HistoryTransactionItem {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	q.Ticker = ""
+	return filter(l.transactions, q, func(item trading212.HistoryTransactionItem) (string, time.Time) {
+		return "", item.DateTime
+	})
+}
+
+// Tickers returns every ticker with orders or dividends, sorted
+func (l *Ledger) Tickers() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	for _, item := range l.orders {
+		seen[item.Order.Ticker] = true
+	}
+	for _, item := range l.dividends {
+		seen[item.Ticker] = true
+	}
+	delete(seen, "")
+
+	tickers := make([]string, 0, len(seen))
+	for ticker := range seen {
+		tickers = append(tickers, ticker)
+	}
+	sort.Strings(tickers)
+	return tickers
+}
+
+func filter[T any](items []T, q Query, fields func(T) (string, time.Time)) []T {
+	var result []T
+	for _, item := range items {
+		ticker, at := fields(item)
+		switch {
+		case q.Ticker != "" && ticker != q.Ticker:
+			continue
+		case !q.From.IsZero() && at.Before(q.From):
+			continue
+		case !q.To.IsZero() && !at.Before(q.To):
+			continue
+		}
+		result = append(result, item)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		_, a := fields(result[i])
+		_, b := fields(result[j])
+		return a.Before(b)
+	})
+	return result
+}
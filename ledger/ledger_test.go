@@ -0,0 +1,265 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+)
+
+// fakeSource serves history newest first in pages, using the index of the
+// first item on the page as the cursor
+type fakeSource struct {
+	orders    []trading212.HistoricalOrder
+	dividends []trading212.HistoryDividendItem
+	cursors   []int64
+	failAt    int64
+	limited   int
+}
+
+func (f *fakeSource) GetHistoricalOrders(ctx context.Context, opts *trading212.HistoryOrdersOptions) (*trading212.PaginatedResponse[trading212.HistoricalOrder], error) {
+	f.cursors = append(f.cursors, opts.Cursor)
+	if f.limited > 0 {
+		f.limited--
+		return nil, &trading212.APIError{StatusCode: http.StatusTooManyRequests}
+	}
+	if f.failAt != 0 && opts.Cursor == f.failAt {
+		return nil, errors.New("connection reset")
+	}
+	return paged(f.orders, opts.Cursor, opts.Limit, "orders"), nil
+}
+
+func (f *fakeSource) GetDividends(ctx context.Context, opts *trading212.HistoryDividendsOptions) (*trading212.PaginatedResponse[trading212.HistoryDividendItem], error) {
+	return paged(f.dividends, opts.Cursor, opts.Limit, "dividends"), nil
+}
+
+func (f *fakeSource) GetTransactions(ctx context.Context, opts *trading212.HistoryTransactionsOptions) (*trading212.PaginatedResponse[trading212.HistoryTransactionItem], error) {
+	return &trading212.PaginatedResponse[trading212.HistoryTransactionItem]{
+		Items: []trading212.HistoryTransactionItem{{Reference: "t1", Type: trading212.TransactionTypeDeposit, Amount: 100}},
+	}, nil
+}
+
+func (f *fakeSource) GetHistoricalOrdersPage(ctx context.Context, path string) (*trading212.PaginatedResponse[trading212.HistoricalOrder], error) {
+	cursor, limit := pageParams(path)
+	return f.GetHistoricalOrders(ctx, &trading212.HistoryOrdersOptions{Cursor: cursor, Limit: limit})
+}
+
+func (f *fakeSource) GetDividendsPage(ctx context.Context, path string) (*trading212.PaginatedResponse[trading212.HistoryDividendItem], error) {
+	cursor, limit := pageParams(path)
+	return f.GetDividends(ctx, &trading212.HistoryDividendsOptions{Cursor: cursor, Limit: limit})
+}
+
+func (f *fakeSource) GetTransactionsPage(ctx context.Context, path string) (*trading212.PaginatedResponse[trading212.HistoryTransactionItem], error) {
+	return nil, fmt.Errorf("unexpected page %s", path)
+}
+
+// pageParams reads back the parameters paged puts in NextPagePath
+func pageParams(path string) (cursor int64, limit int) {
+	u, _ := url.Parse(path)
+	cursor, _ = strconv.ParseInt(u.Query().Get("cursor"), 10, 64)
+	limit, _ = strconv.Atoi(u.Query().Get("limit"))
+	return cursor, limit
+}
+
+func paged[T any](items []T, cursor int64, limit int, name string) *trading212.PaginatedResponse[T] {
+	start := int(cursor)
+	end := start + limit
+	if end > len(items) {
+		end = len(items)
+	}
+	page := &trading212.PaginatedResponse[T]{Items: items[start:end]}
+	if end < len(items) {
+		next := fmt.Sprintf("/api/v0/equity/history/%s?limit=%d&cursor=%d", name, limit, end)
+		page.NextPagePath = &next
+	}
+	return page
+}
+
+var start = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// fill returns the order filled n days after start
+func fill(n int, ticker string) trading212.HistoricalOrder {
+	return trading212.HistoricalOrder{
+		Order: trading212.Order{ID: int64(1000 + n), Ticker: ticker},
+		Fill:  trading212.Fill{ID: int64(n), FilledAt: start.AddDate(0, 0, n), Quantity: 1},
+	}
+}
+
+// newestFirst returns fills for days n-1 down to 0
+func newestFirst(n int) []trading212.HistoricalOrder {
+	orders := make([]trading212.HistoricalOrder, 0, n)
+	for i := n - 1; i >= 0; i-- {
+		ticker := "AAPL_US_EQ"
+		if i%2 == 1 {
+			ticker = "MSFT_US_EQ"
+		}
+		orders = append(orders, fill(i, ticker))
+	}
+	return orders
+}
+
+func TestSyncIsIncremental(t *testing.T) {
+	source := &fakeSource{orders: newestFirst(7)}
+	l, err := Open(NewMemoryStore(), &Options{PageSize: 3})
+	require.NoError(t, err)
+
+	result, err := l.Sync(context.Background(), source)
+	require.NoError(t, err)
+	assert.Equal(t, SyncResult{Orders: 7, Transactions: 1}, result)
+	assert.Equal(t, []int64{0, 3, 6}, source.cursors)
+	assert.True(t, l.State().Streams[StreamOrders].Complete)
+
+	// Two new fills arrive on top; only the first page is fetched again
+	source.orders = append([]trading212.HistoricalOrder{fill(8, "AAPL_US_EQ"), fill(7, "MSFT_US_EQ")}, source.orders...)
+	source.cursors = nil
+	result, err = l.Sync(context.Background(), source)
+	require.NoError(t, err)
+	assert.Equal(t, SyncResult{Orders: 2}, result)
+	assert.Equal(t, []int64{0}, source.cursors)
+	assert.Len(t, l.Orders(Query{}), 9)
+}
+
+func TestSyncResumesInterruptedBackfill(t *testing.T) {
+	dir := t.TempDir()
+	source := &fakeSource{orders: newestFirst(7), failAt: 6}
+
+	store, err := NewFileStore(dir)
+	require.NoError(t, err)
+	l, err := Open(store, &Options{PageSize: 3})
+	require.NoError(t, err)
+
+	added, err := l.SyncStream(context.Background(), source, StreamOrders)
+	require.Error(t, err)
+	assert.Equal(t, 6, added)
+	assert.Equal(t, StreamState{NextPage: "/api/v0/equity/history/orders?limit=3&cursor=6"}, l.State().Streams[StreamOrders])
+
+	// A new process picks up from the saved page after checking the head
+	source.failAt = 0
+	source.cursors = nil
+	store, err = NewFileStore(dir)
+	require.NoError(t, err)
+	l, err = Open(store, &Options{PageSize: 3})
+	require.NoError(t, err)
+	added, err = l.SyncStream(context.Background(), source, StreamOrders)
+	require.NoError(t, err)
+	assert.Equal(t, 1, added)
+	assert.Equal(t, []int64{0, 6}, source.cursors)
+	assert.Len(t, l.Orders(Query{}), 7)
+	assert.True(t, l.State().Streams[StreamOrders].Complete)
+}
+
+func TestSyncRetriesRateLimit(t *testing.T) {
+	source := &fakeSource{orders: newestFirst(2), limited: 2}
+	l, err := Open(NewMemoryStore(), &Options{RetryDelay: time.Millisecond})
+	require.NoError(t, err)
+
+	added, err := l.SyncStream(context.Background(), source, StreamOrders)
+	require.NoError(t, err)
+	assert.Equal(t, 2, added)
+	assert.Len(t, source.cursors, 3)
+}
+
+func TestOpenDeduplicates(t *testing.T) {
+	store := NewMemoryStore()
+	source := &fakeSource{orders: newestFirst(3)}
+	l, err := Open(store, nil)
+	require.NoError(t, err)
+	_, err = l.SyncStream(context.Background(), source, StreamOrders)
+	require.NoError(t, err)
+
+	// Simulate a crash between Append and SaveState: the page is stored twice
+	snapshot, err := store.Load()
+	require.NoError(t, err)
+	require.NoError(t, store.Append(StreamOrders, snapshot.Records[StreamOrders]))
+
+	l, err = Open(store, nil)
+	require.NoError(t, err)
+	assert.Len(t, l.Orders(Query{}), 3)
+}
+
+func TestOrderKey(t *testing.T) {
+	assert.Equal(t, "fill:5", OrderKey(fill(5, "AAPL_US_EQ")))
+	assert.Equal(t, "order:9", OrderKey(trading212.HistoricalOrder{Order: trading212.Order{ID: 9}}))
+}
+
+func TestKeysWithoutReference(t *testing.T) {
+	assert.Equal(t, "r1", DividendKey(trading212.HistoryDividendItem{Reference: "r1", Ticker: "AAPL_US_EQ"}))
+
+	a := trading212.HistoryDividendItem{Ticker: "AAPL_US_EQ", PaidOn: start, Amount: 1.5, Type: trading212.DividendTypeOrdinary}
+	b := a
+	b.Amount = 2
+	assert.NotEmpty(t, DividendKey(a))
+	assert.Equal(t, DividendKey(a), DividendKey(a))
+	assert.NotEqual(t, DividendKey(a), DividendKey(b))
+
+	deposit := trading212.HistoryTransactionItem{DateTime: start, Type: trading212.TransactionTypeDeposit, Amount: 100}
+	withdrawal := deposit
+	withdrawal.Type = trading212.TransactionTypeWithdraw
+	assert.NotEqual(t, TransactionKey(deposit), TransactionKey(withdrawal))
+	assert.NotEqual(t, "", TransactionKey(deposit))
+}
+
+func TestQueries(t *testing.T) {
+	source := &fakeSource{
+		orders: newestFirst(6),
+		dividends: []trading212.HistoryDividendItem{
+			{Reference: "d2", Ticker: "MSFT_US_EQ", PaidOn: start.AddDate(0, 2, 0)},
+			{Reference: "d1", Ticker: "AAPL_US_EQ", PaidOn: start.AddDate(0, 1, 0)},
+		},
+	}
+	l, err := Open(NewMemoryStore(), nil)
+	require.NoError(t, err)
+	_, err = l.Sync(context.Background(), source)
+	require.NoError(t, err)
+
+	orders := l.Orders(Query{Ticker: "AAPL_US_EQ", From: start.AddDate(0, 0, 1), To: start.AddDate(0, 0, 4)})
+	require.Len(t, orders, 1)
+	assert.Equal(t, int64(2), orders[0].Fill.ID)
+
+	all := l.Orders(Query{})
+	for i := 1; i < len(all); i++ {
+		assert.True(t, all[i-1].Fill.FilledAt.Before(all[i].Fill.FilledAt), "oldest first")
+	}
+
+	dividends := l.Dividends(Query{})
+	require.Len(t, dividends, 2)
+	assert.Equal(t, "d1", dividends[0].Reference)
+	assert.Len(t, l.Dividends(Query{Ticker: "MSFT_US_EQ"}), 1)
+	assert.Len(t, l.Transactions(Query{Ticker: "AAPL_US_EQ"}), 1)
+	assert.Equal(t, []string{"AAPL_US_EQ", "MSFT_US_EQ"}, l.Tickers())
+}
+
+func TestFileStoreIgnoresTruncatedLine(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(dir)
+	require.NoError(t, err)
+	require.NoError(t, store.Append(StreamDividends, []Record{{Key: "a", Data: []byte(`{"reference":"a"}`)}}))
+
+	path := filepath.Join(dir, "dividends.jsonl")
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"key":"b","data":{"refer`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	snapshot, err := store.Load()
+	require.NoError(t, err)
+	assert.Len(t, snapshot.Records[StreamDividends], 1)
+
+	require.NoError(t, store.Append(StreamDividends, []Record{{Key: "c", Data: []byte(`{"reference":"c"}`)}}))
+	snapshot, err = store.Load()
+	require.NoError(t, err)
+	require.Len(t, snapshot.Records[StreamDividends], 2)
+	assert.Equal(t, "c", snapshot.Records[StreamDividends][1].Key)
+}
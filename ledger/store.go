@@ -0,0 +1,208 @@
+package ledger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/internal/jsonl"
+)
+
+// Record represents a stored history item
+type Record struct {
+	// Key deduplicates items within a stream, see OrderKey, DividendKey and
+	// TransactionKey
+	Key  string          `json:"key"`
+	Data json.RawMessage `json:"data"`
+}
+
+// StreamState represents the sync progress of a stream
+type StreamState struct {
+	// NextPage is the NextPagePath where an unfinished backfill resumes
+	NextPage string `json:"nextPage,omitempty"`
+	// Complete is set once the oldest item has been fetched
+	Complete bool      `json:"complete"`
+	LastSync time.Time `json:"lastSync"`
+}
+
+// State represents the sync progress of every stream
+type State struct {
+	Streams map[Stream]StreamState `json:"streams"`
+}
+
+// Snapshot represents everything in a store
+type Snapshot struct {
+	Records map[Stream][]Record
+	State   State
+}
+
+// Store persists ledger records and sync state. Records may be appended
+// more than once, e.g. after a crash between Append and SaveState; the
+// ledger keeps the first record of each key.
+type Store interface {
+	Load() (*Snapshot, error)
+	Append(stream Stream, records []Record) error
+	SaveState(state State) error
+}
+
+// MemoryStore is an in-memory Store
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[Stream][]Record
+	state   State
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[Stream][]Record)}
+}
+
+// Load returns a copy of the stored records and state
+func (s *MemoryStore) Load() (*Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := &Snapshot{Records: make(map[Stream][]Record), State: State{Streams: make(map[Stream]StreamState)}}
+	for stream, records := range s.records {
+		snapshot.Records[stream] = append([]Record(nil), records...)
+	}
+	for stream, state := range s.state.Streams {
+		snapshot.State.Streams[stream] = state
+	}
+	return snapshot, nil
+}
+
+// Append adds records to a stream
+func (s *MemoryStore) Append(stream Stream, records []Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[stream] = append(s.records[stream], records...)
+	return nil
+}
+
+// SaveState replaces the sync state
+func (s *MemoryStore) SaveState(state State) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = State{Streams: make(map[Stream]StreamState, len(state.Streams))}
+	for stream, st := range state.Streams {
+		s.state.Streams[stream] = st
+	}
+	return nil
+}
+
+// FileStore is a Store backed by a directory holding one append-only JSON
+// Lines file per stream and a state.json file
+type FileStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewFileStore creates a store in dir, which is created if needed
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create ledger directory: %w", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Load reads every stream file and the state file. A truncated last line,
+// left by a crash during Append, is ignored and removed by the next Append.
+func (s *FileStore) Load() (*Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := &Snapshot{Records: make(map[Stream][]Record)}
+	for _, stream := range Streams {
+		records, err := s.readStream(stream)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Records[stream] = records
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.dir, "state.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return snapshot, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ledger state: %w", err)
+	}
+	if err := json.Unmarshal(data, &snapshot.State); err != nil {
+		return nil, fmt.Errorf("failed to decode ledger state: %w", err)
+	}
+	return snapshot, nil
+}
+
+// Append writes records to the stream file and syncs it to disk
+func (s *FileStore) Append(stream Stream, records []Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values := make([]interface{}, len(records))
+	for i := range records {
+		values[i] = records[i]
+	}
+	if err := jsonl.Append(s.streamPath(stream), values...); err != nil {
+		return fmt.Errorf("failed to write ledger: %w", err)
+	}
+	return nil
+}
+
+// SaveState atomically replaces the state file
+func (s *FileStore) SaveState(state State) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode ledger state: %w", err)
+	}
+
+	path := filepath.Join(s.dir, "state.json")
+	tmp, err := os.CreateTemp(s.dir, "state.json.*")
+	if err != nil {
+		return fmt.Errorf("failed to write ledger state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write ledger state: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write ledger state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write ledger state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write ledger state: %w", err)
+	}
+	return nil
+}
+
+func (s *FileStore) streamPath(stream Stream) string {
+	return filepath.Join(s.dir, string(stream)+".jsonl")
+}
+
+func (s *FileStore) readStream(stream Stream) ([]Record, error) {
+	var records []Record
+	err := jsonl.Read(s.streamPath(stream), func(line int, data []byte) error {
+		var record Record
+		if err := json.Unmarshal(data, &record); err != nil {
+			return fmt.Errorf("failed to decode %s ledger on line %d: %w", stream, line, err)
+		}
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ledger: %w", err)
+	}
+	return records, nil
+}
@@ -0,0 +1,132 @@
+package trading212
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Money represents an exact amount in an ISO 4217 currency. The zero value
+// has no currency and acts as zero in arithmetic with any currency.
+type Money struct {
+	Amount   Decimal `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+var (
+	// ErrCurrencyMismatch is returned when combining amounts in different currencies
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	// ErrInvalidCurrency is returned for currency codes that are not ISO 4217 shaped
+	ErrInvalidCurrency = errors.New("invalid currency code")
+)
+
+// NewMoney pairs amount with currency, which must be a three letter
+// upper-case ISO 4217 code such as GBP
+func NewMoney(amount Decimal, currency string) (Money, error) {
+	if !isCurrencyCode(currency) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidCurrency, currency)
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// MustMoney is like NewMoney but parses amount and panics on invalid input
+func MustMoney(amount, currency string) Money {
+	m, err := NewMoney(MustParseDecimal(amount), currency)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Add returns m + o, failing if the currencies differ
+func (m Money) Add(o Money) (Money, error) {
+	currency, err := m.common(o)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount.Add(o.Amount), Currency: currency}, nil
+}
+
+// Sub returns m - o, failing if the currencies differ
+func (m Money) Sub(o Money) (Money, error) {
+	return m.Add(o.Neg())
+}
+
+// Cmp compares m and o, failing if the currencies differ
+func (m Money) Cmp(o Money) (int, error) {
+	if _, err := m.common(o); err != nil {
+		return 0, err
+	}
+	return m.Amount.Cmp(o.Amount), nil
+}
+
+// Mul returns m scaled by factor, e.g. a per-share amount times a quantity
+func (m Money) Mul(factor Decimal) Money {
+	return Money{Amount: m.Amount.Mul(factor), Currency: m.Currency}
+}
+
+// Neg returns -m
+func (m Money) Neg() Money {
+	return Money{Amount: m.Amount.Neg(), Currency: m.Currency}
+}
+
+// Round returns m rounded half away from zero to places decimal places
+func (m Money) Round(places int32) Money {
+	return Money{Amount: m.Amount.Round(places), Currency: m.Currency}
+}
+
+// IsZero reports whether the amount is 0
+func (m Money) IsZero() bool {
+	return m.Amount.IsZero()
+}
+
+// Convert returns m in currency using rate, the number of units of currency
+// per unit of m.Currency
+func (m Money) Convert(rate Decimal, currency string) (Money, error) {
+	return NewMoney(m.Amount.Mul(rate), currency)
+}
+
+// String returns the amount followed by the currency, e.g. "12.34 GBP"
+func (m Money) String() string {
+	if m.Currency == "" {
+		return m.Amount.String()
+	}
+	return m.Amount.String() + " " + m.Currency
+}
+
+// SumMoney adds values that all share one currency
+func SumMoney(values ...Money) (Money, error) {
+	var total Money
+	for _, value := range values {
+		var err error
+		if total, err = total.Add(value); err != nil {
+			return Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// common returns the currency shared by m and o; a zero Money without a
+// currency takes the currency of the other operand
+func (m Money) common(o Money) (string, error) {
+	switch {
+	case m.Currency == o.Currency:
+		return m.Currency, nil
+	case m.Currency == "" && m.IsZero():
+		return o.Currency, nil
+	case o.Currency == "" && o.IsZero():
+		return m.Currency, nil
+	}
+	return "", fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+}
+
+func isCurrencyCode(s string) bool {
+	if len(s) != 3 {
+		return false
+	}
+	for _, r := range s {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
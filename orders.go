@@ -2,8 +2,10 @@ package trading212
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 )
 
 // MarketOrderRequest represents a market order request
@@ -38,6 +40,67 @@ type StopLimitOrderRequest struct {
 	TimeValidity TimeValidity `json:"timeValidity"`
 }
 
+// Validate checks the request for values the API would reject
+func (r MarketOrderRequest) Validate() error {
+	return validateOrder(r.Ticker, r.Quantity)
+}
+
+// Validate checks the request for values the API would reject
+func (r LimitOrderRequest) Validate() error {
+	if err := validateOrder(r.Ticker, r.Quantity); err != nil {
+		return err
+	}
+	if r.LimitPrice <= 0 {
+		return fmt.Errorf("invalid limit price %v", r.LimitPrice)
+	}
+	return r.TimeValidity.Validate()
+}
+
+// Validate checks the request for values the API would reject
+func (r StopOrderRequest) Validate() error {
+	if err := validateOrder(r.Ticker, r.Quantity); err != nil {
+		return err
+	}
+	if r.StopPrice <= 0 {
+		return fmt.Errorf("invalid stop price %v", r.StopPrice)
+	}
+	return r.TimeValidity.Validate()
+}
+
+// Validate checks the request for values the API would reject
+func (r StopLimitOrderRequest) Validate() error {
+	if err := validateOrder(r.Ticker, r.Quantity); err != nil {
+		return err
+	}
+	if r.LimitPrice <= 0 {
+		return fmt.Errorf("invalid limit price %v", r.LimitPrice)
+	}
+	if r.StopPrice <= 0 {
+		return fmt.Errorf("invalid stop price %v", r.StopPrice)
+	}
+	return r.TimeValidity.Validate()
+}
+
+// Validate checks that the time validity is one the API accepts
+func (v TimeValidity) Validate() error {
+	switch v {
+	case TimeValidityDay, TimeValidityGoodTillCancel:
+		return nil
+	}
+	return fmt.Errorf("invalid time validity %q", v)
+}
+
+// validateOrder checks the fields common to every order request
+func validateOrder(ticker string, quantity float64) error {
+	if ticker == "" {
+		return errors.New("ticker is required")
+	}
+	if quantity == 0 {
+		return errors.New("quantity must not be zero")
+	}
+	return nil
+}
+
 // GetOrders retrieves all pending orders
 func (c *Client) GetOrders(ctx context.Context) ([]Order, error) {
 	resp, err := c.makeRequest(ctx, http.MethodGet, "/api/v0/equity/orders", nil)
@@ -49,6 +112,9 @@ func (c *Client) GetOrders(ctx context.Context) ([]Order, error) {
 	if err := c.handleResponse(resp, &orders); err != nil {
 		return nil, err
 	}
+	for i := range orders {
+		c.journalStatus(&orders[i])
+	}
 
 	return orders, nil
 }
@@ -65,58 +131,82 @@ func (c *Client) GetOrderByID(ctx context.Context, orderID int64) (*Order, error
 	if err := c.handleResponse(resp, &order); err != nil {
 		return nil, err
 	}
+	c.journalStatus(&order)
 
 	return &order, nil
 }
 
 // PlaceMarketOrder places a market order
 func (c *Client) PlaceMarketOrder(ctx context.Context, req MarketOrderRequest) (*Order, error) {
-	resp, err := c.makeRequest(ctx, http.MethodPost, "/api/v0/equity/orders/market", req)
-	if err != nil {
-		return nil, err
-	}
-
-	var order Order
-	if err := c.handleResponse(resp, &order); err != nil {
-		return nil, err
-	}
-
-	return &order, nil
+	return c.placeOrder(ctx, "PlaceMarketOrder", "/api/v0/equity/orders/market", req.Ticker, req)
 }
 
 // PlaceLimitOrder places a limit order
 func (c *Client) PlaceLimitOrder(ctx context.Context, req LimitOrderRequest) (*Order, error) {
-	resp, err := c.makeRequest(ctx, http.MethodPost, "/api/v0/equity/orders/limit", req)
-	if err != nil {
-		return nil, err
+	return c.placeOrder(ctx, "PlaceLimitOrder", "/api/v0/equity/orders/limit", req.Ticker, req)
+}
+
+// PlaceStopOrder places a stop order
+func (c *Client) PlaceStopOrder(ctx context.Context, req StopOrderRequest) (*Order, error) {
+	return c.placeOrder(ctx, "PlaceStopOrder", "/api/v0/equity/orders/stop", req.Ticker, req)
+}
+
+// PlaceStopLimitOrder places a stop-limit order
+func (c *Client) PlaceStopLimitOrder(ctx context.Context, req StopLimitOrderRequest) (*Order, error) {
+	return c.placeOrder(ctx, "PlaceStopLimitOrder", "/api/v0/equity/orders/stop_limit", req.Ticker, req)
+}
+
+// CancelOrder cancels an order by ID
+func (c *Client) CancelOrder(ctx context.Context, orderID int64) error {
+	requestID := newRequestID()
+	if err := c.journalRequest(requestID, "CancelOrder", "", orderID, 0, nil); err != nil {
+		return err
+	}
+	started := time.Now()
+
+	if c.dryRun {
+		var err error
+		if orderID == 0 {
+			err = errors.New("order ID is required")
+		}
+		c.logDryRun(DryRunCall{Operation: "CancelOrder", OrderID: orderID, Err: err})
+		c.journalResponse(requestID, "CancelOrder", "", orderID, 0, started, nil, err)
+		return err
 	}
 
-	var order Order
-	if err := c.handleResponse(resp, &order); err != nil {
-		return nil, err
+	path := fmt.Sprintf("/api/v0/equity/orders/%d", orderID)
+	resp, err := c.makeRequest(ctx, http.MethodDelete, path, nil)
+	if err == nil {
+		err = c.handleResponse(resp, nil)
 	}
 
-	return &order, nil
+	c.journalResponse(requestID, "CancelOrder", "", orderID, 0, started, nil, err)
+	return err
 }
 
-// PlaceStopOrder places a stop order
-func (c *Client) PlaceStopOrder(ctx context.Context, req StopOrderRequest) (*Order, error) {
-	resp, err := c.makeRequest(ctx, http.MethodPost, "/api/v0/equity/orders/stop", req)
-	if err != nil {
+// placeOrder sends an order request, recording it in the order journal
+func (c *Client) placeOrder(ctx context.Context, operation, path, ticker string, req interface{}) (*Order, error) {
+	requestID := newRequestID()
+	if err := c.journalRequest(requestID, operation, ticker, 0, 0, req); err != nil {
 		return nil, err
 	}
-
-	var order Order
-	if err := c.handleResponse(resp, &order); err != nil {
-		return nil, err
+	started := time.Now()
+
+	var order *Order
+	var err error
+	if c.dryRun {
+		order, err = c.dryRunOrder(req)
+		c.logDryRun(DryRunCall{Operation: operation, Request: req, Err: err})
+	} else {
+		order, err = c.sendOrder(ctx, path, req)
 	}
-
-	return &order, nil
+	c.journalResponse(requestID, operation, ticker, 0, 0, started, order, err)
+	return order, err
 }
 
-// PlaceStopLimitOrder places a stop-limit order
-func (c *Client) PlaceStopLimitOrder(ctx context.Context, req StopLimitOrderRequest) (*Order, error) {
-	resp, err := c.makeRequest(ctx, http.MethodPost, "/api/v0/equity/orders/stop_limit", req)
+// sendOrder posts an order request and decodes the created order
+func (c *Client) sendOrder(ctx context.Context, path string, req interface{}) (*Order, error) {
+	resp, err := c.makeRequest(ctx, http.MethodPost, path, req)
 	if err != nil {
 		return nil, err
 	}
@@ -127,15 +217,4 @@ func (c *Client) PlaceStopLimitOrder(ctx context.Context, req StopLimitOrderRequ
 	}
 
 	return &order, nil
-}
-
-// CancelOrder cancels an order by ID
-func (c *Client) CancelOrder(ctx context.Context, orderID int64) error {
-	path := fmt.Sprintf("/api/v0/equity/orders/%d", orderID)
-	resp, err := c.makeRequest(ctx, http.MethodDelete, path, nil)
-	if err != nil {
-		return err
-	}
-
-	return c.handleResponse(resp, nil)
 }
\ No newline at end of file
@@ -0,0 +1,156 @@
+package trading212
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrStopPaging can be returned by ForEach* callbacks to stop early without
+// an error
+var ErrStopPaging = errors.New("stop paging")
+
+// historyRetryDelay is how long ForEach* helpers wait after a 429 response;
+// the history endpoints allow 6 requests per minute
+var historyRetryDelay = 10 * time.Second
+
+// NextPage returns NextPagePath, and false when there are no more pages. The
+// API asks for the whole path to be requested as is, as it carries every
+// parameter of the next page.
+func (p *PaginatedResponse[T]) NextPage() (string, bool) {
+	if p.NextPagePath == nil || *p.NextPagePath == "" {
+		return "", false
+	}
+	return *p.NextPagePath, true
+}
+
+// NextCursor returns the cursor parameter of NextPagePath, and false when
+// there are no more pages. Use NextPage to fetch the next page.
+func (p *PaginatedResponse[T]) NextCursor() (string, bool) {
+	if p.NextPagePath == nil || *p.NextPagePath == "" {
+		return "", false
+	}
+	next, err := url.Parse(*p.NextPagePath)
+	if err != nil {
+		return "", false
+	}
+	cursor := next.Query().Get("cursor")
+	return cursor, cursor != ""
+}
+
+// ForEachHistoricalOrder calls fn for every historical order, following
+// NextPagePath across pages and waiting out rate limit responses
+func (c *Client) ForEachHistoricalOrder(ctx context.Context, opts *HistoryOrdersOptions, fn func(HistoricalOrder) error) error {
+	return paginate(ctx, func() (*PaginatedResponse[HistoricalOrder], error) {
+		return c.GetHistoricalOrders(ctx, opts)
+	}, func(path string) (*PaginatedResponse[HistoricalOrder], error) {
+		return c.GetHistoricalOrdersPage(ctx, path)
+	}, fn)
+}
+
+// ForEachDividend calls fn for every dividend, following NextPagePath across
+// pages and waiting out rate limit responses
+func (c *Client) ForEachDividend(ctx context.Context, opts *HistoryDividendsOptions, fn func(HistoryDividendItem) error) error {
+	return paginate(ctx, func() (*PaginatedResponse[HistoryDividendItem], error) {
+		return c.GetDividends(ctx, opts)
+	}, func(path string) (*PaginatedResponse[HistoryDividendItem], error) {
+		return c.GetDividendsPage(ctx, path)
+	}, fn)
+}
+
+// ForEachTransaction calls fn for every transaction, following NextPagePath
+// across pages and waiting out rate limit responses
+func (c *Client) ForEachTransaction(ctx context.Context, opts *HistoryTransactionsOptions, fn func(HistoryTransactionItem) error) error {
+	return paginate(ctx, func() (*PaginatedResponse[HistoryTransactionItem], error) {
+		return c.GetTransactions(ctx, opts)
+	}, func(path string) (*PaginatedResponse[HistoryTransactionItem], error) {
+		return c.GetTransactionsPage(ctx, path)
+	}, fn)
+}
+
+// GetHistoricalOrdersPage retrieves the page of historical orders at a
+// NextPagePath
+func (c *Client) GetHistoricalOrdersPage(ctx context.Context, path string) (*PaginatedResponse[HistoricalOrder], error) {
+	var result PaginatedResponse[HistoricalOrder]
+	if err := c.getPage(ctx, path, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetDividendsPage retrieves the page of dividends at a NextPagePath
+func (c *Client) GetDividendsPage(ctx context.Context, path string) (*PaginatedResponse[HistoryDividendItem], error) {
+	var result PaginatedResponse[HistoryDividendItem]
+	if err := c.getPage(ctx, path, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetTransactionsPage retrieves the page of transactions at a NextPagePath
+func (c *Client) GetTransactionsPage(ctx context.Context, path string) (*PaginatedResponse[HistoryTransactionItem], error) {
+	var result PaginatedResponse[HistoryTransactionItem]
+	if err := c.getPage(ctx, path, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// getPage requests a NextPagePath as returned, which must be a path on the
+// API host
+func (c *Client) getPage(ctx context.Context, path string, result interface{}) error {
+	if !strings.HasPrefix(path, "/") || strings.HasPrefix(path, "//") {
+		return fmt.Errorf("invalid next page path %q", path)
+	}
+	resp, err := c.makeRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	return c.handleResponse(resp, result)
+}
+
+// paginate fetches the first page and then each NextPagePath until they run
+// out, calling fn per item
+func paginate[T any](ctx context.Context, first func() (*PaginatedResponse[T], error), next func(path string) (*PaginatedResponse[T], error), fn func(T) error) error {
+	path := ""
+	for {
+		var page *PaginatedResponse[T]
+		var err error
+		if path == "" {
+			page, err = first()
+		} else {
+			page, err = next(path)
+		}
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(historyRetryDelay):
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, item := range page.Items {
+			if err := fn(item); err != nil {
+				if errors.Is(err, ErrStopPaging) {
+					return nil
+				}
+				return err
+			}
+		}
+
+		nextPath, ok := page.NextPage()
+		if !ok || nextPath == path {
+			return nil
+		}
+		path = nextPath
+	}
+}
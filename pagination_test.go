@@ -0,0 +1,138 @@
+package trading212
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginatedResponse_NextCursor(t *testing.T) {
+	path := "/api/v0/equity/history/orders?limit=20&cursor=1700000000000&ticker=AAPL_US_EQ"
+	cursor, ok := (&PaginatedResponse[HistoricalOrder]{NextPagePath: &path}).NextCursor()
+	assert.True(t, ok)
+	assert.Equal(t, "1700000000000", cursor)
+
+	_, ok = (&PaginatedResponse[HistoricalOrder]{}).NextCursor()
+	assert.False(t, ok)
+
+	empty := ""
+	_, ok = (&PaginatedResponse[HistoricalOrder]{NextPagePath: &empty}).NextCursor()
+	assert.False(t, ok)
+}
+
+func TestClient_ForEachHistoricalOrder(t *testing.T) {
+	previous := historyRetryDelay
+	historyRetryDelay = time.Millisecond
+	defer func() { historyRetryDelay = previous }()
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "AAPL_US_EQ", r.URL.Query().Get("ticker"))
+		switch {
+		case requests == 2:
+			w.WriteHeader(http.StatusTooManyRequests)
+		case r.URL.Query().Get("cursor") == "55":
+			w.Write([]byte(`{"items": [{"order": {"id": 2}}], "nextPagePath": null}`))
+		default:
+			w.Write([]byte(`{"items": [{"order": {"id": 1}}], "nextPagePath": "/api/v0/equity/history/orders?ticker=AAPL_US_EQ&cursor=55"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(Environment(server.URL), "test-key", "test-secret")
+	opts := &HistoryOrdersOptions{Ticker: "AAPL_US_EQ"}
+
+	var ids []int64
+	err := client.ForEachHistoricalOrder(context.Background(), opts, func(order HistoricalOrder) error {
+		ids = append(ids, order.Order.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, ids)
+	assert.Equal(t, 3, requests)
+	assert.Equal(t, int64(0), opts.Cursor, "caller options must not be modified")
+}
+
+func TestClient_ForEachTransaction_FollowsNextPagePath(t *testing.T) {
+	since := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	next := "/api/v0/equity/history/transactions?limit=2&cursor=abc&time=2024-02-01T09%3A30%3A00Z"
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.RequestURI())
+		if len(requested) == 1 {
+			json.NewEncoder(w).Encode(PaginatedResponse[HistoryTransactionItem]{Items: []HistoryTransactionItem{{Reference: "a"}}, NextPagePath: &next})
+			return
+		}
+		json.NewEncoder(w).Encode(PaginatedResponse[HistoryTransactionItem]{Items: []HistoryTransactionItem{{Reference: "b"}}})
+	}))
+	defer server.Close()
+
+	client := NewClient(Environment(server.URL), "test-key", "test-secret")
+	var references []string
+	err := client.ForEachTransaction(context.Background(), &HistoryTransactionsOptions{Time: &since, Limit: 2}, func(item HistoryTransactionItem) error {
+		references = append(references, item.Reference)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, references)
+	require.Len(t, requested, 2)
+	assert.Contains(t, requested[0], "time=2024-03-01T12%3A00%3A00Z")
+	assert.Equal(t, next, requested[1])
+
+	_, err = client.GetTransactionsPage(context.Background(), "https://example.com/api/v0/equity/history/transactions")
+	assert.Error(t, err)
+}
+
+func TestClient_ForEachTransaction_StopPaging(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"items": [{"reference": "a"}, {"reference": "b"}], "nextPagePath": "/api/v0/equity/history/transactions?cursor=next"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(Environment(server.URL), "test-key", "test-secret")
+	var references []string
+	err := client.ForEachTransaction(context.Background(), nil, func(item HistoryTransactionItem) error {
+		references = append(references, item.Reference)
+		return ErrStopPaging
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, references)
+	assert.Equal(t, 1, requests)
+}
+
+func TestClient_CreatePie(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v0/equity/pies", r.URL.Path)
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "Income", body["name"])
+		assert.Equal(t, "TO_ACCOUNT_CASH", body["dividendCashAction"])
+		assert.NotContains(t, body, "endDate")
+
+		w.Write([]byte(`{"instruments": [{"ticker": "VUSAl_EQ", "expectedShare": 1}], "settings": {"id": 11, "name": "Income", "dividendCashAction": "TO_ACCOUNT_CASH"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(Environment(server.URL), "test-key", "test-secret")
+	pie, err := client.CreatePie(context.Background(), PieRequest{
+		Name:               "Income",
+		DividendCashAction: DividendCashActionToAccountCash,
+		InstrumentShares:   map[string]float64{"VUSAl_EQ": 1},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), pie.Settings.ID)
+	assert.Equal(t, DividendCashActionToAccountCash, pie.Settings.DividendCashAction)
+	require.Len(t, pie.Instruments, 1)
+	assert.Equal(t, "VUSAl_EQ", pie.Instruments[0].Ticker)
+}
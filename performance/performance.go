@@ -0,0 +1,412 @@
+// Package performance measures the return of an account over a period from
+// valuations and the cash that moved in and out of it. The time-weighted
+// return removes the effect of deposits and withdrawals; the money-weighted
+// return (XIRR) includes it. Returns are fractions: 0.05 is 5%.
+package performance
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+)
+
+var (
+	// ErrNoValuation is returned when no valuation covers the start or end
+	// of the period
+	ErrNoValuation = errors.New("no valuation for the period")
+	// ErrNoConvergence is returned when XIRR finds no rate
+	ErrNoConvergence = errors.New("xirr did not converge")
+)
+
+// daysPerYear annualises XIRR
+const daysPerYear = 365.0
+
+// Valuation represents the value of the account at a point in time
+type Valuation struct {
+	Time time.Time `json:"time"`
+	// Value is the total value including cash
+	Value trading212.Decimal `json:"value"`
+	// Holdings is the market value of each instrument by ticker; it is
+	// needed for the contribution of each instrument
+	Holdings map[string]trading212.Decimal `json:"holdings,omitempty"`
+}
+
+// CashFlow represents money moved into (positive) or out of (negative) the
+// account
+type CashFlow struct {
+	Time   time.Time          `json:"time"`
+	Amount trading212.Decimal `json:"amount"`
+}
+
+// Input represents the history a report is computed from
+type Input struct {
+	Valuations   []Valuation
+	Transactions []trading212.HistoryTransactionItem
+	Dividends    []trading212.HistoryDividendItem
+	// Orders are used for the contribution of each instrument
+	Orders []trading212.HistoricalOrder
+}
+
+// Contribution represents the gain of an instrument over the period
+type Contribution struct {
+	Ticker     string             `json:"ticker"`
+	StartValue trading212.Decimal `json:"startValue"`
+	EndValue   trading212.Decimal `json:"endValue"`
+	// NetBought is the cost of purchases less the proceeds of sales
+	NetBought trading212.Decimal `json:"netBought"`
+	Dividends trading212.Decimal `json:"dividends"`
+	// Gain is EndValue - StartValue - NetBought + Dividends
+	Gain trading212.Decimal `json:"gain"`
+	// Contribution is Gain relative to the average capital of the account,
+	// so the contributions add up to roughly the account return before fees
+	Contribution float64 `json:"contribution"`
+}
+
+// Report represents the performance of the account over a period
+type Report struct {
+	From        time.Time          `json:"from"`
+	To          time.Time          `json:"to"`
+	StartValue  trading212.Decimal `json:"startValue"`
+	EndValue    trading212.Decimal `json:"endValue"`
+	Deposits    trading212.Decimal `json:"deposits"`
+	Withdrawals trading212.Decimal `json:"withdrawals"`
+	NetDeposits trading212.Decimal `json:"netDeposits"`
+	Fees        trading212.Decimal `json:"fees"`
+	Dividends   trading212.Decimal `json:"dividends"`
+	// Gain is EndValue - StartValue - NetDeposits
+	Gain trading212.Decimal `json:"gain"`
+	// TWR is the time-weighted return over the period, not annualised
+	TWR float64 `json:"twr"`
+	// MWR is the money-weighted return, annualised; nil when it cannot be
+	// computed, with MWRError saying why
+	MWR      *float64 `json:"mwr"`
+	MWRError string   `json:"mwrError,omitempty"`
+	// Contributions are ordered by gain, largest first; empty unless the
+	// start and end valuations have holdings
+	Contributions []Contribution `json:"contributions,omitempty"`
+}
+
+// Compute returns the performance from the last valuation at or before from
+// (or the first one after it) to the last valuation at or before to. A zero
+// from or to uses the first or last valuation.
+func Compute(in Input, from, to time.Time) (*Report, error) {
+	valuations := append([]Valuation(nil), in.Valuations...)
+	sort.SliceStable(valuations, func(i, j int) bool {
+		return valuations[i].Time.Before(valuations[j].Time)
+	})
+	start, end, ok := window(valuations, from, to)
+	if !ok {
+		return nil, ErrNoValuation
+	}
+	first, last := valuations[start], valuations[end]
+
+	report := &Report{
+		From:       first.Time,
+		To:         last.Time,
+		StartValue: first.Value,
+		EndValue:   last.Value,
+	}
+	flows := Flows(in.Transactions)
+	var period []CashFlow
+	for _, f := range flows {
+		if !within(f.Time, first.Time, last.Time) {
+			continue
+		}
+		period = append(period, f)
+		if f.Amount.Sign() > 0 {
+			report.Deposits = report.Deposits.Add(f.Amount)
+		} else {
+			report.Withdrawals = report.Withdrawals.Sub(f.Amount)
+		}
+	}
+	report.NetDeposits = report.Deposits.Sub(report.Withdrawals)
+	report.Gain = report.EndValue.Sub(report.StartValue).Sub(report.NetDeposits)
+
+	for _, t := range in.Transactions {
+		if t.Type == trading212.TransactionTypeFee && within(t.DateTime, first.Time, last.Time) {
+			report.Fees = report.Fees.Add(trading212.DecimalFromFloat(t.Amount).Abs())
+		}
+	}
+	for _, d := range in.Dividends {
+		if within(d.PaidOn, first.Time, last.Time) {
+			report.Dividends = report.Dividends.Add(trading212.DecimalFromFloat(d.Amount))
+		}
+	}
+
+	report.TWR = TWR(valuations[start:end+1], period)
+	if mwr, err := MWR(first, last, period); err != nil {
+		report.MWRError = err.Error()
+	} else {
+		report.MWR = &mwr
+	}
+
+	if first.Holdings != nil && last.Holdings != nil {
+		report.Contributions = contributions(in, first, last, period)
+	}
+	return report, nil
+}
+
+// Flows returns the deposits, withdrawals and transfers of transactions as
+// cash flows, oldest first. Fees are not flows: they reduce the return.
+func Flows(transactions []trading212.HistoryTransactionItem) []CashFlow {
+	var flows []CashFlow
+	for _, t := range transactions {
+		amount := trading212.DecimalFromFloat(t.Amount)
+		switch t.Type {
+		case trading212.TransactionTypeDeposit:
+			amount = amount.Abs()
+		case trading212.TransactionTypeWithdraw:
+			amount = amount.Abs().Neg()
+		case trading212.TransactionTypeTransfer:
+		default:
+			continue
+		}
+		flows = append(flows, CashFlow{Time: t.DateTime, Amount: amount})
+	}
+	sort.SliceStable(flows, func(i, j int) bool {
+		return flows[i].Time.Before(flows[j].Time)
+	})
+	return flows
+}
+
+// TWR returns the time-weighted return of valuations, oldest first, by
+// chaining the Modified Dietz return of each interval between consecutive
+// valuations. Flows are weighted by the part of the interval they were
+// invested for. Intervals with no capital, such as before the first
+// deposit, are skipped.
+func TWR(valuations []Valuation, flows []CashFlow) float64 {
+	growth := 1.0
+	for i := 1; i < len(valuations); i++ {
+		if r, ok := dietz(valuations[i-1], valuations[i], flows); ok {
+			growth *= 1 + r
+		}
+	}
+	return growth - 1
+}
+
+// MWR returns the annualised money-weighted return between two valuations:
+// the rate at which the start value and the flows grow to the end value
+func MWR(start, end Valuation, flows []CashFlow) (float64, error) {
+	xs := []CashFlow{{Time: start.Time, Amount: start.Value.Neg()}}
+	for _, f := range flows {
+		if within(f.Time, start.Time, end.Time) {
+			xs = append(xs, CashFlow{Time: f.Time, Amount: f.Amount.Neg()})
+		}
+	}
+	xs = append(xs, CashFlow{Time: end.Time, Amount: end.Value})
+	return XIRR(xs)
+}
+
+// XIRR returns the annualised rate r at which the flows have a net present
+// value of zero, discounting each by (1 + r)^(days/365) from the first flow.
+// The flows need at least one positive and one negative amount.
+func XIRR(flows []CashFlow) (float64, error) {
+	if len(flows) == 0 {
+		return 0, ErrNoConvergence
+	}
+	origin := flows[0].Time
+	for _, f := range flows {
+		if f.Time.Before(origin) {
+			origin = f.Time
+		}
+	}
+	years := make([]float64, len(flows))
+	amounts := make([]float64, len(flows))
+	var positive, negative bool
+	for i, f := range flows {
+		years[i] = f.Time.Sub(origin).Hours() / 24 / daysPerYear
+		amounts[i] = f.Amount.Float64()
+		positive = positive || amounts[i] > 0
+		negative = negative || amounts[i] < 0
+	}
+	if !positive || !negative {
+		return 0, ErrNoConvergence
+	}
+
+	npv := func(rate float64) (value, derivative float64) {
+		for i, a := range amounts {
+			factor := math.Pow(1+rate, -years[i])
+			value += a * factor
+			derivative -= years[i] * a * factor / (1 + rate)
+		}
+		return value, derivative
+	}
+
+	// Newton's method usually converges in a few steps
+	rate := 0.1
+	for i := 0; i < 100; i++ {
+		value, derivative := npv(rate)
+		if math.Abs(value) < 1e-9 {
+			return rate, nil
+		}
+		if derivative == 0 || math.IsNaN(derivative) {
+			break
+		}
+		next := rate - value/derivative
+		if next <= -1 || math.IsNaN(next) || math.IsInf(next, 0) {
+			break
+		}
+		if math.Abs(next-rate) < 1e-12 {
+			return next, nil
+		}
+		rate = next
+	}
+
+	// Fall back to bisection, which needs a bracket with a sign change
+	low, high := -0.999999, 1.0
+	vlow, _ := npv(low)
+	vhigh, _ := npv(high)
+	for vlow*vhigh > 0 {
+		if high > 1e6 {
+			return 0, ErrNoConvergence
+		}
+		high *= 2
+		vhigh, _ = npv(high)
+	}
+	for i := 0; i < 200; i++ {
+		mid := (low + high) / 2
+		vmid, _ := npv(mid)
+		if math.Abs(vmid) < 1e-9 || high-low < 1e-12 {
+			return mid, nil
+		}
+		if vlow*vmid < 0 {
+			high = mid
+		} else {
+			low, vlow = mid, vmid
+		}
+	}
+	return (low + high) / 2, nil
+}
+
+// dietz returns the Modified Dietz return between two valuations
+func dietz(start, end Valuation, flows []CashFlow) (float64, bool) {
+	length := end.Time.Sub(start.Time).Seconds()
+	net := 0.0
+	capital := start.Value.Float64()
+	for _, f := range flows {
+		if !within(f.Time, start.Time, end.Time) {
+			continue
+		}
+		amount := f.Amount.Float64()
+		net += amount
+		if length > 0 {
+			capital += amount * end.Time.Sub(f.Time).Seconds() / length
+		}
+	}
+	if capital <= 0 {
+		return 0, false
+	}
+	return (end.Value.Float64() - start.Value.Float64() - net) / capital, true
+}
+
+// contributions returns the gain of each instrument between two valuations
+func contributions(in Input, start, end Valuation, flows []CashFlow) []Contribution {
+	byTicker := make(map[string]*Contribution)
+	get := func(ticker string) *Contribution {
+		c := byTicker[ticker]
+		if c == nil {
+			c = &Contribution{Ticker: ticker}
+			byTicker[ticker] = c
+		}
+		return c
+	}
+
+	for ticker, value := range start.Holdings {
+		get(ticker).StartValue = value
+	}
+	for ticker, value := range end.Holdings {
+		get(ticker).EndValue = value
+	}
+	for _, order := range in.Orders {
+		f := order.Fill
+		if !within(f.FilledAt, start.Time, end.Time) || f.Quantity == 0 {
+			continue
+		}
+		switch f.Type {
+		case trading212.FillTypeStockSplit, trading212.FillTypeStockDistribution, trading212.FillTypeCustomStockDistribution:
+			continue
+		}
+		c := get(order.Order.Ticker)
+		value := fillValue(f)
+		if order.Order.Side == trading212.OrderSideSell || f.Quantity < 0 {
+			value = value.Neg()
+		}
+		c.NetBought = c.NetBought.Add(value)
+	}
+	for _, d := range in.Dividends {
+		if within(d.PaidOn, start.Time, end.Time) {
+			c := get(d.Ticker)
+			c.Dividends = c.Dividends.Add(trading212.DecimalFromFloat(d.Amount))
+		}
+	}
+
+	// The Modified Dietz capital of the whole period
+	capital := start.Value.Float64()
+	length := end.Time.Sub(start.Time).Seconds()
+	for _, f := range flows {
+		if length > 0 {
+			capital += f.Amount.Float64() * end.Time.Sub(f.Time).Seconds() / length
+		}
+	}
+
+	result := make([]Contribution, 0, len(byTicker))
+	for _, c := range byTicker {
+		c.Gain = c.EndValue.Sub(c.StartValue).Sub(c.NetBought).Add(c.Dividends)
+		if capital > 0 {
+			c.Contribution = c.Gain.Float64() / capital
+		}
+		result = append(result, *c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if cmp := result[i].Gain.Cmp(result[j].Gain); cmp != 0 {
+			return cmp > 0
+		}
+		return result[i].Ticker < result[j].Ticker
+	})
+	return result
+}
+
+// fillValue returns the absolute account currency value of a fill
+func fillValue(f trading212.Fill) trading212.Decimal {
+	if f.WalletImpact.NetValue != 0 {
+		return trading212.DecimalFromFloat(f.WalletImpact.NetValue).Abs()
+	}
+	value := trading212.DecimalFromFloat(f.Quantity).Abs().Mul(trading212.DecimalFromFloat(f.Price))
+	if f.WalletImpact.FxRate != 0 {
+		value = value.Mul(trading212.DecimalFromFloat(f.WalletImpact.FxRate))
+	}
+	return value
+}
+
+// window returns the indexes of the valuations that start and end the
+// period
+func window(valuations []Valuation, from, to time.Time) (int, int, bool) {
+	if len(valuations) == 0 {
+		return 0, 0, false
+	}
+	start := 0
+	if !from.IsZero() {
+		start = sort.Search(len(valuations), func(i int) bool {
+			return valuations[i].Time.After(from)
+		}) - 1
+		if start < 0 {
+			start = 0
+		}
+	}
+	end := len(valuations) - 1
+	if !to.IsZero() {
+		end = sort.Search(len(valuations), func(i int) bool {
+			return valuations[i].Time.After(to)
+		}) - 1
+	}
+	return start, end, end > start
+}
+
+// within reports whether t is in (from, to]; flows at the start valuation
+// are assumed to be included in it
+func within(t, from, to time.Time) bool {
+	return t.After(from) && !t.After(to)
+}
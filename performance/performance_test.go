@@ -0,0 +1,146 @@
+package performance
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+)
+
+var day0 = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func on(days int) time.Time {
+	return day0.AddDate(0, 0, days)
+}
+
+func dec(f float64) trading212.Decimal {
+	return trading212.DecimalFromFloat(f)
+}
+
+func assertDecimal(t *testing.T, want string, got trading212.Decimal) {
+	t.Helper()
+	assert.True(t, trading212.MustParseDecimal(want).Equal(got), "want %s, got %s", want, got)
+}
+
+func TestXIRR(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	rate, err := XIRR([]CashFlow{
+		{Time: start, Amount: dec(-1000)},
+		{Time: start.AddDate(1, 0, 0), Amount: dec(1100)},
+	})
+	require.NoError(t, err)
+	assert.InDelta(t, 0.1, rate, 1e-9)
+
+	// Two deposits that lose money need a negative rate
+	rate, err = XIRR([]CashFlow{
+		{Time: start, Amount: dec(-1000)},
+		{Time: start.AddDate(0, 6, 0), Amount: dec(-1000)},
+		{Time: start.AddDate(1, 0, 0), Amount: dec(1500)},
+	})
+	require.NoError(t, err)
+	assert.Less(t, rate, -0.25)
+	var npv float64
+	for _, f := range []struct {
+		days   float64
+		amount float64
+	}{{0, -1000}, {181, -1000}, {365, 1500}} {
+		npv += f.amount * math.Pow(1+rate, -f.days/365)
+	}
+	assert.InDelta(t, 0, npv, 1e-6)
+
+	_, err = XIRR([]CashFlow{{Time: start, Amount: dec(100)}})
+	assert.ErrorIs(t, err, ErrNoConvergence)
+}
+
+func TestTWRIgnoresDeposits(t *testing.T) {
+	valuations := []Valuation{
+		{Time: on(0), Value: dec(1000)},
+		{Time: on(10), Value: dec(2100)},
+		{Time: on(20), Value: dec(2310)},
+	}
+	flows := []CashFlow{{Time: on(10), Amount: dec(1000)}}
+	assert.InDelta(t, 0.21, TWR(valuations, flows), 1e-9)
+
+	// Nothing is invested before the first deposit
+	valuations = append([]Valuation{{Time: on(-5), Value: dec(0)}}, valuations...)
+	flows = append([]CashFlow{{Time: on(0), Amount: dec(1000)}}, flows...)
+	assert.InDelta(t, 0.21, TWR(valuations, flows), 1e-9)
+}
+
+func TestCompute(t *testing.T) {
+	in := Input{
+		Valuations: []Valuation{
+			{Time: on(20), Value: dec(1578), Holdings: map[string]trading212.Decimal{"A": dec(650), "B": dec(820)}},
+			{Time: on(0), Value: dec(1000), Holdings: map[string]trading212.Decimal{"A": dec(600)}},
+		},
+		Transactions: []trading212.HistoryTransactionItem{
+			{Type: trading212.TransactionTypeDeposit, Amount: 500, DateTime: on(5)},
+			{Type: trading212.TransactionTypeFee, Amount: -2, DateTime: on(16)},
+			{Type: trading212.TransactionTypeWithdraw, Amount: -100, DateTime: on(30)},
+		},
+		Dividends: []trading212.HistoryDividendItem{
+			{Ticker: "A", Amount: 10, PaidOn: on(15)},
+		},
+		Orders: []trading212.HistoricalOrder{
+			{
+				Order: trading212.Order{Ticker: "B", Side: trading212.OrderSideBuy},
+				Fill:  trading212.Fill{ID: 1, Type: trading212.FillTypeTrade, Quantity: 8, Price: 100, FilledAt: on(10)},
+			},
+		},
+	}
+
+	report, err := Compute(in, on(3), time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, on(0), report.From)
+	assert.Equal(t, on(20), report.To)
+	assertDecimal(t, "500", report.Deposits)
+	assertDecimal(t, "0", report.Withdrawals)
+	assertDecimal(t, "500", report.NetDeposits)
+	assertDecimal(t, "2", report.Fees)
+	assertDecimal(t, "10", report.Dividends)
+	assertDecimal(t, "78", report.Gain)
+	assert.InDelta(t, 78.0/1375, report.TWR, 1e-9)
+	require.NotNil(t, report.MWR)
+	assert.Greater(t, *report.MWR, report.TWR)
+	assert.Empty(t, report.MWRError)
+
+	require.Len(t, report.Contributions, 2)
+	assert.Equal(t, "A", report.Contributions[0].Ticker)
+	assertDecimal(t, "60", report.Contributions[0].Gain)
+	assert.InDelta(t, 60.0/1375, report.Contributions[0].Contribution, 1e-9)
+	assertDecimal(t, "800", report.Contributions[1].NetBought)
+	assertDecimal(t, "20", report.Contributions[1].Gain)
+
+	_, err = Compute(in, on(25), time.Time{})
+	assert.ErrorIs(t, err, ErrNoValuation)
+}
+
+func TestCompute_MWRDoesNotConverge(t *testing.T) {
+	// Nothing invested at the start and no deposits leaves XIRR without a
+	// negative flow
+	in := Input{Valuations: []Valuation{
+		{Time: on(0), Value: dec(0)},
+		{Time: on(10), Value: dec(50)},
+	}}
+
+	report, err := Compute(in, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Nil(t, report.MWR)
+	assert.Contains(t, report.MWRError, ErrNoConvergence.Error())
+	assertDecimal(t, "50", report.Gain)
+}
+
+func TestFlows(t *testing.T) {
+	flows := Flows([]trading212.HistoryTransactionItem{
+		{Type: trading212.TransactionTypeWithdraw, Amount: 50, DateTime: on(2)},
+		{Type: trading212.TransactionTypeDeposit, Amount: 100, DateTime: on(1)},
+		{Type: trading212.TransactionTypeFee, Amount: -1, DateTime: on(3)},
+	})
+	require.Len(t, flows, 2)
+	assertDecimal(t, "100", flows[0].Amount)
+	assertDecimal(t, "-50", flows[1].Amount)
+}
@@ -0,0 +1,237 @@
+package trading212
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// Pie represents a pie summary
+type Pie struct {
+	// Cash is the amount put into the pie in account currency
+	Cash            float64            `json:"cash"`
+	DividendDetails PieDividendDetails `json:"dividendDetails"`
+	ID              int64              `json:"id"`
+	// Progress is the progress towards the goal, between 0 and 1
+	Progress float64   `json:"progress"`
+	Result   PieResult `json:"result"`
+	Status   PieStatus `json:"status"`
+}
+
+// PieDividendDetails represents the dividends a pie received
+type PieDividendDetails struct {
+	Gained     float64 `json:"gained"`
+	InCash     float64 `json:"inCash"`
+	Reinvested float64 `json:"reinvested"`
+}
+
+// PieResult represents the investment result of a pie or of an instrument in it
+type PieResult struct {
+	PriceAvgInvestedValue float64 `json:"priceAvgInvestedValue"`
+	PriceAvgResult        float64 `json:"priceAvgResult"`
+	PriceAvgResultCoef    float64 `json:"priceAvgResultCoef"`
+	PriceAvgValue         float64 `json:"priceAvgValue"`
+}
+
+// PieStatus represents the progress of a pie towards its goal
+type PieStatus string
+
+const (
+	PieStatusAhead   PieStatus = "AHEAD"
+	PieStatusOnTrack PieStatus = "ON_TRACK"
+	PieStatusBehind  PieStatus = "BEHIND"
+)
+
+// PieDetails represents a pie with its settings and instruments
+type PieDetails struct {
+	Instruments []PieInstrument `json:"instruments"`
+	Settings    PieSettings     `json:"settings"`
+}
+
+// PieInstrument represents an instrument held in a pie
+type PieInstrument struct {
+	CurrentShare  float64              `json:"currentShare"`
+	ExpectedShare float64              `json:"expectedShare"`
+	Issues        []PieInstrumentIssue `json:"issues"`
+	OwnedQuantity float64              `json:"ownedQuantity"`
+	Result        PieResult            `json:"result"`
+	Ticker        string               `json:"ticker"`
+}
+
+// PieInstrumentIssue represents a problem with an instrument in a pie
+type PieInstrumentIssue struct {
+	Name     string `json:"name"`
+	Severity string `json:"severity"`
+}
+
+// PieSettings represents the settings of a pie
+type PieSettings struct {
+	CreationDate       time.Time          `json:"creationDate"`
+	DividendCashAction DividendCashAction `json:"dividendCashAction"`
+	EndDate            time.Time          `json:"endDate"`
+	Goal               float64            `json:"goal"`
+	Icon               string             `json:"icon"`
+	ID                 int64              `json:"id"`
+	InitialInvestment  float64            `json:"initialInvestment"`
+	InstrumentShares   map[string]float64 `json:"instrumentShares"`
+	Name               string             `json:"name"`
+	PublicURL          string             `json:"publicUrl"`
+}
+
+// DividendCashAction represents what happens to dividends paid into a pie
+type DividendCashAction string
+
+const (
+	DividendCashActionReinvest      DividendCashAction = "REINVEST"
+	DividendCashActionToAccountCash DividendCashAction = "TO_ACCOUNT_CASH"
+)
+
+// PieRequest represents a request to create or update a pie
+type PieRequest struct {
+	DividendCashAction DividendCashAction `json:"dividendCashAction,omitempty"`
+	EndDate            *time.Time         `json:"endDate,omitempty"`
+	// Goal is the desired value of the pie in account currency
+	Goal float64 `json:"goal,omitempty"`
+	Icon string  `json:"icon,omitempty"`
+	// InstrumentShares maps tickers to their target weights, which sum to 1
+	InstrumentShares map[string]float64 `json:"instrumentShares,omitempty"`
+	Name             string             `json:"name,omitempty"`
+}
+
+// DuplicatePieRequest represents a request to duplicate a pie
+type DuplicatePieRequest struct {
+	Icon string `json:"icon,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// GetPies retrieves all pies
+func (c *Client) GetPies(ctx context.Context) ([]Pie, error) {
+	resp, err := c.makeRequest(ctx, http.MethodGet, "/api/v0/equity/pies", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var pies []Pie
+	if err := c.handleResponse(resp, &pies); err != nil {
+		return nil, err
+	}
+
+	return pies, nil
+}
+
+// GetPie retrieves a pie with its settings and instruments
+func (c *Client) GetPie(ctx context.Context, pieID int64) (*PieDetails, error) {
+	resp, err := c.makeRequest(ctx, http.MethodGet, fmt.Sprintf("/api/v0/equity/pies/%d", pieID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var pie PieDetails
+	if err := c.handleResponse(resp, &pie); err != nil {
+		return nil, err
+	}
+
+	return &pie, nil
+}
+
+// CreatePie creates a pie
+func (c *Client) CreatePie(ctx context.Context, req PieRequest) (*PieDetails, error) {
+	return c.changePie(ctx, "CreatePie", "/api/v0/equity/pies", 0, req, func() (*PieDetails, error) {
+		if req.Name == "" {
+			return nil, errors.New("pie name is required")
+		}
+		return dryRunPie(atomic.AddInt64(&dryRunOrderID, -1), req.Name, req.Icon, req), nil
+	})
+}
+
+// UpdatePie updates a pie
+func (c *Client) UpdatePie(ctx context.Context, pieID int64, req PieRequest) (*PieDetails, error) {
+	return c.changePie(ctx, "UpdatePie", fmt.Sprintf("/api/v0/equity/pies/%d", pieID), pieID, req, func() (*PieDetails, error) {
+		return dryRunPie(pieID, req.Name, req.Icon, req), nil
+	})
+}
+
+// DuplicatePie creates a copy of a pie
+func (c *Client) DuplicatePie(ctx context.Context, pieID int64, req DuplicatePieRequest) (*PieDetails, error) {
+	return c.changePie(ctx, "DuplicatePie", fmt.Sprintf("/api/v0/equity/pies/%d/duplicate", pieID), pieID, req, func() (*PieDetails, error) {
+		return dryRunPie(atomic.AddInt64(&dryRunOrderID, -1), req.Name, req.Icon, PieRequest{}), nil
+	})
+}
+
+// DeletePie deletes a pie
+func (c *Client) DeletePie(ctx context.Context, pieID int64) error {
+	_, err := c.changePie(ctx, "DeletePie", fmt.Sprintf("/api/v0/equity/pies/%d", pieID), pieID, nil, func() (*PieDetails, error) {
+		return nil, nil
+	})
+	return err
+}
+
+// changePie sends a pie request, recording it in the order journal. In
+// dry-run mode the request is not sent and dryRun builds the result instead.
+func (c *Client) changePie(ctx context.Context, operation, path string, pieID int64, req interface{}, dryRun func() (*PieDetails, error)) (*PieDetails, error) {
+	requestID := newRequestID()
+	if err := c.journalRequest(requestID, operation, "", 0, pieID, req); err != nil {
+		return nil, err
+	}
+	started := time.Now()
+
+	var pie *PieDetails
+	var err error
+	switch {
+	case operation != "CreatePie" && pieID == 0:
+		err = errors.New("pie ID is required")
+	case c.dryRun:
+		pie, err = dryRun()
+		c.logDryRun(DryRunCall{Operation: operation, PieID: pieID, Request: req, Err: err})
+	default:
+		method := http.MethodPost
+		if req == nil {
+			method = http.MethodDelete
+		}
+		var resp *http.Response
+		resp, err = c.makeRequest(ctx, method, path, req)
+		if err == nil {
+			if req == nil {
+				err = c.handleResponse(resp, nil)
+			} else {
+				pie = &PieDetails{}
+				if err = c.handleResponse(resp, pie); err != nil {
+					pie = nil
+				}
+			}
+		}
+	}
+
+	if pie != nil && pie.Settings.ID != 0 {
+		pieID = pie.Settings.ID
+	}
+	c.journalResponse(requestID, operation, "", 0, pieID, started, nil, err)
+	return pie, err
+}
+
+// dryRunPie builds the pie the API would have returned
+func dryRunPie(pieID int64, name, icon string, req PieRequest) *PieDetails {
+	pie := &PieDetails{Settings: PieSettings{
+		CreationDate:       time.Now(),
+		DividendCashAction: req.DividendCashAction,
+		Goal:               req.Goal,
+		Icon:               icon,
+		ID:                 pieID,
+		InstrumentShares:   req.InstrumentShares,
+		Name:               name,
+	}}
+	if req.EndDate != nil {
+		pie.Settings.EndDate = *req.EndDate
+	}
+	for ticker, share := range req.InstrumentShares {
+		pie.Instruments = append(pie.Instruments, PieInstrument{Ticker: ticker, ExpectedShare: share})
+	}
+	sort.Slice(pie.Instruments, func(i, j int) bool {
+		return pie.Instruments[i].Ticker < pie.Instruments[j].Ticker
+	})
+	return pie
+}
@@ -0,0 +1,446 @@
+// Package portfolio replays historical fills to compute holdings, their cost
+// basis and open lots, and the realized profit or loss of every disposal.
+// Amounts are exact decimals. Costs and proceeds are in account currency;
+// average prices are in instrument currency, like Position.AveragePrice.
+package portfolio
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/internal/decimals"
+)
+
+// Method represents how disposals are matched with acquisitions
+type Method string
+
+const (
+	// MethodAverage pools all shares of an instrument at their average
+	// cost, as the positions endpoint does
+	MethodAverage Method = "AVERAGE"
+	// MethodFIFO matches disposals with the oldest open lots first
+	MethodFIFO Method = "FIFO"
+)
+
+// ErrUnknownMethod is returned for a cost basis method that is not supported
+var ErrUnknownMethod = errors.New("unknown cost basis method")
+
+// ParseMethod returns the method with the given name, ignoring case
+func ParseMethod(s string) (Method, error) {
+	switch m := Method(strings.ToUpper(s)); m {
+	case MethodAverage, MethodFIFO:
+		return m, nil
+	}
+	return "", fmt.Errorf("%w %q", ErrUnknownMethod, s)
+}
+
+// Lot represents shares acquired together. Under MethodAverage a holding
+// has a single pooled lot dated at its first acquisition.
+type Lot struct {
+	Ticker   string             `json:"ticker"`
+	FillID   int64              `json:"fillId"`
+	Acquired time.Time          `json:"acquired"`
+	Quantity trading212.Decimal `json:"quantity"`
+	// Cost is the cost of the lot in account currency
+	Cost trading212.Decimal `json:"cost"`
+}
+
+// UnitCost returns the cost per share in account currency
+func (l Lot) UnitCost() trading212.Decimal {
+	return decimals.Ratio(l.Cost, l.Quantity)
+}
+
+// Disposal represents shares sold, with the cost matched against them
+type Disposal struct {
+	Ticker   string             `json:"ticker"`
+	FillID   int64              `json:"fillId"`
+	Date     time.Time          `json:"date"`
+	Quantity trading212.Decimal `json:"quantity"`
+	// Proceeds is the net amount received in account currency
+	Proceeds trading212.Decimal `json:"proceeds"`
+	// Cost is the cost basis of the shares sold in account currency
+	Cost trading212.Decimal `json:"cost"`
+	// RealizedPL is Proceeds - Cost
+	RealizedPL trading212.Decimal `json:"realizedPL"`
+	// Lots are the portions of lots the disposal was matched with
+	Lots []Lot `json:"lots"`
+}
+
+// Holding represents the shares held in an instrument
+type Holding struct {
+	Ticker string `json:"ticker"`
+	// Currency is the account currency of Cost and RealizedPL
+	Currency string             `json:"currency"`
+	Quantity trading212.Decimal `json:"quantity"`
+	// Cost is the cost basis of the open lots under the portfolio method
+	Cost trading212.Decimal `json:"cost"`
+	// AveragePrice is the pooled average purchase price in instrument
+	// currency whatever the method, comparable with Position.AveragePrice
+	AveragePrice trading212.Decimal `json:"averagePrice"`
+	// RealizedPL is the profit or loss of every disposal of the instrument
+	RealizedPL trading212.Decimal `json:"realizedPL"`
+	Lots       []Lot              `json:"lots"`
+}
+
+// AverageCost returns the cost per share in account currency
+func (h Holding) AverageCost() trading212.Decimal {
+	return decimals.Ratio(h.Cost, h.Quantity)
+}
+
+// Portfolio accumulates fills in chronological order
+type Portfolio struct {
+	method    Method
+	holdings  map[string]*holding
+	disposals []Disposal
+	warnings  []string
+}
+
+// holding is the running state of an instrument
+type holding struct {
+	currency string
+	lots     []Lot
+	// instrumentCost is the pooled quantity × price in instrument currency
+	instrumentCost trading212.Decimal
+	realized       trading212.Decimal
+	// pending holds lots closed by a stock split until the opening fill
+	pending               []Lot
+	pendingInstrumentCost trading212.Decimal
+}
+
+// New creates an empty portfolio using method
+func New(method Method) (*Portfolio, error) {
+	if _, err := ParseMethod(string(method)); err != nil {
+		return nil, err
+	}
+	return &Portfolio{method: method, holdings: make(map[string]*holding)}, nil
+}
+
+// Replay sorts orders by fill time and applies them to a new portfolio
+func Replay(orders []trading212.HistoricalOrder, method Method) (*Portfolio, error) {
+	p, err := New(method)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := append([]trading212.HistoricalOrder(nil), orders...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i].Fill, sorted[j].Fill
+		if !a.FilledAt.Equal(b.FilledAt) {
+			return a.FilledAt.Before(b.FilledAt)
+		}
+		// The closing fill of a stock split comes before the opening one
+		if sa, sb := isDisposal(sorted[i]), isDisposal(sorted[j]); sa != sb {
+			return sa
+		}
+		return a.ID < b.ID
+	})
+
+	for _, order := range sorted {
+		p.Apply(order)
+	}
+	return p, nil
+}
+
+// Apply adds a fill to the portfolio. Fills must be applied oldest first.
+// Orders without a fill are ignored.
+//
+// Trades and equity rights are purchases and sales. Stock splits change the
+// quantity but not the cost: a split either closes the whole holding and
+// reopens it with the new quantity, or adjusts the quantity by the fill
+// quantity. Stock distributions add shares at no cost. FOP transfers in are
+// acquisitions at the fill value; transfers out remove shares without a
+// disposal.
+func (p *Portfolio) Apply(order trading212.HistoricalOrder) {
+	f := order.Fill
+	quantity := trading212.DecimalFromFloat(f.Quantity).Abs()
+	if quantity.IsZero() {
+		return
+	}
+
+	ticker := order.Order.Ticker
+	h := p.holdings[ticker]
+	if h == nil {
+		h = &holding{}
+		p.holdings[ticker] = h
+	}
+	if h.currency == "" {
+		h.currency = f.WalletImpact.Currency
+	}
+
+	disposal := isDisposal(order)
+	if f.Type != trading212.FillTypeStockSplit && len(h.pending) > 0 {
+		p.warn("%s: stock split close on %s was not followed by an opening fill", ticker, h.pending[0].Acquired.Format("2006-01-02"))
+		p.restorePending(h)
+	}
+
+	price := trading212.DecimalFromFloat(f.Price)
+	switch f.Type {
+	case trading212.FillTypeStockSplit:
+		if disposal {
+			p.splitClose(ticker, h, quantity)
+		} else {
+			p.splitOpen(ticker, h, quantity, f)
+		}
+	case trading212.FillTypeStockDistribution, trading212.FillTypeCustomStockDistribution:
+		if disposal {
+			p.take(ticker, h, quantity)
+		} else {
+			p.acquire(h, Lot{Ticker: ticker, FillID: f.ID, Acquired: f.FilledAt, Quantity: quantity}, trading212.Decimal{})
+		}
+	case trading212.FillTypeFOP, trading212.FillTypeFOPCorrection:
+		if disposal {
+			p.take(ticker, h, quantity)
+		} else {
+			lot := Lot{Ticker: ticker, FillID: f.ID, Acquired: f.FilledAt, Quantity: quantity, Cost: accountValue(f, quantity, price)}
+			p.acquire(h, lot, quantity.Mul(price))
+		}
+	default:
+		if disposal {
+			p.dispose(ticker, h, f, quantity, accountValue(f, quantity, price))
+		} else {
+			lot := Lot{Ticker: ticker, FillID: f.ID, Acquired: f.FilledAt, Quantity: quantity, Cost: accountValue(f, quantity, price)}
+			p.acquire(h, lot, quantity.Mul(price))
+		}
+	}
+}
+
+// Method returns the cost basis method of the portfolio
+func (p *Portfolio) Method() Method {
+	return p.method
+}
+
+// Holding returns the holding of ticker, including closed holdings with
+// realized P&L
+func (p *Portfolio) Holding(ticker string) (Holding, bool) {
+	h, ok := p.holdings[ticker]
+	if !ok {
+		return Holding{}, false
+	}
+	return h.snapshot(ticker), true
+}
+
+// Holdings returns the open holdings sorted by ticker
+func (p *Portfolio) Holdings() []Holding {
+	var holdings []Holding
+	for ticker, h := range p.holdings {
+		if snapshot := h.snapshot(ticker); !snapshot.Quantity.IsZero() {
+			holdings = append(holdings, snapshot)
+		}
+	}
+	sort.Slice(holdings, func(i, j int) bool {
+		return holdings[i].Ticker < holdings[j].Ticker
+	})
+	return holdings
+}
+
+// Lots returns the open lots of every holding, oldest first
+func (p *Portfolio) Lots() []Lot {
+	var lots []Lot
+	for _, h := range p.Holdings() {
+		lots = append(lots, h.Lots...)
+	}
+	sort.SliceStable(lots, func(i, j int) bool {
+		return lots[i].Acquired.Before(lots[j].Acquired)
+	})
+	return lots
+}
+
+// Disposals returns every disposal in the order applied
+func (p *Portfolio) Disposals() []Disposal {
+	return append([]Disposal(nil), p.disposals...)
+}
+
+// RealizedPL returns the total realized profit or loss. It assumes a single
+// account currency.
+func (p *Portfolio) RealizedPL() trading212.Decimal {
+	var total trading212.Decimal
+	for _, h := range p.holdings {
+		total = total.Add(h.realized)
+	}
+	return total
+}
+
+// Warnings returns problems found while replaying, such as selling more
+// shares than the fills account for
+func (p *Portfolio) Warnings() []string {
+	return append([]string(nil), p.warnings...)
+}
+
+func (p *Portfolio) warn(format string, args ...interface{}) {
+	p.warnings = append(p.warnings, fmt.Sprintf(format, args...))
+}
+
+func (p *Portfolio) acquire(h *holding, lot Lot, instrumentCost trading212.Decimal) {
+	h.instrumentCost = h.instrumentCost.Add(instrumentCost)
+	if p.method == MethodAverage && len(h.lots) > 0 {
+		h.lots[0].Quantity = h.lots[0].Quantity.Add(lot.Quantity)
+		h.lots[0].Cost = h.lots[0].Cost.Add(lot.Cost)
+		return
+	}
+	h.lots = append(h.lots, lot)
+}
+
+func (p *Portfolio) dispose(ticker string, h *holding, f trading212.Fill, quantity, proceeds trading212.Decimal) {
+	matched := p.take(ticker, h, quantity)
+	var cost trading212.Decimal
+	for _, lot := range matched {
+		cost = cost.Add(lot.Cost)
+	}
+
+	d := Disposal{
+		Ticker:     ticker,
+		FillID:     f.ID,
+		Date:       f.FilledAt,
+		Quantity:   quantity,
+		Proceeds:   proceeds,
+		Cost:       cost,
+		RealizedPL: proceeds.Sub(cost),
+		Lots:       matched,
+	}
+	h.realized = h.realized.Add(d.RealizedPL)
+	p.disposals = append(p.disposals, d)
+}
+
+// take removes quantity from the oldest lots and returns the portions
+// removed. Shares beyond the holding are reported as a warning.
+func (p *Portfolio) take(ticker string, h *holding, quantity trading212.Decimal) []Lot {
+	held := h.quantity()
+	if quantity.Cmp(held) > 0 {
+		p.warn("%s: removing %s shares but only %s are held", ticker, quantity, held)
+		quantity = held
+	}
+	if quantity.IsZero() {
+		return nil
+	}
+	if quantity.Equal(held) {
+		h.instrumentCost = trading212.Decimal{}
+	} else {
+		h.instrumentCost = h.instrumentCost.Sub(decimals.Prorate(h.instrumentCost, quantity, held))
+	}
+
+	var matched []Lot
+	remaining := quantity
+	for len(h.lots) > 0 && remaining.Sign() > 0 {
+		lot := &h.lots[0]
+		if lot.Quantity.Cmp(remaining) <= 0 {
+			matched = append(matched, *lot)
+			remaining = remaining.Sub(lot.Quantity)
+			h.lots = h.lots[1:]
+			continue
+		}
+
+		portion := *lot
+		portion.Quantity = remaining
+		portion.Cost = decimals.Prorate(lot.Cost, remaining, lot.Quantity)
+		lot.Quantity = lot.Quantity.Sub(remaining)
+		lot.Cost = lot.Cost.Sub(portion.Cost)
+		matched = append(matched, portion)
+		remaining = trading212.Decimal{}
+	}
+	if len(h.lots) == 0 {
+		h.lots = nil
+	}
+	return matched
+}
+
+// splitClose handles the disposal side of a stock split
+func (p *Portfolio) splitClose(ticker string, h *holding, quantity trading212.Decimal) {
+	held := h.quantity()
+	switch {
+	case held.IsZero():
+		p.warn("%s: stock split closes %s shares but none are held", ticker, quantity)
+	case quantity.Cmp(held) >= 0:
+		h.pending, h.lots = h.lots, nil
+		h.pendingInstrumentCost, h.instrumentCost = h.instrumentCost, trading212.Decimal{}
+	default:
+		h.lots = rescale(h.lots, held.Sub(quantity))
+	}
+}
+
+// splitOpen handles the acquisition side of a stock split
+func (p *Portfolio) splitOpen(ticker string, h *holding, quantity trading212.Decimal, f trading212.Fill) {
+	if len(h.pending) > 0 {
+		h.lots = append(h.lots, rescale(h.pending, quantity)...)
+		h.instrumentCost = h.instrumentCost.Add(h.pendingInstrumentCost)
+		h.pending, h.pendingInstrumentCost = nil, trading212.Decimal{}
+		return
+	}
+
+	held := h.quantity()
+	if held.IsZero() {
+		p.warn("%s: stock split opens %s shares but none are held", ticker, quantity)
+		p.acquire(h, Lot{Ticker: ticker, FillID: f.ID, Acquired: f.FilledAt, Quantity: quantity}, trading212.Decimal{})
+		return
+	}
+	h.lots = rescale(h.lots, held.Add(quantity))
+}
+
+func (p *Portfolio) restorePending(h *holding) {
+	h.lots = append(h.pending, h.lots...)
+	h.instrumentCost = h.instrumentCost.Add(h.pendingInstrumentCost)
+	h.pending, h.pendingInstrumentCost = nil, trading212.Decimal{}
+}
+
+func (h *holding) quantity() trading212.Decimal {
+	var total trading212.Decimal
+	for _, lot := range h.lots {
+		total = total.Add(lot.Quantity)
+	}
+	return total
+}
+
+// snapshot returns the holding as seen from outside; lots closed by a stock
+// split that has not reopened yet are still counted
+func (h *holding) snapshot(ticker string) Holding {
+	lots := append(append([]Lot(nil), h.pending...), h.lots...)
+	out := Holding{Ticker: ticker, Currency: h.currency, RealizedPL: h.realized, Lots: lots}
+	for _, lot := range lots {
+		out.Quantity = out.Quantity.Add(lot.Quantity)
+		out.Cost = out.Cost.Add(lot.Cost)
+	}
+	out.AveragePrice = decimals.Ratio(h.instrumentCost.Add(h.pendingInstrumentCost), out.Quantity)
+	return out
+}
+
+// rescale returns lots with quantities scaled to total and costs unchanged
+func rescale(lots []Lot, total trading212.Decimal) []Lot {
+	var old trading212.Decimal
+	for _, lot := range lots {
+		old = old.Add(lot.Quantity)
+	}
+
+	scaled := make([]Lot, len(lots))
+	var assigned trading212.Decimal
+	for i, lot := range lots {
+		scaled[i] = lot
+		if i == len(lots)-1 {
+			scaled[i].Quantity = total.Sub(assigned)
+			break
+		}
+		scaled[i].Quantity = decimals.Prorate(total, lot.Quantity, old)
+		assigned = assigned.Add(scaled[i].Quantity)
+	}
+	return scaled
+}
+
+// isDisposal reports whether a fill reduces the holding
+func isDisposal(order trading212.HistoricalOrder) bool {
+	return order.Order.Side == trading212.OrderSideSell || order.Fill.Quantity < 0
+}
+
+// accountValue returns the absolute value of a fill in account currency:
+// the wallet net value, or quantity × price × FX rate when that is missing
+func accountValue(f trading212.Fill, quantity, price trading212.Decimal) trading212.Decimal {
+	if f.WalletImpact.NetValue != 0 {
+		return trading212.DecimalFromFloat(f.WalletImpact.NetValue).Abs()
+	}
+	value := quantity.Mul(price)
+	if f.WalletImpact.FxRate != 0 {
+		value = value.Mul(trading212.DecimalFromFloat(f.WalletImpact.FxRate))
+	}
+	return value
+}
@@ -0,0 +1,173 @@
+package portfolio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/internal/fixtures"
+)
+
+var day = time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+func fill(id int64, ticker string, days int, fillType trading212.FillType, quantity, price, netValue float64) trading212.HistoricalOrder {
+	order := fixtures.Fill(id, ticker, day.AddDate(0, 0, days), fillType, quantity, price)
+	order.Fill.WalletImpact.NetValue = netValue
+	return order
+}
+
+func dec(s string) trading212.Decimal {
+	return trading212.MustParseDecimal(s)
+}
+
+var assertDecimal = fixtures.AssertDecimal
+
+func trades() []trading212.HistoricalOrder {
+	return []trading212.HistoricalOrder{
+		fill(3, "AAPL", 10, trading212.FillTypeTrade, -15, 14, 210),
+		fill(1, "AAPL", 0, trading212.FillTypeTrade, 10, 10, 100),
+		fill(2, "AAPL", 5, trading212.FillTypeTrade, 10, 12, 120),
+	}
+}
+
+func TestReplayFIFO(t *testing.T) {
+	p, err := Replay(trades(), MethodFIFO)
+	require.NoError(t, err)
+
+	disposals := p.Disposals()
+	require.Len(t, disposals, 1)
+	assertDecimal(t, "160", disposals[0].Cost)
+	assertDecimal(t, "50", disposals[0].RealizedPL)
+	require.Len(t, disposals[0].Lots, 2)
+	assert.Equal(t, int64(1), disposals[0].Lots[0].FillID)
+	assertDecimal(t, "5", disposals[0].Lots[1].Quantity)
+
+	h, ok := p.Holding("AAPL")
+	require.True(t, ok)
+	assertDecimal(t, "5", h.Quantity)
+	assertDecimal(t, "60", h.Cost)
+	assertDecimal(t, "11", h.AveragePrice)
+	assertDecimal(t, "50", h.RealizedPL)
+	require.Len(t, h.Lots, 1)
+	assert.Equal(t, int64(2), h.Lots[0].FillID)
+	assertDecimal(t, "12", h.Lots[0].UnitCost())
+	assert.Empty(t, p.Warnings())
+}
+
+func TestReplayAverage(t *testing.T) {
+	p, err := Replay(trades(), MethodAverage)
+	require.NoError(t, err)
+
+	assertDecimal(t, "165", p.Disposals()[0].Cost)
+	assertDecimal(t, "45", p.RealizedPL())
+
+	holdings := p.Holdings()
+	require.Len(t, holdings, 1)
+	assertDecimal(t, "55", holdings[0].Cost)
+	assertDecimal(t, "11", holdings[0].AverageCost())
+	assertDecimal(t, "11", holdings[0].AveragePrice)
+	require.Len(t, holdings[0].Lots, 1)
+}
+
+func TestReplayStockSplit(t *testing.T) {
+	orders := append(trades(),
+		fill(4, "AAPL", 20, trading212.FillTypeStockSplit, -5, 12, 0),
+		fill(5, "AAPL", 20, trading212.FillTypeStockSplit, 20, 3, 0),
+	)
+	p, err := Replay(orders, MethodFIFO)
+	require.NoError(t, err)
+
+	h, _ := p.Holding("AAPL")
+	assertDecimal(t, "20", h.Quantity)
+	assertDecimal(t, "60", h.Cost)
+	assertDecimal(t, "2.75", h.AveragePrice)
+	assert.Len(t, p.Disposals(), 1)
+	assert.Empty(t, p.Warnings())
+}
+
+func TestReplayCorporateActions(t *testing.T) {
+	orders := []trading212.HistoricalOrder{
+		fill(1, "VOD", 0, trading212.FillTypeTrade, 10, 1, 10),
+		fill(2, "VOD", 1, trading212.FillTypeStockSplit, 10, 0, 0),
+		fill(3, "VOD", 2, trading212.FillTypeStockDistribution, 5, 0, 0),
+		fill(4, "VOD", 3, trading212.FillTypeFOP, 5, 2, 0),
+		fill(5, "VOD", 4, trading212.FillTypeFOP, -10, 2, 0),
+		fill(6, "VOD", 5, trading212.FillTypeTrade, -20, 1, 20),
+	}
+	p, err := Replay(orders, MethodFIFO)
+	require.NoError(t, err)
+
+	// The split keeps the cost of 10 over 20 shares; the FOP transfer out
+	// removes half of them without a disposal
+	disposals := p.Disposals()
+	require.Len(t, disposals, 1)
+	assertDecimal(t, "15", disposals[0].Cost)
+	assertDecimal(t, "5", p.RealizedPL())
+	assert.Empty(t, p.Holdings())
+	assert.Empty(t, p.Warnings())
+}
+
+func TestReplayOversellWarns(t *testing.T) {
+	p, err := Replay([]trading212.HistoricalOrder{
+		fill(1, "TSLA", 0, trading212.FillTypeTrade, 1, 100, 100),
+		fill(2, "TSLA", 1, trading212.FillTypeTrade, -2, 150, 300),
+		{Order: trading212.Order{ID: 3, Ticker: "TSLA"}},
+	}, MethodAverage)
+	require.NoError(t, err)
+
+	assertDecimal(t, "200", p.RealizedPL())
+	assert.Empty(t, p.Holdings())
+	require.Len(t, p.Warnings(), 1)
+	assert.Contains(t, p.Warnings()[0], "TSLA")
+}
+
+func TestReplayFXValue(t *testing.T) {
+	order := fill(1, "MSFT", 0, trading212.FillTypeTrade, 2, 100, 0)
+	order.Fill.WalletImpact.FxRate = 0.8
+	p, err := Replay([]trading212.HistoricalOrder{order}, MethodFIFO)
+	require.NoError(t, err)
+
+	h, _ := p.Holding("MSFT")
+	assertDecimal(t, "160", h.Cost)
+	assertDecimal(t, "100", h.AveragePrice)
+}
+
+func TestParseMethod(t *testing.T) {
+	m, err := ParseMethod("fifo")
+	require.NoError(t, err)
+	assert.Equal(t, MethodFIFO, m)
+
+	_, err = ParseMethod("lifo")
+	assert.ErrorIs(t, err, ErrUnknownMethod)
+	_, err = New("lifo")
+	assert.ErrorIs(t, err, ErrUnknownMethod)
+}
+
+func TestReconcile(t *testing.T) {
+	p, err := Replay(append(trades(), fill(4, "TSLA", 1, trading212.FillTypeTrade, 1, 200, 200)), MethodFIFO)
+	require.NoError(t, err)
+
+	discrepancies := p.Reconcile([]trading212.Position{
+		{Ticker: "AAPL", Quantity: 5, AveragePrice: 11.005},
+		{Ticker: "MSFT", Quantity: 3, AveragePrice: 300},
+	}, nil)
+	require.Len(t, discrepancies, 2)
+	assert.Equal(t, "MSFT", discrepancies[0].Ticker)
+	assert.Equal(t, FieldQuantity, discrepancies[0].Field)
+	assertDecimal(t, "3", discrepancies[0].Position)
+	assertDecimal(t, "0", discrepancies[0].Computed)
+	assert.Equal(t, "TSLA", discrepancies[1].Ticker)
+	assertDecimal(t, "1", discrepancies[1].Computed)
+
+	discrepancies = p.Reconcile([]trading212.Position{
+		{Ticker: "AAPL", Quantity: 5, AveragePrice: 12},
+		{Ticker: "TSLA", Quantity: 1, AveragePrice: 200},
+	}, nil)
+	require.Len(t, discrepancies, 1)
+	assert.Equal(t, FieldAveragePrice, discrepancies[0].Field)
+	assertDecimal(t, "12", discrepancies[0].Position)
+	assertDecimal(t, "11", discrepancies[0].Computed)
+}
@@ -0,0 +1,106 @@
+package portfolio
+
+import (
+	"sort"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/internal/decimals"
+)
+
+// Reconciled fields
+const (
+	FieldQuantity     = "quantity"
+	FieldAveragePrice = "averagePrice"
+)
+
+// Discrepancy represents a difference between the replayed fills and the
+// positions endpoint
+type Discrepancy struct {
+	Ticker string `json:"ticker"`
+	// Field is FieldQuantity or FieldAveragePrice
+	Field string `json:"field"`
+	// Position is the value reported by the positions endpoint
+	Position trading212.Decimal `json:"position"`
+	// Computed is the value derived from the fills
+	Computed trading212.Decimal `json:"computed"`
+}
+
+// ReconcileOptions represents tolerances for Reconcile
+type ReconcileOptions struct {
+	// QuantityTolerance is the absolute quantity difference ignored,
+	// 0.000001 by default
+	QuantityTolerance trading212.Decimal
+	// PriceTolerance is the relative average price difference ignored,
+	// 0.001 (0.1%) by default
+	PriceTolerance trading212.Decimal
+}
+
+// Reconcile compares the open holdings with positions and returns the
+// differences sorted by ticker. Average prices are only compared when the
+// quantities agree.
+func (p *Portfolio) Reconcile(positions []trading212.Position, opts *ReconcileOptions) []Discrepancy {
+	quantityTolerance := trading212.MustParseDecimal("0.000001")
+	priceTolerance := trading212.MustParseDecimal("0.001")
+	if opts != nil {
+		if !opts.QuantityTolerance.IsZero() {
+			quantityTolerance = opts.QuantityTolerance
+		}
+		if !opts.PriceTolerance.IsZero() {
+			priceTolerance = opts.PriceTolerance
+		}
+	}
+
+	holdings := make(map[string]Holding)
+	for _, h := range p.Holdings() {
+		holdings[h.Ticker] = h
+	}
+
+	var discrepancies []Discrepancy
+	seen := make(map[string]bool)
+	for _, position := range positions {
+		seen[position.Ticker] = true
+		h := holdings[position.Ticker]
+
+		quantity := trading212.DecimalFromFloat(position.Quantity)
+		if quantity.Sub(h.Quantity).Abs().Cmp(quantityTolerance) > 0 {
+			discrepancies = append(discrepancies, Discrepancy{
+				Ticker:   position.Ticker,
+				Field:    FieldQuantity,
+				Position: quantity,
+				Computed: h.Quantity,
+			})
+			continue
+		}
+
+		price := trading212.DecimalFromFloat(position.AveragePrice)
+		if price.IsZero() || h.Quantity.IsZero() {
+			continue
+		}
+		if decimals.Ratio(price.Sub(h.AveragePrice).Abs(), price).Cmp(priceTolerance) > 0 {
+			discrepancies = append(discrepancies, Discrepancy{
+				Ticker:   position.Ticker,
+				Field:    FieldAveragePrice,
+				Position: price,
+				Computed: h.AveragePrice,
+			})
+		}
+	}
+
+	for ticker, h := range holdings {
+		if !seen[ticker] && h.Quantity.Cmp(quantityTolerance) > 0 {
+			discrepancies = append(discrepancies, Discrepancy{
+				Ticker:   ticker,
+				Field:    FieldQuantity,
+				Computed: h.Quantity,
+			})
+		}
+	}
+
+	sort.Slice(discrepancies, func(i, j int) bool {
+		if discrepancies[i].Ticker != discrepancies[j].Ticker {
+			return discrepancies[i].Ticker < discrepancies[j].Ticker
+		}
+		return discrepancies[i].Field > discrepancies[j].Field
+	})
+	return discrepancies
+}
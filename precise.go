@@ -0,0 +1,194 @@
+package trading212
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// The Precise* types mirror the float64 response types with Decimal fields for
+// prices, quantities and cash, so the server's digits survive decoding. They
+// are returned by the *Precise variants of the Client methods.
+
+// PreciseAccountCash represents account cash balance information
+type PreciseAccountCash struct {
+	Free     Decimal `json:"free"`
+	Invested Decimal `json:"invested"`
+	Result   Decimal `json:"result"`
+	Total    Decimal `json:"total"`
+}
+
+// PreciseOrder represents an order
+type PreciseOrder struct {
+	CreatedAt      time.Time     `json:"createdAt"`
+	Currency       string        `json:"currency"`
+	ExtendedHours  bool          `json:"extendedHours"`
+	FilledQuantity Decimal       `json:"filledQuantity"`
+	FilledValue    Decimal       `json:"filledValue"`
+	ID             int64         `json:"id"`
+	InitiatedFrom  string        `json:"initiatedFrom"`
+	Instrument     Instrument    `json:"instrument"`
+	LimitPrice     *Decimal      `json:"limitPrice,omitempty"`
+	Quantity       Decimal       `json:"quantity"`
+	Side           OrderSide     `json:"side"`
+	Status         OrderStatus   `json:"status"`
+	StopPrice      *Decimal      `json:"stopPrice,omitempty"`
+	Strategy       OrderStrategy `json:"strategy"`
+	Ticker         string        `json:"ticker"`
+	TimeInForce    TimeValidity  `json:"timeInForce"`
+	Type           OrderType     `json:"type"`
+	Value          Decimal       `json:"value"`
+}
+
+// PrecisePosition represents a position
+type PrecisePosition struct {
+	AveragePrice    Decimal   `json:"averagePrice"`
+	CurrentPrice    Decimal   `json:"currentPrice"`
+	Frontend        string    `json:"frontend"`
+	FxPpl           Decimal   `json:"fxPpl"`
+	InitialFillDate time.Time `json:"initialFillDate"`
+	MaxBuy          Decimal   `json:"maxBuy"`
+	MaxSell         Decimal   `json:"maxSell"`
+	PieQuantity     Decimal   `json:"pieQuantity"`
+	Ppl             Decimal   `json:"ppl"`
+	Quantity        Decimal   `json:"quantity"`
+	Ticker          string    `json:"ticker"`
+}
+
+// PreciseHistoricalOrder represents a historical order
+type PreciseHistoricalOrder struct {
+	Fill  PreciseFill  `json:"fill"`
+	Order PreciseOrder `json:"order"`
+}
+
+// PreciseFill represents order fill information
+type PreciseFill struct {
+	FilledAt      time.Time               `json:"filledAt"`
+	ID            int64                   `json:"id"`
+	Price         Decimal                 `json:"price"`
+	Quantity      Decimal                 `json:"quantity"`
+	TradingMethod FillTradingMethod       `json:"tradingMethod"`
+	Type          FillType                `json:"type"`
+	WalletImpact  PreciseFillWalletImpact `json:"walletImpact"`
+}
+
+// PreciseFillWalletImpact represents fill wallet impact
+type PreciseFillWalletImpact struct {
+	Currency           string       `json:"currency"`
+	FxRate             Decimal      `json:"fxRate"`
+	NetValue           Decimal      `json:"netValue"`
+	RealisedProfitLoss Decimal      `json:"realisedProfitLoss"`
+	Taxes              []PreciseTax `json:"taxes"`
+}
+
+// PreciseTax represents tax information
+type PreciseTax struct {
+	ChargedAt time.Time `json:"chargedAt"`
+	Currency  string    `json:"currency"`
+	Name      string    `json:"name"`
+	Quantity  Decimal   `json:"quantity"`
+}
+
+// Value returns the fill price times the filled quantity
+func (f PreciseFill) Value() Decimal {
+	return f.Price.Mul(f.Quantity)
+}
+
+// Net returns the net value of the fill in the account currency
+func (w PreciseFillWalletImpact) Net() Money {
+	return Money{Amount: w.NetValue, Currency: w.Currency}
+}
+
+// TotalTaxes sums the taxes and fees charged on the fill
+func (w PreciseFillWalletImpact) TotalTaxes() (Money, error) {
+	taxes := make([]Money, len(w.Taxes))
+	for i, tax := range w.Taxes {
+		taxes[i] = tax.Money()
+	}
+	return SumMoney(taxes...)
+}
+
+// Money returns the charged amount with its currency
+func (t PreciseTax) Money() Money {
+	return Money{Amount: t.Quantity, Currency: t.Currency}
+}
+
+// GetAccountCashPrecise retrieves account cash balance information with exact amounts
+func (c *Client) GetAccountCashPrecise(ctx context.Context) (*PreciseAccountCash, error) {
+	resp, err := c.makeRequest(ctx, http.MethodGet, "/api/v0/equity/account/cash", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var cash PreciseAccountCash
+	if err := c.handleResponse(resp, &cash); err != nil {
+		return nil, err
+	}
+
+	return &cash, nil
+}
+
+// GetOrdersPrecise retrieves all active orders with exact quantities and prices
+func (c *Client) GetOrdersPrecise(ctx context.Context) ([]PreciseOrder, error) {
+	resp, err := c.makeRequest(ctx, http.MethodGet, "/api/v0/equity/orders", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []PreciseOrder
+	if err := c.handleResponse(resp, &orders); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// GetPositionsPrecise retrieves all open positions with exact quantities and prices
+func (c *Client) GetPositionsPrecise(ctx context.Context, opts *GetPositionsOptions) ([]PrecisePosition, error) {
+	path := "/api/v0/equity/portfolio"
+
+	if opts != nil {
+		params := map[string]interface{}{
+			"ticker": opts.Ticker,
+		}
+		path += buildQuery(params)
+	}
+
+	resp, err := c.makeRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var positions []PrecisePosition
+	if err := c.handleResponse(resp, &positions); err != nil {
+		return nil, err
+	}
+
+	return positions, nil
+}
+
+// GetHistoricalOrdersPrecise retrieves historical orders with exact fill amounts
+func (c *Client) GetHistoricalOrdersPrecise(ctx context.Context, opts *HistoryOrdersOptions) (*PaginatedResponse[PreciseHistoricalOrder], error) {
+	path := "/api/v0/equity/history/orders"
+
+	if opts != nil {
+		params := map[string]interface{}{
+			"cursor": opts.Cursor,
+			"ticker": opts.Ticker,
+			"limit":  opts.Limit,
+		}
+		path += buildQuery(params)
+	}
+
+	resp, err := c.makeRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result PaginatedResponse[PreciseHistoricalOrder]
+	if err := c.handleResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
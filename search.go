@@ -0,0 +1,232 @@
+package trading212
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// SearchResult represents an instrument matched by InstrumentCatalog.Search
+type SearchResult struct {
+	Instrument TradableInstrument
+	// Score ranks the match between 0 and 1, higher is better
+	Score float64
+	// Field is the JSON name of the field that produced the best match
+	Field string
+}
+
+// SearchOptions represents options for InstrumentCatalog.Search
+type SearchOptions struct {
+	// Limit caps the number of results (default 20)
+	Limit int
+	// MinScore drops weaker matches (default 0.3)
+	MinScore float64
+	// Type restricts results to an instrument type
+	Type InstrumentType
+}
+
+// searchField describes how strongly a match in a field counts
+type searchField struct {
+	name   string
+	weight float64
+	value  func(*TradableInstrument) string
+}
+
+var searchFields = []searchField{
+	{"ticker", 1.0, func(i *TradableInstrument) string { return i.Ticker }},
+	{"shortName", 1.0, func(i *TradableInstrument) string { return i.ShortName }},
+	{"isin", 1.0, func(i *TradableInstrument) string { return i.ISIN }},
+	{"name", 0.9, func(i *TradableInstrument) string { return i.Name }},
+}
+
+// Search ranks instruments against a free-text query such as "apple" or
+// "vanguard s&p", matching Ticker, ShortName, ISIN and Name with prefix
+// matching and typo tolerance. Results are ordered best first.
+func (c *InstrumentCatalog) Search(query string, opts *SearchOptions) []SearchResult {
+	o := SearchOptions{Limit: 20, MinScore: 0.3}
+	if opts != nil {
+		if opts.Limit > 0 {
+			o.Limit = opts.Limit
+		}
+		if opts.MinScore > 0 {
+			o.MinScore = opts.MinScore
+		}
+		o.Type = opts.Type
+	}
+
+	queryTokens := searchTokens(query)
+	if len(queryTokens) == 0 {
+		return nil
+	}
+	whole := strings.Join(queryTokens, "")
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var results []SearchResult
+	for i := range c.instruments {
+		instrument := &c.instruments[i]
+		if o.Type != "" && instrument.Type != o.Type {
+			continue
+		}
+
+		best := SearchResult{}
+		for _, field := range searchFields {
+			score := scoreField(queryTokens, whole, field.value(instrument)) * field.weight
+			if score > best.Score {
+				best = SearchResult{Score: score, Field: field.name}
+			}
+		}
+		if best.Score >= o.MinScore {
+			best.Instrument = *instrument
+			results = append(results, best)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if len(results[i].Instrument.Name) != len(results[j].Instrument.Name) {
+			return len(results[i].Instrument.Name) < len(results[j].Instrument.Name)
+		}
+		return results[i].Instrument.Ticker < results[j].Instrument.Ticker
+	})
+	if len(results) > o.Limit {
+		results = results[:o.Limit]
+	}
+	return results
+}
+
+// scoreField scores a field value against the query tokens
+func scoreField(queryTokens []string, whole, value string) float64 {
+	fieldTokens := searchTokens(value)
+	if len(fieldTokens) == 0 {
+		return 0
+	}
+
+	joined := strings.Join(fieldTokens, "")
+	switch {
+	case joined == whole:
+		return 1
+	case strings.HasPrefix(joined, whole) && len(whole) >= 2:
+		return 0.9
+	}
+
+	total := 0.0
+	for _, queryToken := range queryTokens {
+		best := 0.0
+		for _, fieldToken := range fieldTokens {
+			if score := scoreToken(queryToken, fieldToken); score > best {
+				best = score
+			}
+		}
+		if best == 0 {
+			return 0
+		}
+		total += best
+	}
+
+	// Prefer fields where the query covers more of the value
+	coverage := float64(len(queryTokens)) / float64(len(fieldTokens))
+	if coverage > 1 {
+		coverage = 1
+	}
+	return total / float64(len(queryTokens)) * (0.85 + 0.15*coverage)
+}
+
+// scoreToken scores a single query token against a single field token
+func scoreToken(query, token string) float64 {
+	switch {
+	case query == token:
+		return 1
+	case strings.HasPrefix(token, query):
+		return 0.75 + 0.2*float64(len(query))/float64(len(token))
+	}
+
+	allowed := 0
+	switch n := len([]rune(query)); {
+	case n >= 8:
+		allowed = 2
+	case n >= 4:
+		allowed = 1
+	}
+	if allowed == 0 {
+		return 0
+	}
+
+	// Compare against the token and against its prefix of the same length,
+	// so a misspelt partial word still matches
+	distance := editDistance(query, token)
+	if r := []rune(token); len(r) > len([]rune(query)) {
+		if d := editDistance(query, string(r[:len([]rune(query))])); d < distance {
+			distance = d
+		}
+	}
+	if distance > allowed {
+		return 0
+	}
+	return 0.7 - 0.15*float64(distance-1)
+}
+
+// searchTokens lower-cases s and splits it into alphanumeric words, dropping
+// punctuation inside words so that "S&P" becomes "sp"
+func searchTokens(s string) []string {
+	var tokens []string
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			current.WriteRune(r)
+		case r == '&' || r == '\'' || r == '.':
+		default:
+			flush()
+		}
+	}
+	flush()
+	return tokens
+}
+
+// editDistance returns the Damerau-Levenshtein (optimal string alignment)
+// distance between a and b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = minInt(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(rb)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
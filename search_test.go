@@ -0,0 +1,66 @@
+package trading212
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func searchCatalog() *InstrumentCatalog {
+	return NewInstrumentCatalogFrom([]TradableInstrument{
+		{Ticker: "AAPL_US_EQ", ShortName: "AAPL", Name: "Apple", ISIN: "US0378331005", Type: InstrumentTypeStock},
+		{Ticker: "APLE_US_EQ", ShortName: "APLE", Name: "Apple Hospitality REIT", ISIN: "US03784Y2000", Type: InstrumentTypeStock},
+		{Ticker: "VUSAl_EQ", ShortName: "VUSA", Name: "Vanguard S&P 500 (Dist)", ISIN: "IE00B3XXRP09", Type: InstrumentTypeETF},
+		{Ticker: "VWRLl_EQ", ShortName: "VWRL", Name: "Vanguard FTSE All-World (Dist)", ISIN: "IE00B3RBWM25", Type: InstrumentTypeETF},
+		{Ticker: "MSFT_US_EQ", ShortName: "MSFT", Name: "Microsoft", ISIN: "US5949181045", Type: InstrumentTypeStock},
+	})
+}
+
+func TestInstrumentCatalog_Search(t *testing.T) {
+	catalog := searchCatalog()
+
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"apple", "AAPL_US_EQ"},
+		{"AAPL", "AAPL_US_EQ"},
+		{"aapl_us_eq", "AAPL_US_EQ"},
+		{"vanguard s&p", "VUSAl_EQ"},
+		{"vangaurd all world", "VWRLl_EQ"},
+		{"micro", "MSFT_US_EQ"},
+		{"microsfot", "MSFT_US_EQ"},
+		{"IE00B3XXRP09", "VUSAl_EQ"},
+	}
+	for _, tt := range tests {
+		results := catalog.Search(tt.query, nil)
+		require.NotEmpty(t, results, tt.query)
+		assert.Equal(t, tt.expected, results[0].Instrument.Ticker, tt.query)
+	}
+
+	assert.Empty(t, catalog.Search("zzzz", nil))
+	assert.Empty(t, catalog.Search("  ", nil))
+}
+
+func TestInstrumentCatalog_SearchOptions(t *testing.T) {
+	catalog := searchCatalog()
+
+	results := catalog.Search("vanguard", &SearchOptions{Limit: 1})
+	assert.Len(t, results, 1)
+
+	results = catalog.Search("apple", &SearchOptions{Type: InstrumentTypeETF})
+	assert.Empty(t, results)
+
+	results = catalog.Search("apple", nil)
+	require.Len(t, results, 2)
+	assert.Greater(t, results[0].Score, results[1].Score)
+	assert.Equal(t, "name", results[0].Field)
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("apple", "apple"))
+	assert.Equal(t, 1, editDistance("aple", "apple"))
+	assert.Equal(t, 1, editDistance("vangaurd", "vanguard"))
+	assert.Equal(t, 3, editDistance("kitten", "sitting"))
+}
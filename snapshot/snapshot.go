@@ -0,0 +1,252 @@
+// Package snapshot records the account value and positions at regular
+// intervals, since the API only reports the current valuation, and turns
+// the recorded snapshots into equity curves and per-position series.
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/performance"
+)
+
+// MinInterval is the shortest recording interval. The cash and positions
+// endpoints allow a request every 2 and 1 seconds, so recording no more
+// often than this leaves room for other clients of the same key.
+const MinInterval = 10 * time.Second
+
+// Source represents the endpoints a snapshot is built from;
+// *trading212.Client implements it
+type Source interface {
+	GetAccountInfo(ctx context.Context) (*trading212.AccountInfo, error)
+	GetAccountCash(ctx context.Context) (*trading212.AccountCash, error)
+	GetPositions(ctx context.Context, opts *trading212.GetPositionsOptions) ([]trading212.Position, error)
+}
+
+// Snapshot represents the account at a point in time. Position prices and
+// values are in instrument currency; cash and P&L in account currency.
+type Snapshot struct {
+	Time      time.Time              `json:"time"`
+	Currency  string                 `json:"currency"`
+	Cash      trading212.AccountCash `json:"cash"`
+	Positions []trading212.Position  `json:"positions"`
+}
+
+// Position returns the position in ticker
+func (s Snapshot) Position(ticker string) (trading212.Position, bool) {
+	for _, p := range s.Positions {
+		if p.Ticker == ticker {
+			return p, true
+		}
+	}
+	return trading212.Position{}, false
+}
+
+// Options represents options for a Recorder
+type Options struct {
+	// Interval is the time between snapshots (default 15m, at least
+	// MinInterval)
+	Interval time.Duration
+	// RetryDelay is how long to wait after a 429 response (default 10s)
+	RetryDelay time.Duration
+	// OnError is called when a snapshot fails; Run keeps going
+	OnError func(error)
+	// Now returns the snapshot time (default time.Now)
+	Now func() time.Time
+}
+
+// Recorder takes snapshots from a source and appends them to a store
+type Recorder struct {
+	source   Source
+	store    Store
+	opts     Options
+	currency string
+}
+
+// NewRecorder creates a recorder; opts may be nil
+func NewRecorder(source Source, store Store, opts *Options) *Recorder {
+	r := &Recorder{source: source, store: store}
+	if opts != nil {
+		r.opts = *opts
+	}
+	if r.opts.Interval <= 0 {
+		r.opts.Interval = 15 * time.Minute
+	}
+	if r.opts.Interval < MinInterval {
+		r.opts.Interval = MinInterval
+	}
+	if r.opts.RetryDelay <= 0 {
+		r.opts.RetryDelay = 10 * time.Second
+	}
+	if r.opts.Now == nil {
+		r.opts.Now = time.Now
+	}
+	return r
+}
+
+// Record takes one snapshot and stores it
+func (r *Recorder) Record(ctx context.Context) (Snapshot, error) {
+	if r.currency == "" {
+		info, err := retry(ctx, r.opts.RetryDelay, r.source.GetAccountInfo)
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("failed to get account info: %w", err)
+		}
+		r.currency = info.Currency
+	}
+
+	cash, err := retry(ctx, r.opts.RetryDelay, r.source.GetAccountCash)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("failed to get account cash: %w", err)
+	}
+	positions, err := retry(ctx, r.opts.RetryDelay, func(ctx context.Context) ([]trading212.Position, error) {
+		return r.source.GetPositions(ctx, nil)
+	})
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("failed to get positions: %w", err)
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		return positions[i].Ticker < positions[j].Ticker
+	})
+
+	s := Snapshot{Time: r.opts.Now().UTC(), Currency: r.currency, Cash: *cash, Positions: positions}
+	if err := r.store.Append(s); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to store snapshot: %w", err)
+	}
+	return s, nil
+}
+
+// Run takes a snapshot immediately and then every Interval until ctx is
+// done, which is the only error it returns
+func (r *Recorder) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.opts.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := r.Record(ctx); err != nil && ctx.Err() == nil && r.opts.OnError != nil {
+			r.opts.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// retry calls fetch until it succeeds or fails with something other than
+// a 429 response
+func retry[T any](ctx context.Context, delay time.Duration, fetch func(context.Context) (T, error)) (T, error) {
+	for {
+		v, err := fetch(ctx)
+		var apiErr *trading212.APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+			return v, err
+		}
+		select {
+		case <-ctx.Done():
+			return v, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// EquityPoint represents the account value in a snapshot
+type EquityPoint struct {
+	Time     time.Time `json:"time"`
+	Total    float64   `json:"total"`
+	Free     float64   `json:"free"`
+	Invested float64   `json:"invested"`
+	Result   float64   `json:"result"`
+}
+
+// EquityCurve returns the account value of each snapshot, oldest first
+func EquityCurve(snapshots []Snapshot) []EquityPoint {
+	points := make([]EquityPoint, 0, len(snapshots))
+	for _, s := range sorted(snapshots) {
+		points = append(points, EquityPoint{
+			Time:     s.Time,
+			Total:    s.Cash.Total,
+			Free:     s.Cash.Free,
+			Invested: s.Cash.Invested,
+			Result:   s.Cash.Result,
+		})
+	}
+	return points
+}
+
+// PositionPoint represents a position in a snapshot
+type PositionPoint struct {
+	Time         time.Time `json:"time"`
+	Quantity     float64   `json:"quantity"`
+	Price        float64   `json:"price"`
+	AveragePrice float64   `json:"averagePrice"`
+	// Value is Quantity × Price in instrument currency
+	Value float64 `json:"value"`
+	// Ppl is the unrealized P&L in account currency
+	Ppl   float64 `json:"ppl"`
+	FxPpl float64 `json:"fxPpl"`
+}
+
+// PositionSeries returns the position in ticker of each snapshot that
+// holds it, oldest first
+func PositionSeries(snapshots []Snapshot, ticker string) []PositionPoint {
+	var points []PositionPoint
+	for _, s := range sorted(snapshots) {
+		p, ok := s.Position(ticker)
+		if !ok {
+			continue
+		}
+		points = append(points, PositionPoint{
+			Time:         s.Time,
+			Quantity:     p.Quantity,
+			Price:        p.CurrentPrice,
+			AveragePrice: p.AveragePrice,
+			Value:        p.Quantity * p.CurrentPrice,
+			Ppl:          p.Ppl,
+			FxPpl:        p.FxPpl,
+		})
+	}
+	return points
+}
+
+// Tickers returns every ticker held in any snapshot, sorted
+func Tickers(snapshots []Snapshot) []string {
+	seen := make(map[string]bool)
+	var tickers []string
+	for _, s := range snapshots {
+		for _, p := range s.Positions {
+			if !seen[p.Ticker] {
+				seen[p.Ticker] = true
+				tickers = append(tickers, p.Ticker)
+			}
+		}
+	}
+	sort.Strings(tickers)
+	return tickers
+}
+
+// Valuations returns the total value of each snapshot for performance
+// reporting. Holdings are left out because position values are in
+// instrument currency.
+func Valuations(snapshots []Snapshot) []performance.Valuation {
+	valuations := make([]performance.Valuation, 0, len(snapshots))
+	for _, s := range sorted(snapshots) {
+		valuations = append(valuations, performance.Valuation{
+			Time:  s.Time,
+			Value: trading212.DecimalFromFloat(s.Cash.Total),
+		})
+	}
+	return valuations
+}
+
+func sorted(snapshots []Snapshot) []Snapshot {
+	out := append([]Snapshot(nil), snapshots...)
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Time.Before(out[j].Time)
+	})
+	return out
+}
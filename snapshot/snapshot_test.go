@@ -0,0 +1,160 @@
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+)
+
+type fakeSource struct {
+	cash      trading212.AccountCash
+	positions []trading212.Position
+	limited   int
+	infoCalls int
+	fail      error
+}
+
+func (f *fakeSource) GetAccountInfo(ctx context.Context) (*trading212.AccountInfo, error) {
+	f.infoCalls++
+	return &trading212.AccountInfo{Currency: "GBP", ID: 1}, nil
+}
+
+func (f *fakeSource) GetAccountCash(ctx context.Context) (*trading212.AccountCash, error) {
+	if f.limited > 0 {
+		f.limited--
+		return nil, &trading212.APIError{StatusCode: http.StatusTooManyRequests}
+	}
+	if f.fail != nil {
+		return nil, f.fail
+	}
+	return &f.cash, nil
+}
+
+func (f *fakeSource) GetPositions(ctx context.Context, opts *trading212.GetPositionsOptions) ([]trading212.Position, error) {
+	return append([]trading212.Position(nil), f.positions...), nil
+}
+
+var start = time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+
+func clock() func() time.Time {
+	now := start
+	return func() time.Time {
+		t := now
+		now = now.Add(time.Hour)
+		return t
+	}
+}
+
+func TestRecordRetriesRateLimit(t *testing.T) {
+	source := &fakeSource{
+		cash:      trading212.AccountCash{Total: 1000, Free: 100, Invested: 850, Result: 50},
+		positions: []trading212.Position{{Ticker: "TSLA", Quantity: 2}, {Ticker: "AAPL", Quantity: 1}},
+		limited:   2,
+	}
+	store := NewMemoryStore()
+	r := NewRecorder(source, store, &Options{RetryDelay: time.Millisecond, Now: clock()})
+
+	s, err := r.Record(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "GBP", s.Currency)
+	assert.Equal(t, start, s.Time)
+	assert.Equal(t, "AAPL", s.Positions[0].Ticker)
+
+	_, err = r.Record(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, source.infoCalls)
+
+	snapshots, err := store.Load(start.Add(time.Minute), time.Time{})
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, start.Add(time.Hour), snapshots[0].Time)
+}
+
+func TestRunReportsErrorsUntilCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	source := &fakeSource{fail: errors.New("connection reset")}
+	var reported []error
+	r := NewRecorder(source, NewMemoryStore(), &Options{Interval: time.Millisecond, OnError: func(err error) {
+		reported = append(reported, err)
+		cancel()
+	}})
+
+	assert.ErrorIs(t, r.Run(ctx), context.Canceled)
+	require.Len(t, reported, 1)
+	assert.Contains(t, reported[0].Error(), "connection reset")
+	assert.Equal(t, MinInterval, r.opts.Interval)
+}
+
+func TestFileStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(dir)
+	require.NoError(t, err)
+
+	r := NewRecorder(&fakeSource{cash: trading212.AccountCash{Total: 1000}}, store, &Options{Now: clock()})
+	_, err = r.Record(context.Background())
+	require.NoError(t, err)
+
+	// A crash in the middle of a write leaves a partial line
+	f, err := os.OpenFile(filepath.Join(dir, "snapshots.jsonl"), os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"time": "2024-`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	snapshots, err := store.Load(time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Len(t, snapshots, 1)
+
+	_, err = r.Record(context.Background())
+	require.NoError(t, err)
+	store, err = NewFileStore(dir)
+	require.NoError(t, err)
+	snapshots, err = store.Load(time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, 1000.0, snapshots[1].Cash.Total)
+}
+
+func TestSeries(t *testing.T) {
+	snapshots := []Snapshot{
+		{
+			Time:      start.Add(time.Hour),
+			Cash:      trading212.AccountCash{Total: 1100},
+			Positions: []trading212.Position{{Ticker: "AAPL", Quantity: 2, CurrentPrice: 110, Ppl: 20}},
+		},
+		{
+			Time:      start,
+			Cash:      trading212.AccountCash{Total: 1000},
+			Positions: []trading212.Position{{Ticker: "AAPL", Quantity: 2, CurrentPrice: 100}, {Ticker: "MSFT", Quantity: 1}},
+		},
+	}
+
+	curve := EquityCurve(snapshots)
+	require.Len(t, curve, 2)
+	assert.Equal(t, 1000.0, curve[0].Total)
+	assert.Equal(t, 1100.0, curve[1].Total)
+
+	series := PositionSeries(snapshots, "AAPL")
+	require.Len(t, series, 2)
+	assert.Equal(t, 200.0, series[0].Value)
+	assert.Equal(t, 220.0, series[1].Value)
+	assert.Equal(t, 20.0, series[1].Ppl)
+	assert.Len(t, PositionSeries(snapshots, "MSFT"), 1)
+
+	assert.Equal(t, []string{"AAPL", "MSFT"}, Tickers(snapshots))
+
+	valuations := Valuations(snapshots)
+	require.Len(t, valuations, 2)
+	assert.Equal(t, start, valuations[0].Time)
+	assert.Equal(t, "1100", valuations[1].Value.String())
+}
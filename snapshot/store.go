@@ -0,0 +1,112 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/internal/jsonl"
+)
+
+// Store persists snapshots
+type Store interface {
+	Append(s Snapshot) error
+	// Load returns the snapshots taken from (inclusive) to (exclusive),
+	// oldest first; a zero time leaves that side open
+	Load(from, to time.Time) ([]Snapshot, error)
+}
+
+// MemoryStore is an in-memory Store
+type MemoryStore struct {
+	mu        sync.Mutex
+	snapshots []Snapshot
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Append adds a snapshot
+func (s *MemoryStore) Append(snapshot Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snapshots = append(s.snapshots, snapshot)
+	return nil
+}
+
+// Load returns the snapshots in the range
+func (s *MemoryStore) Load(from, to time.Time) ([]Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return between(s.snapshots, from, to), nil
+}
+
+// FileStore is a Store backed by an append-only JSON Lines file,
+// snapshots.jsonl, in a directory
+type FileStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewFileStore creates a store in dir, which is created if needed
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Append writes a snapshot and syncs the file to disk
+func (s *FileStore) Append(snapshot Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := jsonl.Append(s.path(), snapshot); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// Load reads the snapshots in the range. A truncated last line, left by a
+// crash during Append, is ignored and removed by the next Append.
+func (s *FileStore) Load(from, to time.Time) ([]Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var snapshots []Snapshot
+	err := jsonl.Read(s.path(), func(line int, data []byte) error {
+		var snapshot Snapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			return fmt.Errorf("failed to decode snapshot on line %d: %w", line, err)
+		}
+		snapshots = append(snapshots, snapshot)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshots: %w", err)
+	}
+	return between(snapshots, from, to), nil
+}
+
+func (s *FileStore) path() string {
+	return filepath.Join(s.dir, "snapshots.jsonl")
+}
+
+// between returns the snapshots in the range, oldest first
+func between(snapshots []Snapshot, from, to time.Time) []Snapshot {
+	var selected []Snapshot
+	for _, snapshot := range snapshots {
+		if (from.IsZero() || !snapshot.Time.Before(from)) && (to.IsZero() || snapshot.Time.Before(to)) {
+			selected = append(selected, snapshot)
+		}
+	}
+	sort.SliceStable(selected, func(i, j int) bool {
+		return selected[i].Time.Before(selected[j].Time)
+	})
+	return selected
+}
@@ -0,0 +1,6 @@
+// Package spec contains types, enum constants and endpoint skeletons
+// generated from api.yaml. It describes the API as documented and is used to
+// detect where the hand-written SDK differs from it.
+package spec
+
+//go:generate go run ../cmd/specgen -spec ../api.yaml -out zz_generated.go
@@ -0,0 +1,770 @@
+// Code generated by specgen from api.yaml; DO NOT EDIT.
+
+package spec
+
+import (
+	"context"
+	"time"
+)
+
+// AccountBucketDetailedResponse represents the AccountBucketDetailedResponse schema.
+type AccountBucketDetailedResponse struct {
+	CreationDate       time.Time                                       `json:"creationDate,omitempty"`
+	DividendCashAction AccountBucketDetailedResponseDividendCashAction `json:"dividendCashAction,omitempty"`
+	EndDate            time.Time                                       `json:"endDate,omitempty"`
+	Goal               float64                                         `json:"goal,omitempty"`
+	Icon               string                                          `json:"icon,omitempty"`
+	ID                 int64                                           `json:"id,omitempty"`
+	InitialInvestment  float64                                         `json:"initialInvestment,omitempty"`
+	InstrumentShares   map[string]float64                              `json:"instrumentShares,omitempty"`
+	Name               string                                          `json:"name,omitempty"`
+	PublicURL          string                                          `json:"publicUrl,omitempty"`
+}
+
+// AccountBucketInstrumentResult represents the AccountBucketInstrumentResult schema.
+type AccountBucketInstrumentResult struct {
+	CurrentShare  float64           `json:"currentShare,omitempty"`
+	ExpectedShare float64           `json:"expectedShare,omitempty"`
+	Issues        []InstrumentIssue `json:"issues,omitempty"`
+	OwnedQuantity float64           `json:"ownedQuantity,omitempty"`
+	Result        InvestmentResult  `json:"result,omitempty"`
+	Ticker        string            `json:"ticker,omitempty"`
+}
+
+// AccountBucketInstrumentsDetailedResponse represents the AccountBucketInstrumentsDetailedResponse schema.
+type AccountBucketInstrumentsDetailedResponse struct {
+	Instruments []AccountBucketInstrumentResult `json:"instruments,omitempty"`
+	Settings    AccountBucketDetailedResponse   `json:"settings,omitempty"`
+}
+
+// AccountBucketResultResponse represents the AccountBucketResultResponse schema.
+type AccountBucketResultResponse struct {
+	// Amount of money put into the pie in account currency
+	Cash            float64         `json:"cash,omitempty"`
+	DividendDetails DividendDetails `json:"dividendDetails,omitempty"`
+	ID              int64           `json:"id,omitempty"`
+	// Progress of the pie based on the set goal
+	Progress float64          `json:"progress,omitempty"`
+	Result   InvestmentResult `json:"result,omitempty"`
+	// Status of the pie based on the set goal
+	Status AccountBucketResultResponseStatus `json:"status,omitempty"`
+}
+
+// AccountSummary represents the AccountSummary schema.
+type AccountSummary struct {
+	Cash Cash `json:"cash,omitempty"`
+	// Primary account currency in ISO 4217 format.
+	Currency string `json:"currency,omitempty"`
+	// Primary trading account number. This is the same account ID you would see in the Trading 212 web or mobile application.
+	ID          int64       `json:"id,omitempty"`
+	Investments Investments `json:"investments,omitempty"`
+	// Investments value in your account's primary currency.
+	TotalValue float64 `json:"totalValue,omitempty"`
+}
+
+// Cash represents the Cash schema.
+type Cash struct {
+	// Funds available for investing.
+	AvailableToTrade float64 `json:"availableToTrade,omitempty"`
+	// It’s the sum of the cash inside of all pies that is not yet invested.
+	InPies float64 `json:"inPies,omitempty"`
+	// The amount of cash reserved for pending orders. This cash is not available for placing new trades.
+	ReservedForOrders float64 `json:"reservedForOrders,omitempty"`
+}
+
+// DividendDetails represents the DividendDetails schema.
+type DividendDetails struct {
+	Gained     float64 `json:"gained,omitempty"`
+	InCash     float64 `json:"inCash,omitempty"`
+	Reinvested float64 `json:"reinvested,omitempty"`
+}
+
+// DuplicateBucketRequest represents the DuplicateBucketRequest schema.
+type DuplicateBucketRequest struct {
+	Icon string `json:"icon,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// EnqueuedReportResponse represents the EnqueuedReportResponse schema.
+type EnqueuedReportResponse struct {
+	ReportID int64 `json:"reportId,omitempty"`
+}
+
+// Exchange represents the Exchange schema.
+type Exchange struct {
+	ID               int64             `json:"id,omitempty"`
+	Name             string            `json:"name,omitempty"`
+	WorkingSchedules []WorkingSchedule `json:"workingSchedules,omitempty"`
+}
+
+// Fill represents the Fill schema.
+type Fill struct {
+	FilledAt      time.Time         `json:"filledAt,omitempty"`
+	ID            int64             `json:"id,omitempty"`
+	Price         float64           `json:"price,omitempty"`
+	Quantity      float64           `json:"quantity,omitempty"`
+	TradingMethod FillTradingMethod `json:"tradingMethod,omitempty"`
+	Type          FillType          `json:"type,omitempty"`
+	WalletImpact  FillWalletImpact  `json:"walletImpact,omitempty"`
+}
+
+// FillWalletImpact represents the FillWalletImpact schema.
+type FillWalletImpact struct {
+	Currency           string  `json:"currency,omitempty"`
+	FxRate             float64 `json:"fxRate,omitempty"`
+	NetValue           float64 `json:"netValue,omitempty"`
+	RealisedProfitLoss float64 `json:"realisedProfitLoss,omitempty"`
+	Taxes              []Tax   `json:"taxes,omitempty"`
+}
+
+// HistoricalOrder represents the HistoricalOrder schema.
+type HistoricalOrder struct {
+	Fill  Fill  `json:"fill,omitempty"`
+	Order Order `json:"order,omitempty"`
+}
+
+// HistoryDividendItem represents the HistoryDividendItem schema.
+type HistoryDividendItem struct {
+	// In account's primary currency.
+	Amount       float64 `json:"amount,omitempty"`
+	AmountInEuro float64 `json:"amountInEuro,omitempty"`
+	// The account's primary currency.
+	Currency string `json:"currency,omitempty"`
+	// In instrument currency
+	GrossAmountPerShare float64                 `json:"grossAmountPerShare,omitempty"`
+	Instrument          Instrument              `json:"instrument,omitempty"`
+	PaidOn              time.Time               `json:"paidOn,omitempty"`
+	Quantity            float64                 `json:"quantity,omitempty"`
+	Reference           string                  `json:"reference,omitempty"`
+	Ticker              string                  `json:"ticker,omitempty"`
+	TickerCurrency      string                  `json:"tickerCurrency,omitempty"`
+	Type                HistoryDividendItemType `json:"type,omitempty"`
+}
+
+// HistoryTransactionItem represents the HistoryTransactionItem schema.
+type HistoryTransactionItem struct {
+	// Amount in the currency of the transaction
+	Amount float64 `json:"amount,omitempty"`
+	// Currency of the transaction
+	Currency string    `json:"currency,omitempty"`
+	DateTime time.Time `json:"dateTime,omitempty"`
+	// ID
+	Reference string                     `json:"reference,omitempty"`
+	Type      HistoryTransactionItemType `json:"type,omitempty"`
+}
+
+// Instrument represents the Instrument schema.
+// Instrument information as given by /instruments endpoint.
+type Instrument struct {
+	// Instrument currency in ISO 4217 format.
+	Currency string `json:"currency,omitempty"`
+	// ISIN of the instrument.
+	ISIN string `json:"isin,omitempty"`
+	// Name of the instrument.
+	Name string `json:"name,omitempty"`
+	// Unique instrument identifier.
+	Ticker string `json:"ticker,omitempty"`
+}
+
+// InstrumentIssue represents the InstrumentIssue schema.
+type InstrumentIssue struct {
+	Name     InstrumentIssueName     `json:"name,omitempty"`
+	Severity InstrumentIssueSeverity `json:"severity,omitempty"`
+}
+
+// InvestmentResult represents the InvestmentResult schema.
+type InvestmentResult struct {
+	PriceAvgInvestedValue float64 `json:"priceAvgInvestedValue,omitempty"`
+	PriceAvgResult        float64 `json:"priceAvgResult,omitempty"`
+	PriceAvgResultCoef    float64 `json:"priceAvgResultCoef,omitempty"`
+	PriceAvgValue         float64 `json:"priceAvgValue,omitempty"`
+}
+
+// Investments represents the Investments schema.
+type Investments struct {
+	// Current value of all the investments.
+	CurrentValue float64 `json:"currentValue,omitempty"`
+	// The all-time realised profit loss from all of the trades executed.
+	RealizedProfitLoss float64 `json:"realizedProfitLoss,omitempty"`
+	// The cost basis of your current investments. The total amount of funds you've invested in the shares you currently own.
+	TotalCost float64 `json:"totalCost,omitempty"`
+	// The potential profit/loss of your current investments, showing how much you could gain or lose if you were to sell them now.
+	UnrealizedProfitLoss float64 `json:"unrealizedProfitLoss,omitempty"`
+}
+
+// LimitRequest represents the LimitRequest schema.
+type LimitRequest struct {
+	LimitPrice float64 `json:"limitPrice,omitempty"`
+	Quantity   float64 `json:"quantity,omitempty"`
+	Ticker     string  `json:"ticker,omitempty"`
+	// Expiration
+	TimeValidity TimeValidity `json:"timeValidity,omitempty"`
+}
+
+// MarketRequest represents the MarketRequest schema.
+type MarketRequest struct {
+	ExtendedHours bool    `json:"extendedHours,omitempty"`
+	Quantity      float64 `json:"quantity,omitempty"`
+	Ticker        string  `json:"ticker,omitempty"`
+}
+
+// Order represents the Order schema.
+type Order struct {
+	// The ISO 8601 formatted date of when the order was created.
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+	// The currency used for the order in ISO 4217 format.
+	Currency string `json:"currency,omitempty"`
+	// If true, the order is eligible for execution outside regular trading hours.
+	ExtendedHours bool `json:"extendedHours,omitempty"`
+	// The number of shares that have been successfully executed. Applicable to quantity orders.
+	FilledQuantity float64 `json:"filledQuantity,omitempty"`
+	// The total monetary value of the executed portion of the order. Applicable to orders placed by value.Note: Placing orders by value is not currently supported via the API but can be done through other Trading 212 platforms.
+	FilledValue float64 `json:"filledValue,omitempty"`
+	// A unique, system-generated identifier for the order.
+	ID int64 `json:"id,omitempty"`
+	// How the order was initiated.
+	InitiatedFrom OrderInitiatedFrom `json:"initiatedFrom,omitempty"`
+	Instrument    Instrument         `json:"instrument,omitempty"`
+	// Applicable to LIMIT and STOP_LIMIT orders.
+	LimitPrice float64 `json:"limitPrice,omitempty"`
+	// The total number of shares requested. Applicable to quantity orders.
+	Quantity float64 `json:"quantity,omitempty"`
+	// Indicates whether the order is BUY or SELL.
+	Side OrderSide `json:"side,omitempty"`
+	// The current state of the order in its lifecycle.
+	Status OrderStatus `json:"status,omitempty"`
+	// Applicable to STOP and STOP_LIMIT orders.
+	StopPrice float64 `json:"stopPrice,omitempty"`
+	// The strategy used to place the order, either by QUANTITY or VALUE. The API currently only supports placing orders by QUANTITY.
+	Strategy OrderStrategy `json:"strategy,omitempty"`
+	// Unique instrument identifier. Get from the /instruments endpoint
+	Ticker      string       `json:"ticker,omitempty"`
+	TimeInForce TimeValidity `json:"timeInForce,omitempty"`
+	Type        OrderType    `json:"type,omitempty"`
+	// The total monetary value of the order. Applicable to value orders.
+	Value float64 `json:"value,omitempty"`
+}
+
+// PaginatedResponseHistoricalOrder represents the PaginatedResponseHistoricalOrder schema.
+type PaginatedResponseHistoricalOrder struct {
+	Items        []HistoricalOrder `json:"items,omitempty"`
+	NextPagePath string            `json:"nextPagePath,omitempty"`
+}
+
+// PaginatedResponseHistoryDividendItem represents the PaginatedResponseHistoryDividendItem schema.
+type PaginatedResponseHistoryDividendItem struct {
+	Items        []HistoryDividendItem `json:"items,omitempty"`
+	NextPagePath string                `json:"nextPagePath,omitempty"`
+}
+
+// PaginatedResponseHistoryTransactionItem represents the PaginatedResponseHistoryTransactionItem schema.
+type PaginatedResponseHistoryTransactionItem struct {
+	Items        []HistoryTransactionItem `json:"items,omitempty"`
+	NextPagePath string                   `json:"nextPagePath,omitempty"`
+}
+
+// PieRequest represents the PieRequest schema.
+type PieRequest struct {
+	DividendCashAction PieRequestDividendCashAction `json:"dividendCashAction,omitempty"`
+	EndDate            time.Time                    `json:"endDate,omitempty"`
+	// Total desired value of the pie in account currency
+	Goal             float64            `json:"goal,omitempty"`
+	Icon             string             `json:"icon,omitempty"`
+	InstrumentShares map[string]float64 `json:"instrumentShares,omitempty"`
+	Name             string             `json:"name,omitempty"`
+}
+
+// Position represents the Position schema.
+type Position struct {
+	// Average price paid, in instrument currency, per share.
+	AveragePricePaid float64 `json:"averagePricePaid,omitempty"`
+	// The ISO 8601 formatted date of when the position was opened.
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+	// Current price, in instrument currency, of a single share.
+	CurrentPrice float64    `json:"currentPrice,omitempty"`
+	Instrument   Instrument `json:"instrument,omitempty"`
+	// Total quantity of shares owned.
+	Quantity float64 `json:"quantity,omitempty"`
+	// Quantity of shares available for trading.
+	QuantityAvailableForTrading float64 `json:"quantityAvailableForTrading,omitempty"`
+	// Quantity of shares currently used in pie.
+	QuantityInPies float64 `json:"quantityInPies,omitempty"`
+	// Collects the financial impact of the position on the user's wallet.
+	WalletImpact PositionWalletImpact `json:"walletImpact,omitempty"`
+}
+
+// PositionWalletImpact represents the PositionWalletImpact schema.
+type PositionWalletImpact struct {
+	// The currency code used to represent all the wallet impact information.
+	Currency string `json:"currency,omitempty"`
+	// The current market value of the position.
+	CurrentValue float64 `json:"currentValue,omitempty"`
+	// The positive or negative impact on the position's value due to currency rate changes.
+	FxImpact float64 `json:"fxImpact,omitempty"`
+	// The total cost paid for the position.
+	TotalCost float64 `json:"totalCost,omitempty"`
+	// The unrealized profit & loss for the position. Calculated as currentValue - totalCost.
+	UnrealizedProfitLoss float64 `json:"unrealizedProfitLoss,omitempty"`
+}
+
+// PublicReportRequest represents the PublicReportRequest schema.
+type PublicReportRequest struct {
+	DataIncluded ReportDataIncluded `json:"dataIncluded,omitempty"`
+	TimeFrom     time.Time          `json:"timeFrom,omitempty"`
+	TimeTo       time.Time          `json:"timeTo,omitempty"`
+}
+
+// ReportDataIncluded represents the ReportDataIncluded schema.
+type ReportDataIncluded struct {
+	IncludeDividends    bool `json:"includeDividends,omitempty"`
+	IncludeInterest     bool `json:"includeInterest,omitempty"`
+	IncludeOrders       bool `json:"includeOrders,omitempty"`
+	IncludeTransactions bool `json:"includeTransactions,omitempty"`
+}
+
+// ReportResponse represents the ReportResponse schema.
+type ReportResponse struct {
+	DataIncluded ReportDataIncluded   `json:"dataIncluded,omitempty"`
+	DownloadLink string               `json:"downloadLink,omitempty"`
+	ReportID     int64                `json:"reportId,omitempty"`
+	Status       ReportResponseStatus `json:"status,omitempty"`
+	TimeFrom     time.Time            `json:"timeFrom,omitempty"`
+	TimeTo       time.Time            `json:"timeTo,omitempty"`
+}
+
+// StopLimitRequest represents the StopLimitRequest schema.
+type StopLimitRequest struct {
+	LimitPrice float64 `json:"limitPrice,omitempty"`
+	Quantity   float64 `json:"quantity,omitempty"`
+	StopPrice  float64 `json:"stopPrice,omitempty"`
+	Ticker     string  `json:"ticker,omitempty"`
+	// Expiration
+	TimeValidity TimeValidity `json:"timeValidity,omitempty"`
+}
+
+// StopRequest represents the StopRequest schema.
+type StopRequest struct {
+	Quantity  float64 `json:"quantity,omitempty"`
+	StopPrice float64 `json:"stopPrice,omitempty"`
+	Ticker    string  `json:"ticker,omitempty"`
+	// Expiration
+	TimeValidity TimeValidity `json:"timeValidity,omitempty"`
+}
+
+// Tax represents the Tax schema.
+type Tax struct {
+	ChargedAt time.Time `json:"chargedAt,omitempty"`
+	Currency  string    `json:"currency,omitempty"`
+	Name      TaxName   `json:"name,omitempty"`
+	Quantity  float64   `json:"quantity,omitempty"`
+}
+
+// TimeEvent represents the TimeEvent schema.
+type TimeEvent struct {
+	Date time.Time     `json:"date,omitempty"`
+	Type TimeEventType `json:"type,omitempty"`
+}
+
+// TimeValidity represents the TimeValidity schema.
+// Specifies how long the order remains active: * DAY: The order will automatically expire if not executed by midnight in the time zone of the instrument's exchange. * GOOD_TILL_CANCEL: The order remains active indefinitely until it is either filled or explicitly cancelled by you.
+type TimeValidity string
+
+const (
+	TimeValidityDay            TimeValidity = "DAY"
+	TimeValidityGoodTillCancel TimeValidity = "GOOD_TILL_CANCEL"
+)
+
+// TradableInstrument represents the TradableInstrument schema.
+type TradableInstrument struct {
+	// On the platform since
+	AddedOn time.Time `json:"addedOn,omitempty"`
+	// ISO 4217
+	CurrencyCode    string  `json:"currencyCode,omitempty"`
+	ExtendedHours   bool    `json:"extendedHours,omitempty"`
+	ISIN            string  `json:"isin,omitempty"`
+	MaxOpenQuantity float64 `json:"maxOpenQuantity,omitempty"`
+	Name            string  `json:"name,omitempty"`
+	ShortName       string  `json:"shortName,omitempty"`
+	// Unique identifier
+	Ticker string                 `json:"ticker,omitempty"`
+	Type   TradableInstrumentType `json:"type,omitempty"`
+	// Get items in the /exchanges endpoint
+	WorkingScheduleID int64 `json:"workingScheduleId,omitempty"`
+}
+
+// WorkingSchedule represents the WorkingSchedule schema.
+type WorkingSchedule struct {
+	ID         int64       `json:"id,omitempty"`
+	TimeEvents []TimeEvent `json:"timeEvents,omitempty"`
+}
+
+// AccountBucketDetailedResponseDividendCashAction represents the values of AccountBucketDetailedResponse.dividendCashAction.
+type AccountBucketDetailedResponseDividendCashAction string
+
+const (
+	AccountBucketDetailedResponseDividendCashActionReinvest      AccountBucketDetailedResponseDividendCashAction = "REINVEST"
+	AccountBucketDetailedResponseDividendCashActionToAccountCash AccountBucketDetailedResponseDividendCashAction = "TO_ACCOUNT_CASH"
+)
+
+// AccountBucketResultResponseStatus represents the values of AccountBucketResultResponse.status.
+type AccountBucketResultResponseStatus string
+
+const (
+	AccountBucketResultResponseStatusAhead   AccountBucketResultResponseStatus = "AHEAD"
+	AccountBucketResultResponseStatusOnTrack AccountBucketResultResponseStatus = "ON_TRACK"
+	AccountBucketResultResponseStatusBehind  AccountBucketResultResponseStatus = "BEHIND"
+)
+
+// FillTradingMethod represents the values of Fill.tradingMethod.
+type FillTradingMethod string
+
+const (
+	FillTradingMethodTOTV FillTradingMethod = "TOTV"
+	FillTradingMethodOTC  FillTradingMethod = "OTC"
+)
+
+// FillType represents the values of Fill.type.
+type FillType string
+
+const (
+	FillTypeTrade                   FillType = "TRADE"
+	FillTypeStockSplit              FillType = "STOCK_SPLIT"
+	FillTypeStockDistribution       FillType = "STOCK_DISTRIBUTION"
+	FillTypeFOP                     FillType = "FOP"
+	FillTypeFOPCorrection           FillType = "FOP_CORRECTION"
+	FillTypeCustomStockDistribution FillType = "CUSTOM_STOCK_DISTRIBUTION"
+	FillTypeEquityRights            FillType = "EQUITY_RIGHTS"
+)
+
+// HistoryDividendItemType represents the values of HistoryDividendItem.type.
+type HistoryDividendItemType string
+
+const (
+	HistoryDividendItemTypeOrdinary                                                          HistoryDividendItemType = "ORDINARY"
+	HistoryDividendItemTypeBonus                                                             HistoryDividendItemType = "BONUS"
+	HistoryDividendItemTypePropertyIncome                                                    HistoryDividendItemType = "PROPERTY_INCOME"
+	HistoryDividendItemTypeReturnOfCapitalNonUS                                              HistoryDividendItemType = "RETURN_OF_CAPITAL_NON_US"
+	HistoryDividendItemTypeDemerger                                                          HistoryDividendItemType = "DEMERGER"
+	HistoryDividendItemTypeInterest                                                          HistoryDividendItemType = "INTEREST"
+	HistoryDividendItemTypeCapitalGainsDistributionNonUS                                     HistoryDividendItemType = "CAPITAL_GAINS_DISTRIBUTION_NON_US"
+	HistoryDividendItemTypeInterimLiquidation                                                HistoryDividendItemType = "INTERIM_LIQUIDATION"
+	HistoryDividendItemTypeOrdinaryManufacturedPayment                                       HistoryDividendItemType = "ORDINARY_MANUFACTURED_PAYMENT"
+	HistoryDividendItemTypeBonusManufacturedPayment                                          HistoryDividendItemType = "BONUS_MANUFACTURED_PAYMENT"
+	HistoryDividendItemTypePropertyIncomeManufacturedPayment                                 HistoryDividendItemType = "PROPERTY_INCOME_MANUFACTURED_PAYMENT"
+	HistoryDividendItemTypeReturnOfCapitalNonUSManufacturedPayment                           HistoryDividendItemType = "RETURN_OF_CAPITAL_NON_US_MANUFACTURED_PAYMENT"
+	HistoryDividendItemTypeDemergerManufacturedPayment                                       HistoryDividendItemType = "DEMERGER_MANUFACTURED_PAYMENT"
+	HistoryDividendItemTypeInterestManufacturedPayment                                       HistoryDividendItemType = "INTEREST_MANUFACTURED_PAYMENT"
+	HistoryDividendItemTypeCapitalGainsDistributionNonUSManufacturedPayment                  HistoryDividendItemType = "CAPITAL_GAINS_DISTRIBUTION_NON_US_MANUFACTURED_PAYMENT"
+	HistoryDividendItemTypeInterimLiquidationManufacturedPayment                             HistoryDividendItemType = "INTERIM_LIQUIDATION_MANUFACTURED_PAYMENT"
+	HistoryDividendItemTypeInterestPaidByUSObligors                                          HistoryDividendItemType = "INTEREST_PAID_BY_US_OBLIGORS"
+	HistoryDividendItemTypeInterestPaidByForeignCorporations                                 HistoryDividendItemType = "INTEREST_PAID_BY_FOREIGN_CORPORATIONS"
+	HistoryDividendItemTypeDividendsPaidByUSCorporations                                     HistoryDividendItemType = "DIVIDENDS_PAID_BY_US_CORPORATIONS"
+	HistoryDividendItemTypeDividendsPaidByForeignCorporations                                HistoryDividendItemType = "DIVIDENDS_PAID_BY_FOREIGN_CORPORATIONS"
+	HistoryDividendItemTypeCapitalGains                                                      HistoryDividendItemType = "CAPITAL_GAINS"
+	HistoryDividendItemTypeRealPropertyIncomeAndNaturalResourcesRoyalties                    HistoryDividendItemType = "REAL_PROPERTY_INCOME_AND_NATURAL_RESOURCES_ROYALTIES"
+	HistoryDividendItemTypeOtherIncome                                                       HistoryDividendItemType = "OTHER_INCOME"
+	HistoryDividendItemTypeQualifiedInvestmentEntity                                         HistoryDividendItemType = "QUALIFIED_INVESTMENT_ENTITY"
+	HistoryDividendItemTypeTrustDistribution                                                 HistoryDividendItemType = "TRUST_DISTRIBUTION"
+	HistoryDividendItemTypePubliclyTradedPartnershipDistribution                             HistoryDividendItemType = "PUBLICLY_TRADED_PARTNERSHIP_DISTRIBUTION"
+	HistoryDividendItemTypeCapitalGainsDistribution                                          HistoryDividendItemType = "CAPITAL_GAINS_DISTRIBUTION"
+	HistoryDividendItemTypeReturnOfCapital                                                   HistoryDividendItemType = "RETURN_OF_CAPITAL"
+	HistoryDividendItemTypeOtherDividendEquivalent                                           HistoryDividendItemType = "OTHER_DIVIDEND_EQUIVALENT"
+	HistoryDividendItemTypeTaxEvent1446FForPubliclyTradedSecurities                          HistoryDividendItemType = "TAX_EVENT_1446F_FOR_PUBLICLY_TRADED_SECURITIES"
+	HistoryDividendItemTypePTPUncharacterisedIncome                                          HistoryDividendItemType = "PTP_UNCHARACTERISED_INCOME"
+	HistoryDividendItemTypeMultiple1042STaxComponents                                        HistoryDividendItemType = "MULTIPLE_1042S_TAX_COMPONENTS"
+	HistoryDividendItemTypeDividend                                                          HistoryDividendItemType = "DIVIDEND"
+	HistoryDividendItemTypeShortTermCapitalGains                                             HistoryDividendItemType = "SHORT_TERM_CAPITAL_GAINS"
+	HistoryDividendItemTypeLongTermCapitalGains                                              HistoryDividendItemType = "LONG_TERM_CAPITAL_GAINS"
+	HistoryDividendItemTypePropertyIncomeDistribution                                        HistoryDividendItemType = "PROPERTY_INCOME_DISTRIBUTION"
+	HistoryDividendItemTypeTaxExempted                                                       HistoryDividendItemType = "TAX_EXEMPTED"
+	HistoryDividendItemTypeInterestPaidByUSObligorsManufacturedPayment                       HistoryDividendItemType = "INTEREST_PAID_BY_US_OBLIGORS_MANUFACTURED_PAYMENT"
+	HistoryDividendItemTypeInterestPaidByForeignCorporationsManufacturedPayment              HistoryDividendItemType = "INTEREST_PAID_BY_FOREIGN_CORPORATIONS_MANUFACTURED_PAYMENT"
+	HistoryDividendItemTypeDividendsPaidByUSCorporationsManufacturedPayment                  HistoryDividendItemType = "DIVIDENDS_PAID_BY_US_CORPORATIONS_MANUFACTURED_PAYMENT"
+	HistoryDividendItemTypeDividendsPaidByForeignCorporationsManufacturedPayment             HistoryDividendItemType = "DIVIDENDS_PAID_BY_FOREIGN_CORPORATIONS_MANUFACTURED_PAYMENT"
+	HistoryDividendItemTypeCapitalGainsManufacturedPayment                                   HistoryDividendItemType = "CAPITAL_GAINS_MANUFACTURED_PAYMENT"
+	HistoryDividendItemTypeRealPropertyIncomeAndNaturalResourcesRoyaltiesManufacturedPayment HistoryDividendItemType = "REAL_PROPERTY_INCOME_AND_NATURAL_RESOURCES_ROYALTIES_MANUFACTURED_PAYMENT"
+	HistoryDividendItemTypeOtherIncomeManufacturedPayment                                    HistoryDividendItemType = "OTHER_INCOME_MANUFACTURED_PAYMENT"
+	HistoryDividendItemTypeQualifiedInvestmentEntityManufacturedPayment                      HistoryDividendItemType = "QUALIFIED_INVESTMENT_ENTITY_MANUFACTURED_PAYMENT"
+	HistoryDividendItemTypeTrustDistributionManufacturedPayment                              HistoryDividendItemType = "TRUST_DISTRIBUTION_MANUFACTURED_PAYMENT"
+	HistoryDividendItemTypePubliclyTradedPartnershipDistributionManufacturedPayment          HistoryDividendItemType = "PUBLICLY_TRADED_PARTNERSHIP_DISTRIBUTION_MANUFACTURED_PAYMENT"
+	HistoryDividendItemTypeCapitalGainsDistributionManufacturedPayment                       HistoryDividendItemType = "CAPITAL_GAINS_DISTRIBUTION_MANUFACTURED_PAYMENT"
+	HistoryDividendItemTypeReturnOfCapitalManufacturedPayment                                HistoryDividendItemType = "RETURN_OF_CAPITAL_MANUFACTURED_PAYMENT"
+	HistoryDividendItemTypeOtherDividendEquivalentManufacturedPayment                        HistoryDividendItemType = "OTHER_DIVIDEND_EQUIVALENT_MANUFACTURED_PAYMENT"
+	HistoryDividendItemTypeTaxEvent1446FForPubliclyTradedSecuritiesManufacturedPayment       HistoryDividendItemType = "TAX_EVENT_1446F_FOR_PUBLICLY_TRADED_SECURITIES_MANUFACTURED_PAYMENT"
+	HistoryDividendItemTypePTPUncharacterisedIncomeManufacturedPayment                       HistoryDividendItemType = "PTP_UNCHARACTERISED_INCOME_MANUFACTURED_PAYMENT"
+	HistoryDividendItemTypeMultiple1042STaxComponentsManufacturedPayment                     HistoryDividendItemType = "MULTIPLE_1042S_TAX_COMPONENTS_MANUFACTURED_PAYMENT"
+	HistoryDividendItemTypeDividendManufacturedPayment                                       HistoryDividendItemType = "DIVIDEND_MANUFACTURED_PAYMENT"
+	HistoryDividendItemTypeShortTermCapitalGainsManufacturedPayment                          HistoryDividendItemType = "SHORT_TERM_CAPITAL_GAINS_MANUFACTURED_PAYMENT"
+	HistoryDividendItemTypeLongTermCapitalGainsManufacturedPayment                           HistoryDividendItemType = "LONG_TERM_CAPITAL_GAINS_MANUFACTURED_PAYMENT"
+	HistoryDividendItemTypePropertyIncomeDistributionManufacturedPayment                     HistoryDividendItemType = "PROPERTY_INCOME_DISTRIBUTION_MANUFACTURED_PAYMENT"
+	HistoryDividendItemTypeTaxExemptedManufacturedPayment                                    HistoryDividendItemType = "TAX_EXEMPTED_MANUFACTURED_PAYMENT"
+)
+
+// HistoryTransactionItemType represents the values of HistoryTransactionItem.type.
+type HistoryTransactionItemType string
+
+const (
+	HistoryTransactionItemTypeWithdraw HistoryTransactionItemType = "WITHDRAW"
+	HistoryTransactionItemTypeDeposit  HistoryTransactionItemType = "DEPOSIT"
+	HistoryTransactionItemTypeFee      HistoryTransactionItemType = "FEE"
+	HistoryTransactionItemTypeTransfer HistoryTransactionItemType = "TRANSFER"
+)
+
+// InstrumentIssueName represents the values of InstrumentIssue.name.
+type InstrumentIssueName string
+
+const (
+	InstrumentIssueNameDelisted                         InstrumentIssueName = "DELISTED"
+	InstrumentIssueNameSuspended                        InstrumentIssueName = "SUSPENDED"
+	InstrumentIssueNameNoLongerTradable                 InstrumentIssueName = "NO_LONGER_TRADABLE"
+	InstrumentIssueNameMaxPositionSizeReached           InstrumentIssueName = "MAX_POSITION_SIZE_REACHED"
+	InstrumentIssueNameApproachingMaxPositionSize       InstrumentIssueName = "APPROACHING_MAX_POSITION_SIZE"
+	InstrumentIssueNameComplexInstrumentAppTestRequired InstrumentIssueName = "COMPLEX_INSTRUMENT_APP_TEST_REQUIRED"
+	InstrumentIssueNamePriceTooLow                      InstrumentIssueName = "PRICE_TOO_LOW"
+)
+
+// InstrumentIssueSeverity represents the values of InstrumentIssue.severity.
+type InstrumentIssueSeverity string
+
+const (
+	InstrumentIssueSeverityIrreversible InstrumentIssueSeverity = "IRREVERSIBLE"
+	InstrumentIssueSeverityReversible   InstrumentIssueSeverity = "REVERSIBLE"
+	InstrumentIssueSeverityInformative  InstrumentIssueSeverity = "INFORMATIVE"
+)
+
+// OrderInitiatedFrom represents the values of Order.initiatedFrom.
+type OrderInitiatedFrom string
+
+const (
+	OrderInitiatedFromAPI        OrderInitiatedFrom = "API"
+	OrderInitiatedFromIos        OrderInitiatedFrom = "IOS"
+	OrderInitiatedFromAndroid    OrderInitiatedFrom = "ANDROID"
+	OrderInitiatedFromWeb        OrderInitiatedFrom = "WEB"
+	OrderInitiatedFromSystem     OrderInitiatedFrom = "SYSTEM"
+	OrderInitiatedFromAutoinvest OrderInitiatedFrom = "AUTOINVEST"
+)
+
+// OrderSide represents the values of Order.side.
+type OrderSide string
+
+const (
+	OrderSideBuy  OrderSide = "BUY"
+	OrderSideSell OrderSide = "SELL"
+)
+
+// OrderStatus represents the values of Order.status.
+type OrderStatus string
+
+const (
+	OrderStatusLocal           OrderStatus = "LOCAL"
+	OrderStatusUnconfirmed     OrderStatus = "UNCONFIRMED"
+	OrderStatusConfirmed       OrderStatus = "CONFIRMED"
+	OrderStatusNew             OrderStatus = "NEW"
+	OrderStatusCancelling      OrderStatus = "CANCELLING"
+	OrderStatusCancelled       OrderStatus = "CANCELLED"
+	OrderStatusPartiallyFilled OrderStatus = "PARTIALLY_FILLED"
+	OrderStatusFilled          OrderStatus = "FILLED"
+	OrderStatusRejected        OrderStatus = "REJECTED"
+	OrderStatusReplacing       OrderStatus = "REPLACING"
+	OrderStatusReplaced        OrderStatus = "REPLACED"
+)
+
+// OrderStrategy represents the values of Order.strategy.
+type OrderStrategy string
+
+const (
+	OrderStrategyQuantity OrderStrategy = "QUANTITY"
+	OrderStrategyValue    OrderStrategy = "VALUE"
+)
+
+// OrderType represents the values of Order.type.
+type OrderType string
+
+const (
+	OrderTypeLimit     OrderType = "LIMIT"
+	OrderTypeStop      OrderType = "STOP"
+	OrderTypeMarket    OrderType = "MARKET"
+	OrderTypeStopLimit OrderType = "STOP_LIMIT"
+)
+
+// PieRequestDividendCashAction represents the values of PieRequest.dividendCashAction.
+type PieRequestDividendCashAction string
+
+const (
+	PieRequestDividendCashActionReinvest      PieRequestDividendCashAction = "REINVEST"
+	PieRequestDividendCashActionToAccountCash PieRequestDividendCashAction = "TO_ACCOUNT_CASH"
+)
+
+// ReportResponseStatus represents the values of ReportResponse.status.
+type ReportResponseStatus string
+
+const (
+	ReportResponseStatusQueued     ReportResponseStatus = "Queued"
+	ReportResponseStatusProcessing ReportResponseStatus = "Processing"
+	ReportResponseStatusRunning    ReportResponseStatus = "Running"
+	ReportResponseStatusCanceled   ReportResponseStatus = "Canceled"
+	ReportResponseStatusFailed     ReportResponseStatus = "Failed"
+	ReportResponseStatusFinished   ReportResponseStatus = "Finished"
+)
+
+// TaxName represents the values of Tax.name.
+type TaxName string
+
+const (
+	TaxNameCommissionTurnover    TaxName = "COMMISSION_TURNOVER"
+	TaxNameCurrencyConversionFee TaxName = "CURRENCY_CONVERSION_FEE"
+	TaxNameFinraFee              TaxName = "FINRA_FEE"
+	TaxNameFrenchTransactionTax  TaxName = "FRENCH_TRANSACTION_TAX"
+	TaxNamePtmLevy               TaxName = "PTM_LEVY"
+	TaxNameStampDuty             TaxName = "STAMP_DUTY"
+	TaxNameStampDutyReserveTax   TaxName = "STAMP_DUTY_RESERVE_TAX"
+	TaxNameTransactionFee        TaxName = "TRANSACTION_FEE"
+)
+
+// TimeEventType represents the values of TimeEvent.type.
+type TimeEventType string
+
+const (
+	TimeEventTypeOpen            TimeEventType = "OPEN"
+	TimeEventTypeClose           TimeEventType = "CLOSE"
+	TimeEventTypeBreakStart      TimeEventType = "BREAK_START"
+	TimeEventTypeBreakEnd        TimeEventType = "BREAK_END"
+	TimeEventTypePreMarketOpen   TimeEventType = "PRE_MARKET_OPEN"
+	TimeEventTypeAfterHoursOpen  TimeEventType = "AFTER_HOURS_OPEN"
+	TimeEventTypeAfterHoursClose TimeEventType = "AFTER_HOURS_CLOSE"
+	TimeEventTypeOvernightOpen   TimeEventType = "OVERNIGHT_OPEN"
+)
+
+// TradableInstrumentType represents the values of TradableInstrument.type.
+type TradableInstrumentType string
+
+const (
+	TradableInstrumentTypeCryptocurrency TradableInstrumentType = "CRYPTOCURRENCY"
+	TradableInstrumentTypeETF            TradableInstrumentType = "ETF"
+	TradableInstrumentTypeForex          TradableInstrumentType = "FOREX"
+	TradableInstrumentTypeFutures        TradableInstrumentType = "FUTURES"
+	TradableInstrumentTypeIndex          TradableInstrumentType = "INDEX"
+	TradableInstrumentTypeStock          TradableInstrumentType = "STOCK"
+	TradableInstrumentTypeWarrant        TradableInstrumentType = "WARRANT"
+	TradableInstrumentTypeCrypto         TradableInstrumentType = "CRYPTO"
+	TradableInstrumentTypeCVR            TradableInstrumentType = "CVR"
+	TradableInstrumentTypeCorpact        TradableInstrumentType = "CORPACT"
+)
+
+// Endpoint describes an operation in the spec.
+type Endpoint struct {
+	OperationID string
+	Method      string
+	Path        string
+	Request     string
+	Response    string
+}
+
+// Endpoints lists every operation in the spec sorted by path and method.
+var Endpoints = []Endpoint{
+	{OperationID: "getAccountSummary", Method: "GET", Path: "/api/v0/equity/account/summary", Request: "", Response: "AccountSummary"},
+	{OperationID: "dividends", Method: "GET", Path: "/api/v0/equity/history/dividends", Request: "", Response: "PaginatedResponseHistoryDividendItem"},
+	{OperationID: "getReports", Method: "GET", Path: "/api/v0/equity/history/exports", Request: "", Response: "[]ReportResponse"},
+	{OperationID: "requestReport", Method: "POST", Path: "/api/v0/equity/history/exports", Request: "PublicReportRequest", Response: "EnqueuedReportResponse"},
+	{OperationID: "orders_1", Method: "GET", Path: "/api/v0/equity/history/orders", Request: "", Response: "PaginatedResponseHistoricalOrder"},
+	{OperationID: "transactions", Method: "GET", Path: "/api/v0/equity/history/transactions", Request: "", Response: "PaginatedResponseHistoryTransactionItem"},
+	{OperationID: "exchanges", Method: "GET", Path: "/api/v0/equity/metadata/exchanges", Request: "", Response: "[]Exchange"},
+	{OperationID: "instruments", Method: "GET", Path: "/api/v0/equity/metadata/instruments", Request: "", Response: "[]TradableInstrument"},
+	{OperationID: "orders", Method: "GET", Path: "/api/v0/equity/orders", Request: "", Response: "[]Order"},
+	{OperationID: "placeLimitOrder", Method: "POST", Path: "/api/v0/equity/orders/limit", Request: "LimitRequest", Response: "Order"},
+	{OperationID: "placeMarketOrder", Method: "POST", Path: "/api/v0/equity/orders/market", Request: "MarketRequest", Response: "Order"},
+	{OperationID: "placeStopOrder_1", Method: "POST", Path: "/api/v0/equity/orders/stop", Request: "StopRequest", Response: "Order"},
+	{OperationID: "placeStopOrder", Method: "POST", Path: "/api/v0/equity/orders/stop_limit", Request: "StopLimitRequest", Response: "Order"},
+	{OperationID: "cancelOrder", Method: "DELETE", Path: "/api/v0/equity/orders/{id}", Request: "", Response: ""},
+	{OperationID: "orderById", Method: "GET", Path: "/api/v0/equity/orders/{id}", Request: "", Response: "Order"},
+	{OperationID: "getAll", Method: "GET", Path: "/api/v0/equity/pies", Request: "", Response: "[]AccountBucketResultResponse"},
+	{OperationID: "create", Method: "POST", Path: "/api/v0/equity/pies", Request: "PieRequest", Response: "AccountBucketInstrumentsDetailedResponse"},
+	{OperationID: "delete", Method: "DELETE", Path: "/api/v0/equity/pies/{id}", Request: "", Response: ""},
+	{OperationID: "getDetailed", Method: "GET", Path: "/api/v0/equity/pies/{id}", Request: "", Response: "AccountBucketInstrumentsDetailedResponse"},
+	{OperationID: "update", Method: "POST", Path: "/api/v0/equity/pies/{id}", Request: "PieRequest", Response: "AccountBucketInstrumentsDetailedResponse"},
+	{OperationID: "duplicatePie", Method: "POST", Path: "/api/v0/equity/pies/{id}/duplicate", Request: "DuplicateBucketRequest", Response: "AccountBucketInstrumentsDetailedResponse"},
+	{OperationID: "getPositions", Method: "GET", Path: "/api/v0/equity/positions", Request: "", Response: "[]Position"},
+}
+
+// DividendsParams represents the query parameters of GET /api/v0/equity/history/dividends.
+type DividendsParams struct {
+	// Pagination cursor
+	Cursor *int64 `query:"cursor"`
+	// Ticker filter
+	Ticker *string `query:"ticker"`
+	// Max items: 50
+	Limit *int32 `query:"limit"`
+}
+
+// Orders1Params represents the query parameters of GET /api/v0/equity/history/orders.
+type Orders1Params struct {
+	// Pagination cursor
+	Cursor *int64 `query:"cursor"`
+	// Ticker filter
+	Ticker *string `query:"ticker"`
+	// Max items: 50
+	Limit *int32 `query:"limit"`
+}
+
+// TransactionsParams represents the query parameters of GET /api/v0/equity/history/transactions.
+type TransactionsParams struct {
+	// Pagination cursor
+	Cursor *string `query:"cursor"`
+	// Retrieve transactions starting from the specified time
+	Time *time.Time `query:"time"`
+	// Max items: 50
+	Limit *int32 `query:"limit"`
+}
+
+// GetPositionsParams represents the query parameters of GET /api/v0/equity/positions.
+type GetPositionsParams struct {
+	Ticker *string `query:"ticker"`
+}
+
+// API has a method skeleton for every operation in the spec.
+type API interface {
+	// GetAccountSummary calls GET /api/v0/equity/account/summary: Get account summary
+	GetAccountSummary(ctx context.Context) (*AccountSummary, error)
+	// Dividends calls GET /api/v0/equity/history/dividends: Get paid out dividends
+	Dividends(ctx context.Context, params DividendsParams) (*PaginatedResponseHistoryDividendItem, error)
+	// GetReports calls GET /api/v0/equity/history/exports: List generated reports
+	GetReports(ctx context.Context) ([]ReportResponse, error)
+	// RequestReport calls POST /api/v0/equity/history/exports: Request a CSV report
+	RequestReport(ctx context.Context, body PublicReportRequest) (*EnqueuedReportResponse, error)
+	// Orders1 calls GET /api/v0/equity/history/orders: Get historical orders data
+	Orders1(ctx context.Context, params Orders1Params) (*PaginatedResponseHistoricalOrder, error)
+	// Transactions calls GET /api/v0/equity/history/transactions: Get transactions
+	Transactions(ctx context.Context, params TransactionsParams) (*PaginatedResponseHistoryTransactionItem, error)
+	// Exchanges calls GET /api/v0/equity/metadata/exchanges: Get exchanges metadata
+	Exchanges(ctx context.Context) ([]Exchange, error)
+	// Instruments calls GET /api/v0/equity/metadata/instruments: Get all available instruments
+	Instruments(ctx context.Context) ([]TradableInstrument, error)
+	// Orders calls GET /api/v0/equity/orders: Get all pending orders
+	Orders(ctx context.Context) ([]Order, error)
+	// PlaceLimitOrder calls POST /api/v0/equity/orders/limit: Place a Limit order
+	PlaceLimitOrder(ctx context.Context, body LimitRequest) (*Order, error)
+	// PlaceMarketOrder calls POST /api/v0/equity/orders/market: Place a Market order
+	PlaceMarketOrder(ctx context.Context, body MarketRequest) (*Order, error)
+	// PlaceStopOrder1 calls POST /api/v0/equity/orders/stop: Place a Stop order
+	PlaceStopOrder1(ctx context.Context, body StopRequest) (*Order, error)
+	// PlaceStopOrder calls POST /api/v0/equity/orders/stop_limit: Place a StopLimit order
+	PlaceStopOrder(ctx context.Context, body StopLimitRequest) (*Order, error)
+	// CancelOrder calls DELETE /api/v0/equity/orders/{id}: Cancel a pending order
+	CancelOrder(ctx context.Context, id int64) error
+	// OrderByID calls GET /api/v0/equity/orders/{id}: Get a pending order by ID
+	OrderByID(ctx context.Context, id int64) (*Order, error)
+	// GetAll calls GET /api/v0/equity/pies: Fetch all pies
+	GetAll(ctx context.Context) ([]AccountBucketResultResponse, error)
+	// Create calls POST /api/v0/equity/pies: Create pie
+	Create(ctx context.Context, body PieRequest) (*AccountBucketInstrumentsDetailedResponse, error)
+	// Delete calls DELETE /api/v0/equity/pies/{id}: Delete pie
+	Delete(ctx context.Context, id int64) error
+	// GetDetailed calls GET /api/v0/equity/pies/{id}: Fetch a pie
+	GetDetailed(ctx context.Context, id int64) (*AccountBucketInstrumentsDetailedResponse, error)
+	// Update calls POST /api/v0/equity/pies/{id}: Update pie
+	Update(ctx context.Context, id int64, body PieRequest) (*AccountBucketInstrumentsDetailedResponse, error)
+	// DuplicatePie calls POST /api/v0/equity/pies/{id}/duplicate: Duplicate pie
+	DuplicatePie(ctx context.Context, id int64, body DuplicateBucketRequest) (*AccountBucketInstrumentsDetailedResponse, error)
+	// GetPositions calls GET /api/v0/equity/positions: Fetch all open positions
+	GetPositions(ctx context.Context, params GetPositionsParams) ([]Position, error)
+}
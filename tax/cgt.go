@@ -0,0 +1,394 @@
+// Package tax computes UK capital gains on shares from the fill history.
+//
+// Disposals are matched with acquisitions using the HMRC share identification
+// rules: first with shares bought on the same day, then with shares bought in
+// the following 30 days (the bed and breakfast rule), and finally with the
+// Section 104 pool, which holds every other acquisition at its average cost.
+// Values are converted to pounds with the fill's FX rate, and the taxes and
+// fees charged on a fill are allowable costs.
+package tax
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/internal/decimals"
+)
+
+// Currency is the currency gains are computed in
+const Currency = "GBP"
+
+// ErrCurrency is returned for fills whose wallet currency is not pounds
+var ErrCurrency = errors.New("capital gains require a GBP account")
+
+// bedAndBreakfastDays is how long after a disposal an acquisition is matched
+// with it instead of the pool
+const bedAndBreakfastDays = 30
+
+// Rule represents the share identification rule that matched a disposal
+type Rule string
+
+const (
+	RuleSameDay         Rule = "SAME_DAY"
+	RuleBedAndBreakfast Rule = "BED_AND_BREAKFAST"
+	RuleSection104      Rule = "SECTION_104"
+)
+
+// Account represents the fill history of one account. Shares are pooled
+// across all accounts that are not ISAs.
+type Account struct {
+	Name string
+	// ISA accounts are exempt from capital gains tax and are skipped
+	ISA    bool
+	Orders []trading212.HistoricalOrder
+}
+
+// Match represents the acquisition cost matched with part of a disposal
+type Match struct {
+	Rule     Rule               `json:"rule"`
+	Quantity trading212.Decimal `json:"quantity"`
+	Cost     trading212.Decimal `json:"cost"`
+	// AcquiredOn is the acquisition date; zero for the Section 104 pool
+	AcquiredOn time.Time `json:"acquiredOn"`
+}
+
+// Disposal represents the shares of an instrument sold on one day, which
+// HMRC treats as a single disposal
+type Disposal struct {
+	Ticker   string             `json:"ticker"`
+	Date     time.Time          `json:"date"`
+	TaxYear  TaxYear            `json:"taxYear"`
+	Quantity trading212.Decimal `json:"quantity"`
+	// Proceeds is the gross sale value
+	Proceeds trading212.Decimal `json:"proceeds"`
+	// AllowableCost is the matched acquisition cost plus the costs of sale
+	AllowableCost trading212.Decimal `json:"allowableCost"`
+	// Gain is Proceeds - AllowableCost; losses are negative
+	Gain    trading212.Decimal `json:"gain"`
+	Matches []Match            `json:"matches"`
+}
+
+// Pool represents the Section 104 holding of an instrument
+type Pool struct {
+	Ticker   string             `json:"ticker"`
+	Quantity trading212.Decimal `json:"quantity"`
+	Cost     trading212.Decimal `json:"cost"`
+}
+
+// YearSummary represents the disposals of a tax year
+type YearSummary struct {
+	TaxYear        TaxYear            `json:"taxYear"`
+	Disposals      int                `json:"disposals"`
+	Proceeds       trading212.Decimal `json:"proceeds"`
+	AllowableCosts trading212.Decimal `json:"allowableCosts"`
+	// Gains is the total of the disposals at a gain
+	Gains trading212.Decimal `json:"gains"`
+	// Losses is the total of the disposals at a loss, as a positive amount
+	Losses  trading212.Decimal `json:"losses"`
+	NetGain trading212.Decimal `json:"netGain"`
+}
+
+// Report represents the capital gains computed from the fill history
+type Report struct {
+	// Disposals are ordered by date, then ticker
+	Disposals []Disposal
+	// Years are the tax years with disposals, oldest first
+	Years []YearSummary
+	// Pools are the Section 104 holdings left after the last fill
+	Pools []Pool
+	// Excluded lists the ISA accounts that were skipped
+	Excluded []string
+	// Warnings lists problems such as disposals of more shares than held
+	Warnings []string
+}
+
+// Year returns the summary of year, which is empty if there were no
+// disposals
+func (r *Report) Year(year TaxYear) YearSummary {
+	for _, s := range r.Years {
+		if s.TaxYear == year {
+			return s
+		}
+	}
+	return YearSummary{TaxYear: year}
+}
+
+// day represents the fills of an instrument on one date
+type day struct {
+	date time.Time
+
+	bought     trading212.Decimal
+	boughtCost trading212.Decimal
+	sold       trading212.Decimal
+	proceeds   trading212.Decimal
+	saleCosts  trading212.Decimal
+
+	// adjustment is the change in quantity from splits and distributions,
+	// which changes the pool without a new acquisition
+	adjustment trading212.Decimal
+	// transferred is the quantity moved out by FOP, removed from the pool
+	// at its average cost without a disposal
+	transferred trading212.Decimal
+
+	// unmatched acquisitions and disposals left after each rule
+	buyLeft     trading212.Decimal
+	buyCostLeft trading212.Decimal
+	sellLeft    trading212.Decimal
+	matches     []Match
+}
+
+// Compute returns the capital gains of the accounts that are not ISAs
+func Compute(accounts []Account) (*Report, error) {
+	report := &Report{}
+	byTicker := make(map[string]map[time.Time]*day)
+	for _, account := range accounts {
+		if account.ISA {
+			report.Excluded = append(report.Excluded, account.Name)
+			continue
+		}
+		for _, order := range account.Orders {
+			if err := add(byTicker, order); err != nil {
+				return nil, fmt.Errorf("failed to add fill %d of %s: %w", order.Fill.ID, accountName(account), err)
+			}
+		}
+	}
+
+	tickers := make([]string, 0, len(byTicker))
+	for ticker := range byTicker {
+		tickers = append(tickers, ticker)
+	}
+	sort.Strings(tickers)
+
+	for _, ticker := range tickers {
+		days := make([]*day, 0, len(byTicker[ticker]))
+		for _, d := range byTicker[ticker] {
+			days = append(days, d)
+		}
+		sort.Slice(days, func(i, j int) bool {
+			return days[i].date.Before(days[j].date)
+		})
+		report.match(ticker, days)
+	}
+
+	sort.SliceStable(report.Disposals, func(i, j int) bool {
+		return report.Disposals[i].Date.Before(report.Disposals[j].Date)
+	})
+	report.summarise()
+	return report, nil
+}
+
+// add records a fill against its instrument and date
+func add(byTicker map[string]map[time.Time]*day, order trading212.HistoricalOrder) error {
+	f := order.Fill
+	quantity := trading212.DecimalFromFloat(f.Quantity).Abs()
+	if quantity.IsZero() {
+		return nil
+	}
+	if f.WalletImpact.Currency != "" && f.WalletImpact.Currency != Currency {
+		return fmt.Errorf("%w, fill is in %s", ErrCurrency, f.WalletImpact.Currency)
+	}
+
+	ticker := order.Order.Ticker
+	days := byTicker[ticker]
+	if days == nil {
+		days = make(map[time.Time]*day)
+		byTicker[ticker] = days
+	}
+	date := dateOf(f.FilledAt)
+	d := days[date]
+	if d == nil {
+		d = &day{date: date}
+		days[date] = d
+	}
+
+	disposal := order.Order.Side == trading212.OrderSideSell || f.Quantity < 0
+	value := quantity.Mul(trading212.DecimalFromFloat(f.Price))
+	if f.WalletImpact.FxRate != 0 {
+		value = value.Mul(trading212.DecimalFromFloat(f.WalletImpact.FxRate))
+	}
+	var costs trading212.Decimal
+	for _, t := range f.WalletImpact.Taxes {
+		costs = costs.Add(trading212.DecimalFromFloat(t.Quantity).Abs())
+	}
+
+	switch f.Type {
+	case trading212.FillTypeStockSplit, trading212.FillTypeStockDistribution, trading212.FillTypeCustomStockDistribution:
+		if disposal {
+			quantity = quantity.Neg()
+		}
+		d.adjustment = d.adjustment.Add(quantity)
+	case trading212.FillTypeFOP, trading212.FillTypeFOPCorrection:
+		if disposal {
+			d.transferred = d.transferred.Add(quantity)
+		} else {
+			d.bought = d.bought.Add(quantity)
+			d.boughtCost = d.boughtCost.Add(value).Add(costs)
+		}
+	default:
+		if disposal {
+			d.sold = d.sold.Add(quantity)
+			d.proceeds = d.proceeds.Add(value)
+			d.saleCosts = d.saleCosts.Add(costs)
+		} else {
+			d.bought = d.bought.Add(quantity)
+			d.boughtCost = d.boughtCost.Add(value).Add(costs)
+		}
+	}
+	return nil
+}
+
+// match applies the identification rules to the days of one instrument
+func (r *Report) match(ticker string, days []*day) {
+	for _, d := range days {
+		d.buyLeft, d.buyCostLeft, d.sellLeft = d.bought, d.boughtCost, d.sold
+	}
+
+	for _, d := range days {
+		matchAcquisition(d, d, RuleSameDay)
+	}
+	for i, d := range days {
+		limit := d.date.AddDate(0, 0, bedAndBreakfastDays)
+		for _, later := range days[i+1:] {
+			if later.date.After(limit) || d.sellLeft.IsZero() {
+				break
+			}
+			matchAcquisition(d, later, RuleBedAndBreakfast)
+		}
+	}
+
+	pool := Pool{Ticker: ticker}
+	for _, d := range days {
+		if !d.adjustment.IsZero() {
+			if pool.Quantity.IsZero() {
+				r.warn("%s: %s shares from a corporate action on %s with nothing in the pool", ticker, d.adjustment, d.date.Format("2006-01-02"))
+			}
+			pool.Quantity = pool.Quantity.Add(d.adjustment)
+			if pool.Quantity.Sign() < 0 {
+				pool.Quantity = trading212.Decimal{}
+			}
+		}
+		pool.Quantity = pool.Quantity.Add(d.buyLeft)
+		pool.Cost = pool.Cost.Add(d.buyCostLeft)
+		if !d.transferred.IsZero() {
+			r.takeFromPool(&pool, d.transferred, d.date)
+		}
+		if !d.sellLeft.IsZero() {
+			quantity, cost := r.takeFromPool(&pool, d.sellLeft, d.date)
+			if !quantity.IsZero() {
+				d.matches = append(d.matches, Match{Rule: RuleSection104, Quantity: quantity, Cost: cost})
+			}
+		}
+
+		if d.sold.IsZero() {
+			continue
+		}
+		disposal := Disposal{
+			Ticker:        ticker,
+			Date:          d.date,
+			TaxYear:       TaxYearOf(d.date),
+			Quantity:      d.sold,
+			Proceeds:      d.proceeds,
+			AllowableCost: d.saleCosts,
+			Matches:       d.matches,
+		}
+		for _, m := range d.matches {
+			disposal.AllowableCost = disposal.AllowableCost.Add(m.Cost)
+		}
+		disposal.Gain = disposal.Proceeds.Sub(disposal.AllowableCost)
+		r.Disposals = append(r.Disposals, disposal)
+	}
+
+	if !pool.Quantity.IsZero() {
+		r.Pools = append(r.Pools, pool)
+	}
+}
+
+// matchAcquisition matches the unmatched disposal of d with the unmatched
+// acquisition of acquired
+func matchAcquisition(d, acquired *day, rule Rule) {
+	quantity := d.sellLeft
+	if acquired.buyLeft.Cmp(quantity) < 0 {
+		quantity = acquired.buyLeft
+	}
+	if quantity.IsZero() {
+		return
+	}
+
+	cost := acquired.buyCostLeft
+	if !quantity.Equal(acquired.buyLeft) {
+		cost = decimals.Prorate(acquired.buyCostLeft, quantity, acquired.buyLeft)
+	}
+	d.matches = append(d.matches, Match{Rule: rule, Quantity: quantity, Cost: cost, AcquiredOn: acquired.date})
+	d.sellLeft = d.sellLeft.Sub(quantity)
+	acquired.buyLeft = acquired.buyLeft.Sub(quantity)
+	acquired.buyCostLeft = acquired.buyCostLeft.Sub(cost)
+}
+
+// takeFromPool removes quantity from the pool at its average cost and
+// returns the quantity and cost removed
+func (r *Report) takeFromPool(pool *Pool, quantity trading212.Decimal, date time.Time) (trading212.Decimal, trading212.Decimal) {
+	if quantity.Cmp(pool.Quantity) > 0 {
+		r.warn("%s: %s shares disposed of on %s but only %s are in the pool", pool.Ticker, quantity, date.Format("2006-01-02"), pool.Quantity)
+		quantity = pool.Quantity
+	}
+	if quantity.IsZero() {
+		return quantity, trading212.Decimal{}
+	}
+
+	cost := pool.Cost
+	if !quantity.Equal(pool.Quantity) {
+		cost = decimals.Prorate(pool.Cost, quantity, pool.Quantity)
+	}
+	pool.Quantity = pool.Quantity.Sub(quantity)
+	pool.Cost = pool.Cost.Sub(cost)
+	return quantity, cost
+}
+
+// summarise totals the disposals by tax year
+func (r *Report) summarise() {
+	years := make(map[TaxYear]*YearSummary)
+	for _, d := range r.Disposals {
+		s := years[d.TaxYear]
+		if s == nil {
+			s = &YearSummary{TaxYear: d.TaxYear}
+			years[d.TaxYear] = s
+		}
+		s.Disposals++
+		s.Proceeds = s.Proceeds.Add(d.Proceeds)
+		s.AllowableCosts = s.AllowableCosts.Add(d.AllowableCost)
+		if d.Gain.Sign() > 0 {
+			s.Gains = s.Gains.Add(d.Gain)
+		} else {
+			s.Losses = s.Losses.Sub(d.Gain)
+		}
+		s.NetGain = s.NetGain.Add(d.Gain)
+	}
+
+	for _, s := range years {
+		r.Years = append(r.Years, *s)
+	}
+	sort.Slice(r.Years, func(i, j int) bool {
+		return r.Years[i].TaxYear < r.Years[j].TaxYear
+	})
+}
+
+func (r *Report) warn(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// dateOf returns the UK date of t as midnight UTC, so dates compare and
+// add days without daylight saving shifts
+func dateOf(t time.Time) time.Time {
+	y, m, d := t.In(london).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func accountName(account Account) string {
+	if account.Name == "" {
+		return "account"
+	}
+	return "account " + account.Name
+}
@@ -0,0 +1,154 @@
+package tax
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	trading212 "github.com/SwanHtetAungPhyo/trading212-go-sdk"
+	"github.com/SwanHtetAungPhyo/trading212-go-sdk/internal/fixtures"
+)
+
+func fill(id int64, ticker, date string, fillType trading212.FillType, quantity, price, fxRate float64, taxes ...float64) trading212.HistoricalOrder {
+	filledAt, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		panic(err)
+	}
+	order := fixtures.Fill(id, ticker, filledAt.Add(12*time.Hour), fillType, quantity, price)
+	order.Fill.WalletImpact.FxRate = fxRate
+	for _, tax := range taxes {
+		order.Fill.WalletImpact.Taxes = append(order.Fill.WalletImpact.Taxes, trading212.Tax{Name: "STAMP_DUTY", Quantity: tax, Currency: "GBP"})
+	}
+	return order
+}
+
+func trade(id int64, ticker, date string, quantity, price float64, taxes ...float64) trading212.HistoricalOrder {
+	return fill(id, ticker, date, trading212.FillTypeTrade, quantity, price, 0, taxes...)
+}
+
+var assertDecimal = fixtures.AssertDecimal
+
+func history() []trading212.HistoricalOrder {
+	return []trading212.HistoricalOrder{
+		trade(1, "VOD", "2023-05-01", 100, 10, -5),
+		trade(2, "VOD", "2023-06-01", 100, 12),
+		trade(3, "VOD", "2023-07-01", -50, 15, -2),
+		trade(4, "VOD", "2023-07-01", 20, 14),
+		trade(5, "VOD", "2023-07-11", 10, 13),
+		fill(6, "MSFT", "2024-04-01", trading212.FillTypeTrade, 2, 100, 0.8),
+		fill(7, "MSFT", "2024-04-10", trading212.FillTypeTrade, -2, 90, 0.8),
+	}
+}
+
+func TestComputeMatchingRules(t *testing.T) {
+	report, err := Compute([]Account{{Name: "invest", Orders: history()}})
+	require.NoError(t, err)
+	require.Len(t, report.Disposals, 2)
+
+	vod := report.Disposals[0]
+	assert.Equal(t, "VOD", vod.Ticker)
+	assert.Equal(t, TaxYear(2023), vod.TaxYear)
+	assertDecimal(t, "50", vod.Quantity)
+	assertDecimal(t, "750", vod.Proceeds)
+	assertDecimal(t, "632.5", vod.AllowableCost)
+	assertDecimal(t, "117.5", vod.Gain)
+
+	require.Len(t, vod.Matches, 3)
+	assert.Equal(t, RuleSameDay, vod.Matches[0].Rule)
+	assertDecimal(t, "280", vod.Matches[0].Cost)
+	assert.Equal(t, RuleBedAndBreakfast, vod.Matches[1].Rule)
+	assertDecimal(t, "130", vod.Matches[1].Cost)
+	assert.Equal(t, "2023-07-11", vod.Matches[1].AcquiredOn.Format("2006-01-02"))
+	assert.Equal(t, RuleSection104, vod.Matches[2].Rule)
+	assertDecimal(t, "20", vod.Matches[2].Quantity)
+	assertDecimal(t, "220.5", vod.Matches[2].Cost)
+
+	msft := report.Disposals[1]
+	assert.Equal(t, TaxYear(2024), msft.TaxYear)
+	assertDecimal(t, "144", msft.Proceeds)
+	assertDecimal(t, "-16", msft.Gain)
+
+	// Shares matched by the 30-day rule never enter the pool
+	require.Len(t, report.Pools, 1)
+	assertDecimal(t, "180", report.Pools[0].Quantity)
+	assertDecimal(t, "1984.5", report.Pools[0].Cost)
+
+	require.Len(t, report.Years, 2)
+	assertDecimal(t, "117.5", report.Year(2023).Gains)
+	assertDecimal(t, "16", report.Year(2024).Losses)
+	assertDecimal(t, "-16", report.Year(2024).NetGain)
+	assert.Equal(t, 0, report.Year(2025).Disposals)
+	assert.Empty(t, report.Warnings)
+}
+
+func TestComputePoolsAcrossAccountsAndSkipsISA(t *testing.T) {
+	report, err := Compute([]Account{
+		{Name: "invest", Orders: []trading212.HistoricalOrder{trade(1, "VOD", "2023-05-01", 10, 10)}},
+		{Name: "isa", ISA: true, Orders: []trading212.HistoricalOrder{trade(2, "VOD", "2023-05-02", -50, 20)}},
+		{Name: "invest2", Orders: []trading212.HistoricalOrder{trade(3, "VOD", "2023-09-01", -10, 12)}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"isa"}, report.Excluded)
+	require.Len(t, report.Disposals, 1)
+	assertDecimal(t, "20", report.Disposals[0].Gain)
+	assert.Empty(t, report.Pools)
+	assert.Empty(t, report.Warnings)
+}
+
+func TestComputeStockSplit(t *testing.T) {
+	report, err := Compute([]Account{{Orders: []trading212.HistoricalOrder{
+		trade(1, "NVDA", "2023-05-01", 10, 100),
+		fill(2, "NVDA", "2024-06-10", trading212.FillTypeStockSplit, -10, 100, 0),
+		fill(3, "NVDA", "2024-06-10", trading212.FillTypeStockSplit, 100, 10, 0),
+		trade(4, "NVDA", "2024-07-01", -50, 12),
+	}}})
+	require.NoError(t, err)
+
+	require.Len(t, report.Disposals, 1)
+	assertDecimal(t, "500", report.Disposals[0].AllowableCost)
+	assertDecimal(t, "100", report.Disposals[0].Gain)
+	assertDecimal(t, "50", report.Pools[0].Quantity)
+}
+
+func TestComputeWarnsOnShortPool(t *testing.T) {
+	report, err := Compute([]Account{{Orders: []trading212.HistoricalOrder{
+		trade(1, "TSLA", "2023-05-01", -1, 100),
+	}}})
+	require.NoError(t, err)
+	require.Len(t, report.Warnings, 1)
+	assertDecimal(t, "100", report.Disposals[0].Gain)
+}
+
+func TestComputeRejectsOtherCurrencies(t *testing.T) {
+	order := trade(1, "TSLA", "2023-05-01", 1, 100)
+	order.Fill.WalletImpact.Currency = "EUR"
+	_, err := Compute([]Account{{Name: "invest", Orders: []trading212.HistoricalOrder{order}}})
+	assert.ErrorIs(t, err, ErrCurrency)
+}
+
+func TestTaxYear(t *testing.T) {
+	assert.Equal(t, TaxYear(2023), TaxYearOf(time.Date(2024, 4, 5, 12, 0, 0, 0, time.UTC)))
+	assert.Equal(t, TaxYear(2024), TaxYearOf(time.Date(2024, 4, 6, 12, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "2024/25", TaxYear(2024).String())
+	assert.Equal(t, "1999/00", TaxYear(1999).String())
+	assert.True(t, TaxYear(2024).Contains(TaxYear(2024).Start()))
+	assert.False(t, TaxYear(2024).Contains(TaxYear(2024).End()))
+
+	for _, s := range []string{"2024/25", "2024-25", "2024"} {
+		y, err := ParseTaxYear(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, TaxYear(2024), y)
+	}
+	for _, s := range []string{"2024/26", "24/25", "year"} {
+		_, err := ParseTaxYear(s)
+		assert.Error(t, err, s)
+	}
+
+	data, err := json.Marshal(YearSummary{TaxYear: 2024})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"taxYear":"2024/25"`)
+}
@@ -0,0 +1,83 @@
+package tax
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// london is the time zone that decides the date of a transaction; UTC is
+// used when the tz database is unavailable
+var london = func() *time.Location {
+	loc, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}()
+
+// TaxYear represents a UK tax year by the calendar year it starts in, so
+// 2024 is the year from 6 April 2024 to 5 April 2025
+type TaxYear int
+
+// TaxYearOf returns the tax year containing t
+func TaxYearOf(t time.Time) TaxYear {
+	t = t.In(london)
+	if t.Month() < time.April || (t.Month() == time.April && t.Day() < 6) {
+		return TaxYear(t.Year() - 1)
+	}
+	return TaxYear(t.Year())
+}
+
+// ParseTaxYear parses a tax year written as 2024/25, 2024-25 or 2024
+func ParseTaxYear(s string) (TaxYear, error) {
+	start := s
+	if i := strings.IndexAny(s, "/-"); i >= 0 {
+		start = s[:i]
+	}
+	year, err := strconv.Atoi(start)
+	if err != nil || len(start) != 4 {
+		return 0, fmt.Errorf("invalid tax year %q, expected YYYY/YY", s)
+	}
+	y := TaxYear(year)
+	if start != s && s != y.String() && s != strings.Replace(y.String(), "/", "-", 1) {
+		return 0, fmt.Errorf("invalid tax year %q, expected %s", s, y)
+	}
+	return y, nil
+}
+
+// Start returns the first moment of the tax year
+func (y TaxYear) Start() time.Time {
+	return time.Date(int(y), time.April, 6, 0, 0, 0, 0, london)
+}
+
+// End returns the first moment of the next tax year
+func (y TaxYear) End() time.Time {
+	return (y + 1).Start()
+}
+
+// Contains reports whether t falls in the tax year
+func (y TaxYear) Contains(t time.Time) bool {
+	return TaxYearOf(t) == y
+}
+
+// String returns the tax year as 2024/25
+func (y TaxYear) String() string {
+	return fmt.Sprintf("%d/%02d", int(y), (int(y)+1)%100)
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (y TaxYear) MarshalText() ([]byte, error) {
+	return []byte(y.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (y *TaxYear) UnmarshalText(data []byte) error {
+	parsed, err := ParseTaxYear(string(data))
+	if err != nil {
+		return err
+	}
+	*y = parsed
+	return nil
+}
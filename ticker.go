@@ -0,0 +1,217 @@
+package trading212
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Ticker represents a parsed Trading 212 ticker. US listings use the form
+// SYMBOL_COUNTRY_KIND (AAPL_US_EQ) while European listings append a
+// lower-case market suffix to the symbol (VUSAl_EQ).
+type Ticker struct {
+	Symbol  string
+	Country string
+	Market  string
+	Kind    string
+}
+
+// tickerMarket describes a European market suffix
+type tickerMarket struct {
+	name         string
+	symbolSuffix string
+}
+
+// tickerMarkets maps the lower-case market suffixes used in tickers to the
+// exchange and the suffix used by common market data vendors
+var tickerMarkets = map[string]tickerMarket{
+	"l": {"London Stock Exchange", ".L"},
+	"d": {"Xetra", ".DE"},
+	"p": {"Euronext Paris", ".PA"},
+	"a": {"Euronext Amsterdam", ".AS"},
+	"m": {"Borsa Italiana", ".MI"},
+	"e": {"Bolsa de Madrid", ".MC"},
+	"s": {"SIX Swiss Exchange", ".SW"},
+	"b": {"Euronext Brussels", ".BR"},
+}
+
+// ErrInvalidTicker is returned when a string is not a Trading 212 ticker
+var ErrInvalidTicker = errors.New("invalid ticker")
+
+// ParseTicker splits a Trading 212 ticker into its components
+func ParseTicker(s string) (Ticker, error) {
+	i := strings.LastIndex(s, "_")
+	if i <= 0 || i == len(s)-1 {
+		return Ticker{}, fmt.Errorf("%w: %q", ErrInvalidTicker, s)
+	}
+	t := Ticker{Kind: s[i+1:]}
+	rest := s[:i]
+
+	if j := strings.LastIndex(rest, "_"); j > 0 && isCountryCode(rest[j+1:]) {
+		t.Symbol = rest[:j]
+		t.Country = rest[j+1:]
+	} else {
+		last := rest[len(rest)-1:]
+		if !isLowerASCII(last) || len(rest) < 2 {
+			return Ticker{}, fmt.Errorf("%w: %q has no country or market", ErrInvalidTicker, s)
+		}
+		t.Symbol = rest[:len(rest)-1]
+		t.Market = last
+	}
+
+	if err := t.Validate(); err != nil {
+		return Ticker{}, err
+	}
+	return t, nil
+}
+
+// MustParseTicker is like ParseTicker but panics on invalid input
+func MustParseTicker(s string) Ticker {
+	t, err := ParseTicker(s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// NewUSTicker builds the ticker of a US equity, e.g. AAPL_US_EQ
+func NewUSTicker(symbol string) Ticker {
+	return Ticker{Symbol: strings.ToUpper(symbol), Country: "US", Kind: "EQ"}
+}
+
+// Validate checks that the ticker components are well formed
+func (t Ticker) Validate() error {
+	if t.Symbol == "" {
+		return fmt.Errorf("%w: empty symbol", ErrInvalidTicker)
+	}
+	if t.Kind == "" {
+		return fmt.Errorf("%w: empty kind", ErrInvalidTicker)
+	}
+	if (t.Country == "") == (t.Market == "") {
+		return fmt.Errorf("%w: exactly one of country and market must be set", ErrInvalidTicker)
+	}
+	if t.Country != "" && !isCountryCode(t.Country) {
+		return fmt.Errorf("%w: invalid country %q", ErrInvalidTicker, t.Country)
+	}
+	if t.Market != "" && (len(t.Market) != 1 || !isLowerASCII(t.Market)) {
+		return fmt.Errorf("%w: invalid market %q", ErrInvalidTicker, t.Market)
+	}
+	for _, r := range t.Symbol {
+		if !(unicode.IsUpper(r) || unicode.IsDigit(r) || r == '_' || r == '.') {
+			return fmt.Errorf("%w: invalid symbol %q", ErrInvalidTicker, t.Symbol)
+		}
+	}
+	return nil
+}
+
+// String returns the Trading 212 form of the ticker
+func (t Ticker) String() string {
+	if t.Country != "" {
+		return t.Symbol + "_" + t.Country + "_" + t.Kind
+	}
+	return t.Symbol + t.Market + "_" + t.Kind
+}
+
+// ExchangeName returns a human readable name of the listing market, or an
+// empty string if it is not known
+func (t Ticker) ExchangeName() string {
+	if t.Country == "US" {
+		return "US"
+	}
+	return tickerMarkets[t.Market].name
+}
+
+// ExchangeSymbol returns the symbol as used by common market data vendors:
+// the bare symbol for US listings and SYMBOL.SUFFIX (VUSA.L) elsewhere
+func (t Ticker) ExchangeSymbol() string {
+	if market, ok := tickerMarkets[t.Market]; ok {
+		return t.Symbol + market.symbolSuffix
+	}
+	return t.Symbol
+}
+
+// ParseExchangeSymbol converts a vendor symbol such as "AAPL" or "VUSA.L"
+// into the equity ticker it most likely refers to. Symbols without a known
+// suffix are assumed to be US listings.
+func ParseExchangeSymbol(symbol string) (Ticker, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if symbol == "" {
+		return Ticker{}, fmt.Errorf("%w: empty symbol", ErrInvalidTicker)
+	}
+
+	if i := strings.LastIndex(symbol, "."); i > 0 {
+		suffix := symbol[i:]
+		for code, market := range tickerMarkets {
+			if market.symbolSuffix == suffix {
+				t := Ticker{Symbol: symbol[:i], Market: code, Kind: "EQ"}
+				return t, t.Validate()
+			}
+		}
+	}
+
+	t := NewUSTicker(symbol)
+	return t, t.Validate()
+}
+
+// ResolveSymbol finds the instrument for a vendor symbol ("AAPL", "VUSA.L")
+// or a Trading 212 ticker. When the derived ticker is not in the catalogue
+// the symbol is looked up by short name, which may yield several listings.
+func (c *InstrumentCatalog) ResolveSymbol(symbol string) ([]TradableInstrument, error) {
+	if instrument, ok := c.ByTicker(symbol); ok {
+		return []TradableInstrument{instrument}, nil
+	}
+
+	t, err := ParseExchangeSymbol(symbol)
+	if err != nil {
+		return nil, err
+	}
+	if instrument, ok := c.ByTicker(t.String()); ok {
+		return []TradableInstrument{instrument}, nil
+	}
+
+	candidates := c.ByShortName(t.Symbol)
+	if t.Market != "" {
+		var filtered []TradableInstrument
+		for _, candidate := range candidates {
+			if parsed, err := ParseTicker(candidate.Ticker); err == nil && parsed.Market == t.Market {
+				filtered = append(filtered, candidate)
+			}
+		}
+		candidates = filtered
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTicker, symbol)
+	}
+	return candidates, nil
+}
+
+// ISINOf returns the ISIN of the instrument with the given ticker
+func (c *InstrumentCatalog) ISINOf(t Ticker) (string, bool) {
+	instrument, ok := c.ByTicker(t.String())
+	if !ok || instrument.ISIN == "" {
+		return "", false
+	}
+	return instrument.ISIN, true
+}
+
+func isCountryCode(s string) bool {
+	if len(s) != 2 {
+		return false
+	}
+	for _, r := range s {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+func isLowerASCII(s string) bool {
+	for _, r := range s {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,72 @@
+package trading212
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTicker(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Ticker
+	}{
+		{"AAPL_US_EQ", Ticker{Symbol: "AAPL", Country: "US", Kind: "EQ"}},
+		{"BRK_B_US_EQ", Ticker{Symbol: "BRK_B", Country: "US", Kind: "EQ"}},
+		{"VUSAl_EQ", Ticker{Symbol: "VUSA", Market: "l", Kind: "EQ"}},
+		{"SAPd_EQ", Ticker{Symbol: "SAP", Market: "d", Kind: "EQ"}},
+	}
+	for _, tt := range tests {
+		parsed, err := ParseTicker(tt.input)
+		require.NoError(t, err, tt.input)
+		assert.Equal(t, tt.expected, parsed)
+		assert.Equal(t, tt.input, parsed.String())
+	}
+
+	for _, invalid := range []string{"", "AAPL", "AAPL_", "_EQ", "AAPL_EQ", "l_EQ", "aapl_US_EQ"} {
+		_, err := ParseTicker(invalid)
+		assert.ErrorIs(t, err, ErrInvalidTicker, invalid)
+	}
+}
+
+func TestTicker_ExchangeSymbol(t *testing.T) {
+	assert.Equal(t, "AAPL", MustParseTicker("AAPL_US_EQ").ExchangeSymbol())
+	assert.Equal(t, "VUSA.L", MustParseTicker("VUSAl_EQ").ExchangeSymbol())
+	assert.Equal(t, "London Stock Exchange", MustParseTicker("VUSAl_EQ").ExchangeName())
+
+	parsed, err := ParseExchangeSymbol("vusa.l")
+	require.NoError(t, err)
+	assert.Equal(t, "VUSAl_EQ", parsed.String())
+
+	parsed, err = ParseExchangeSymbol("AAPL")
+	require.NoError(t, err)
+	assert.Equal(t, "AAPL_US_EQ", parsed.String())
+}
+
+func TestInstrumentCatalog_ResolveSymbol(t *testing.T) {
+	catalog := NewInstrumentCatalogFrom([]TradableInstrument{
+		{Ticker: "AAPL_US_EQ", ShortName: "AAPL", ISIN: "US0378331005"},
+		{Ticker: "VUSAl_EQ", ShortName: "VUSA", ISIN: "IE00B3XXRP09"},
+		{Ticker: "VUSAa_EQ", ShortName: "VUSA", ISIN: "IE00B3XXRP09"},
+		{Ticker: "RDSAl_EQ", ShortName: "SHEL", ISIN: "GB00BP6MXD84"},
+	})
+
+	for symbol, expected := range map[string]string{"AAPL": "AAPL_US_EQ", "VUSA.L": "VUSAl_EQ", "VUSA.AS": "VUSAa_EQ", "VUSAl_EQ": "VUSAl_EQ", "SHEL.L": "RDSAl_EQ"} {
+		instruments, err := catalog.ResolveSymbol(symbol)
+		require.NoError(t, err, symbol)
+		require.Len(t, instruments, 1, symbol)
+		assert.Equal(t, expected, instruments[0].Ticker, symbol)
+	}
+
+	instruments, err := catalog.ResolveSymbol("VUSA")
+	require.NoError(t, err)
+	assert.Len(t, instruments, 2)
+
+	_, err = catalog.ResolveSymbol("MSFT")
+	assert.ErrorIs(t, err, ErrUnknownTicker)
+
+	isin, ok := catalog.ISINOf(MustParseTicker("AAPL_US_EQ"))
+	assert.True(t, ok)
+	assert.Equal(t, "US0378331005", isin)
+}